@@ -0,0 +1,121 @@
+// Package apierror defines the errors handlers return to API clients. Each
+// error carries an HTTP status and a stable machine-readable code; the
+// middleware.Errors handler renders them in one shape with the request ID.
+package apierror
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Code identifies a kind of error. Codes are part of the API and never change
+// meaning; clients should branch on them rather than on messages.
+type Code string
+
+const (
+	CodeBadRequest           Code = "bad_request"
+	CodeValidationFailed     Code = "validation_failed"
+	CodeUnauthorized         Code = "unauthorized"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeConflict             Code = "conflict"
+	CodeGone                 Code = "gone"
+	CodePayloadTooLarge      Code = "payload_too_large"
+	CodeUnsupportedMediaType Code = "unsupported_media_type"
+	CodeUnprocessable        Code = "unprocessable"
+	CodeRateLimited          Code = "rate_limited"
+	CodeInternal             Code = "internal_error"
+	CodeBadGateway           Code = "bad_gateway"
+	CodeUnavailable          Code = "unavailable"
+)
+
+// Error is an error to report to the client
+type Error struct {
+	Status  int
+	Code    Code
+	Message string
+	Details interface{} // optional structured context, e.g. the invalid fields
+}
+
+func (e *Error) Error() string {
+	return string(e.Code) + ": " + e.Message
+}
+
+// WithDetails returns a copy of the error carrying details
+func (e *Error) WithDetails(details interface{}) *Error {
+	copy := *e
+	copy.Details = details
+	return &copy
+}
+
+// New returns an error with an explicit status and code
+func New(status int, code Code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func BadRequest(message string) *Error { return New(http.StatusBadRequest, CodeBadRequest, message) }
+
+// Validation reports a request body that failed validation; details lists the fields
+func Validation(message string, details interface{}) *Error {
+	return New(http.StatusBadRequest, CodeValidationFailed, message).WithDetails(details)
+}
+
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// Forbidden is for authenticated callers who may not perform the action,
+// including callers with the wrong role
+func Forbidden(message string) *Error { return New(http.StatusForbidden, CodeForbidden, message) }
+
+func NotFound(message string) *Error { return New(http.StatusNotFound, CodeNotFound, message) }
+
+func Conflict(message string) *Error { return New(http.StatusConflict, CodeConflict, message) }
+
+func Gone(message string) *Error { return New(http.StatusGone, CodeGone, message) }
+
+func PayloadTooLarge(message string) *Error {
+	return New(http.StatusRequestEntityTooLarge, CodePayloadTooLarge, message)
+}
+
+func UnsupportedMediaType(message string) *Error {
+	return New(http.StatusUnsupportedMediaType, CodeUnsupportedMediaType, message)
+}
+
+func Unprocessable(message string) *Error {
+	return New(http.StatusUnprocessableEntity, CodeUnprocessable, message)
+}
+
+func RateLimited(message string) *Error {
+	return New(http.StatusTooManyRequests, CodeRateLimited, message)
+}
+
+// Internal is for failures that are not the client's fault. The message is
+// shown to the client, so it should not include the underlying error.
+func Internal(message string) *Error {
+	return New(http.StatusInternalServerError, CodeInternal, message)
+}
+
+func BadGateway(message string) *Error { return New(http.StatusBadGateway, CodeBadGateway, message) }
+
+func Unavailable(message string) *Error {
+	return New(http.StatusServiceUnavailable, CodeUnavailable, message)
+}
+
+// Abort stops the handler chain with err. The error is rendered by the
+// middleware.Errors handler once the chain unwinds.
+func Abort(c *gin.Context, err *Error) {
+	c.Abort()
+	c.Error(err)
+}
+
+// From returns the *Error in the context's errors, if any
+func From(c *gin.Context) (*Error, bool) {
+	for i := len(c.Errors) - 1; i >= 0; i-- {
+		if err, ok := c.Errors[i].Err.(*Error); ok {
+			return err, true
+		}
+	}
+	return nil, false
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
+	"log/slog"
+	"os"
 )
 
 func ConnectDB() *gorm.DB {
@@ -13,6 +15,33 @@ func ConnectDB() *gorm.DB {
 		panic("Failed to connect to MySQL database: " + err.Error())
 	}
 
-	fmt.Println("MySQL DB connected successfully")
+	slog.Info("MySQL DB connected successfully")
 	return db
 }
+
+// GetEnv returns the value of the environment variable or the fallback when unset
+func GetEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// DevMode reports whether APP_ENV is "development". Only then may secrets fall
+// back to built-in keys, which anyone reading the source could use.
+func DevMode() bool {
+	return GetEnv("APP_ENV", "") == "development"
+}
+
+// Secret returns the secret in the environment variable key. Without one it
+// returns devDefault in development and an error anywhere else, since a
+// built-in secret is public.
+func Secret(key, devDefault string) (string, error) {
+	if value := GetEnv(key, ""); value != "" {
+		return value, nil
+	}
+	if DevMode() {
+		return devDefault, nil
+	}
+	return "", fmt.Errorf("%s is not set (APP_ENV=development uses a built-in secret)", key)
+}
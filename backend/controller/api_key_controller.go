@@ -0,0 +1,239 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	maxAPIKeysPerUser = 20
+	// apiKeyUsageInterval limits how often a key's last-used time is written
+	apiKeyUsageInterval = time.Minute
+)
+
+var (
+	errInvalidAPIKey = errors.New("Invalid API key")
+	errExpiredAPIKey = errors.New("This API key has expired")
+	errRevokedAPIKey = errors.New("This API key has been revoked")
+
+	apiKeyUsageWrites sync.Map // key ID -> time.Time of the last write
+)
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ResolveAPIKey authenticates an API key for middleware.AuthMiddleware and
+// records when and from where it was last used
+func ResolveAPIKey(key, clientIP string) (middleware.APIKeyIdentity, error) {
+	var identity middleware.APIKeyIdentity
+	var apiKey models.APIKey
+	if err := DB.Where("hash = ?", hashAPIKey(key)).First(&apiKey).Error; err != nil {
+		return identity, errInvalidAPIKey
+	}
+	if apiKey.RevokedAt != nil {
+		return identity, errRevokedAPIKey
+	}
+	if apiKey.ExpiresAt != nil && time.Now().After(*apiKey.ExpiresAt) {
+		return identity, errExpiredAPIKey
+	}
+	// The key acts with its owner's current role
+	var user models.User
+	if err := DB.Select("id", "role").First(&user, apiKey.UserID).Error; err != nil {
+		return identity, errInvalidAPIKey
+	}
+
+	now := time.Now()
+	last, seen := apiKeyUsageWrites.Load(apiKey.ID)
+	if !seen || now.Sub(last.(time.Time)) >= apiKeyUsageInterval {
+		apiKeyUsageWrites.Store(apiKey.ID, now)
+		DB.Model(&apiKey).UpdateColumns(map[string]interface{}{"last_used_at": now, "last_used_ip": clientIP})
+	}
+
+	return middleware.APIKeyIdentity{
+		KeyID:  apiKey.ID,
+		UserID: user.ID,
+		Role:   user.Role,
+		Scopes: strings.Split(apiKey.Scopes, ","),
+	}, nil
+}
+
+func validAPIKeyScope(scope string) bool {
+	for _, s := range models.APIKeyScopes {
+		if s.Name == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// visibleAPIKeys limits a query to keys the user may see: their own, plus every
+// key of their organisation if they own it
+func visibleAPIKeys(userID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if member, ok := organisationMembership(userID); ok && member.Role == models.OrgRoleOwner {
+			return db.Where("user_id = ? OR organisation_id = ?", userID, member.OrganisationID)
+		}
+		return db.Where("user_id = ?", userID)
+	}
+}
+
+// GetAPIKeyScopes - Lists the scopes an API key can be given
+func GetAPIKeyScopes(c *gin.Context) {
+	c.JSON(http.StatusOK, apiKeyScopesResponse{Scopes: models.APIKeyScopes})
+}
+
+type apiKeyScopesResponse struct {
+	Scopes []models.APIKeyScope `json:"scopes"`
+}
+
+type createAPIKeyInput struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days"` // 0 = never
+}
+
+// CreateAPIKey - Company creates an API key; the key is only shown in this response
+func CreateAPIKey(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input createAPIKeyInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	scopes := []string{}
+	seen := map[string]bool{}
+	for _, scope := range input.Scopes {
+		if !validAPIKeyScope(scope) {
+			apierror.Abort(c, apierror.BadRequest("Unknown scope: "+scope))
+			return
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if input.ExpiresInDays < 0 || input.ExpiresInDays > 365 {
+		apierror.Abort(c, apierror.BadRequest("expires_in_days must be between 0 and 365"))
+		return
+	}
+
+	var count int64
+	DB.Model(&models.APIKey{}).Where("user_id = ? AND revoked_at IS NULL", userID).Count(&count)
+	if count >= maxAPIKeysPerUser {
+		apierror.Abort(c, apierror.BadRequest("Revoke an unused key before creating another"))
+		return
+	}
+
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to create API key"))
+		return
+	}
+	key := middleware.APIKeyPrefix + hex.EncodeToString(secret)
+
+	apiKey := models.APIKey{
+		UserID:         userID,
+		OrganisationID: organisationIDFor(userID),
+		Name:           input.Name,
+		Prefix:         key[:len(middleware.APIKeyPrefix)+8],
+		Hash:           hashAPIKey(key),
+		Scopes:         strings.Join(scopes, ","),
+	}
+	if input.ExpiresInDays > 0 {
+		expires := time.Now().AddDate(0, 0, input.ExpiresInDays)
+		apiKey.ExpiresAt = &expires
+	}
+	if err := DB.Create(&apiKey).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to create API key"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, createAPIKeyResponse{
+		Message: "API key created. Store it now; it is not shown again.",
+		APIKey:  apiKey,
+		Key:     key,
+	})
+}
+
+// createAPIKeyResponse carries the plaintext key, which is only ever shown once
+type createAPIKeyResponse struct {
+	Message string        `json:"message"`
+	APIKey  models.APIKey `json:"api_key"`
+	Key     string        `json:"key"`
+}
+
+type apiKeysResponse struct {
+	APIKeys []apiKeyEntry `json:"api_keys"`
+}
+
+type apiKeyEntry struct {
+	APIKey    models.APIKey `json:"api_key"`
+	OwnerName string        `json:"owner_name"`
+	Active    bool          `json:"active"`
+}
+
+// GetAPIKeys - Company lists its API keys; organisation owners see every key in the organisation
+func GetAPIKeys(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var keys []models.APIKey
+	if err := DB.Scopes(visibleAPIKeys(userID)).Order("created_at DESC").Find(&keys).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch API keys"))
+		return
+	}
+
+	ownerIDs := make([]uint, 0, len(keys))
+	for _, key := range keys {
+		ownerIDs = append(ownerIDs, key.UserID)
+	}
+	var owners []models.User
+	DB.Select("id", "name").Where("id IN ?", ownerIDs).Find(&owners)
+	names := map[uint]string{}
+	for _, owner := range owners {
+		names[owner.ID] = owner.Name
+	}
+
+	entries := make([]apiKeyEntry, 0, len(keys))
+	for _, key := range keys {
+		entries = append(entries, apiKeyEntry{
+			APIKey:    key,
+			OwnerName: names[key.UserID],
+			Active:    key.RevokedAt == nil && (key.ExpiresAt == nil || key.ExpiresAt.After(time.Now())),
+		})
+	}
+	c.JSON(http.StatusOK, apiKeysResponse{APIKeys: entries})
+}
+
+// RevokeAPIKey - Company revokes an API key so it stops working immediately
+func RevokeAPIKey(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var apiKey models.APIKey
+	if err := DB.Scopes(visibleAPIKeys(userID)).Where("id = ?", c.Param("id")).First(&apiKey).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("API key not found"))
+		return
+	}
+	if apiKey.RevokedAt != nil {
+		c.JSON(http.StatusOK, models.MessageResponse{Message: "API key already revoked"})
+		return
+	}
+	if err := DB.Model(&apiKey).Update("revoked_at", time.Now()).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to revoke API key"))
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "API key revoked"})
+}
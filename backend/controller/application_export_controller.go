@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/models"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportJobApplications - Company downloads a job's applications as CSV
+func ExportJobApplications(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var job models.JobListing
+	if err := DB.Scopes(ownedByCompany("job_listings", userID)).Where("id = ?", c.Param("id")).First(&job).Error; err != nil {
+		apierror.Abort(c, apierror.Forbidden("Unauthorized"))
+		return
+	}
+
+	query := DB.Where("job_listing_id = ?", job.ID).Preload("User")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var applications []models.JobApplication
+	if err := query.Order("applied_at ASC").Find(&applications).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch applications"))
+		return
+	}
+	fits := applicationFits(applications)
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="job-%d-applications.csv"`, job.ID))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"application_id", "name", "email", "phone", "university", "status", "applied_at", "stage_changed_at", "fit_score", "resume", "resume_file_id"})
+	for _, app := range applications {
+		var stageChanged, fitScore, resumeFile string
+		if app.StageChangedAt != nil {
+			stageChanged = app.StageChangedAt.UTC().Format(time.RFC3339)
+		}
+		if fit := fits[app.ID]; fit != nil {
+			fitScore = strconv.FormatFloat(fit.Score, 'f', 1, 64)
+		}
+		if app.ResumeFileID != nil {
+			resumeFile = strconv.FormatUint(uint64(*app.ResumeFileID), 10)
+		}
+		w.Write([]string{
+			strconv.FormatUint(uint64(app.ID), 10),
+			csvSafe(app.User.Name),
+			csvSafe(app.User.Email),
+			csvSafe(app.User.Phone),
+			csvSafe(app.User.University),
+			csvSafe(app.Status),
+			app.AppliedAt.UTC().Format(time.RFC3339),
+			stageChanged,
+			fitScore,
+			csvSafe(app.Resume),
+			resumeFile,
+		})
+	}
+	w.Flush()
+}
+
+// csvSafe stops spreadsheet apps from treating applicant-supplied text as a formula
+func csvSafe(s string) string {
+	if s != "" && (s[0] == '=' || s[0] == '+' || s[0] == '-' || s[0] == '@') {
+		return "'" + s
+	}
+	return s
+}
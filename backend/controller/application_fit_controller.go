@@ -0,0 +1,273 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/models"
+	"SkillBridge/queue"
+	"SkillBridge/resume"
+	"SkillBridge/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// applicantResume returns the resume an application was submitted with, as a
+// document plus its plain text. Attached versions are used as-is; uploaded
+// files are read and parsed. Applications without a resume return an empty document.
+func applicantResume(ctx context.Context, app models.JobApplication) (resume.Document, string) {
+	if app.ResumeVersionID != nil {
+		var version models.ResumeVersion
+		if err := DB.First(&version, *app.ResumeVersionID).Error; err == nil {
+			var doc resume.Document
+			if json.Unmarshal(version.Content, &doc) == nil {
+				return doc, documentText(doc)
+			}
+		}
+	}
+	if app.ResumeFileID != nil && ResumeStore != nil {
+		var file models.ResumeFile
+		if err := DB.Unscoped().First(&file, *app.ResumeFileID).Error; err != nil {
+			return resume.Document{}, ""
+		}
+		reader, err := ResumeStore.Get(ctx, file.StorageKey)
+		if err != nil {
+			slog.Warn("failed to read resume for fit scoring", "key", file.StorageKey, "error", err)
+			return resume.Document{}, ""
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(io.LimitReader(reader, maxResumeBytes()+1))
+		if err != nil {
+			return resume.Document{}, ""
+		}
+		text, err := resume.ExtractText(file.ContentType, data)
+		if err != nil {
+			return resume.Document{}, ""
+		}
+		return resume.Parse(text), text
+	}
+	return resume.Document{}, ""
+}
+
+// documentText flattens a resume document into searchable text
+func documentText(doc resume.Document) string {
+	parts := []string{doc.Title, doc.Bio, strings.Join(doc.Skills, ", ")}
+	for _, e := range doc.Experience {
+		parts = append(parts, e.Position, e.Company, e.Summary, strings.Join(e.Highlights, " "))
+	}
+	for _, e := range doc.Education {
+		parts = append(parts, e.Degree, e.Field, e.Institution)
+	}
+	for _, p := range doc.Projects {
+		parts = append(parts, p.Name, p.Description, strings.Join(p.Technologies, ", "), strings.Join(p.Highlights, " "))
+	}
+	for _, cert := range doc.Certifications {
+		parts = append(parts, cert.Name)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// jsonStrings decodes a JSON array column such as JobListing.Skills
+func jsonStrings(raw json.RawMessage) []string {
+	var values []string
+	json.Unmarshal(raw, &values)
+	return values
+}
+
+// computeApplicationFit scores an application against its job and stores the result
+func computeApplicationFit(ctx context.Context, app models.JobApplication, job models.JobListing, user models.User) (models.ApplicationFit, error) {
+	doc, text := applicantResume(ctx, app)
+
+	skills := append(utils.ParseSkills(user.Skills), doc.Skills...)
+	var projectSkills [][]string
+	var submissions []models.Submission
+	DB.Preload("Project").Where("student_id = ? AND status = ?", user.ID, "accepted").Find(&submissions)
+	for _, submission := range submissions {
+		projectSkills = append(projectSkills, utils.ParseSkills(submission.Project.Skills))
+	}
+
+	now := time.Now()
+	years := resume.ExperienceYears(doc, now)
+	result := utils.ComputeFit(utils.FitJob{
+		Skills:          jsonStrings(job.Skills),
+		Requirements:    jsonStrings(job.Requirements),
+		ExperienceYears: job.Experience,
+	}, utils.FitCandidate{
+		Skills:          skills,
+		ProjectSkills:   projectSkills,
+		ResumeText:      strings.Join([]string{user.Bio, user.Major, text}, "\n"),
+		ExperienceYears: years,
+	})
+
+	matched, _ := json.Marshal(result.MatchedSkills)
+	missing, _ := json.Marshal(result.MissingSkills)
+	verified, _ := json.Marshal(result.VerifiedSkills)
+	fit := models.ApplicationFit{
+		JobApplicationID: app.ID,
+		JobListingID:     job.ID,
+		UserID:           user.ID,
+		Score:            result.Score,
+		SkillScore:       result.SkillScore,
+		RequirementScore: result.RequirementScore,
+		ExperienceScore:  result.ExperienceScore,
+		ProjectScore:     result.ProjectScore,
+		ExperienceYears:  float64(int(years*10)) / 10,
+		MatchedSkills:    matched,
+		MissingSkills:    missing,
+		VerifiedSkills:   verified,
+		ComputedAt:       now,
+	}
+	err := DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job_application_id"}},
+		UpdateAll: true,
+	}).Create(&fit).Error
+	return fit, err
+}
+
+// jobComputeFit scores one application in the background
+const jobComputeFit = "compute_application_fit"
+
+type computeFitPayload struct {
+	ApplicationID uint `json:"application_id"`
+}
+
+// computeFit is the jobComputeFit handler
+func computeFit(ctx context.Context, payload json.RawMessage) error {
+	var p computeFitPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return queue.Permanent(err)
+	}
+	var app models.JobApplication
+	err := DB.WithContext(ctx).Preload("User").Preload("JobListing").First(&app, p.ApplicationID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil // withdrawn or deleted since
+	}
+	if err != nil {
+		return err
+	}
+	_, err = computeApplicationFit(ctx, app, app.JobListing, app.User)
+	return err
+}
+
+// enqueueFits queues a fresh score for each application. Scores asked for by
+// readers are de-duplicated; after a change, pass fresh so the score is
+// computed again even if a computation is already running on the old data.
+func enqueueFits(db *gorm.DB, applicationIDs []uint, fresh bool) error {
+	for _, id := range applicationIDs {
+		var opts []queue.Option
+		if !fresh {
+			opts = append(opts, queue.Unique(fmt.Sprintf("fit:%d", id)))
+		}
+		_, err := queue.Enqueue(db, jobComputeFit, computeFitPayload{ApplicationID: id}, opts...)
+		if err != nil && !errors.Is(err, queue.ErrDuplicate) {
+			return err
+		}
+	}
+	return nil
+}
+
+// applicationFits returns the stored fit scores for the given applications and
+// queues a computation for any that are missing or stale. Scoring reads resumes,
+// so it never happens in the request.
+func applicationFits(applications []models.JobApplication) map[uint]*models.ApplicationFit {
+	fits := map[uint]*models.ApplicationFit{}
+	if len(applications) == 0 {
+		return fits
+	}
+	ids := make([]uint, len(applications))
+	for i, app := range applications {
+		ids[i] = app.ID
+	}
+	var stored []models.ApplicationFit
+	if err := DB.Where("job_application_id IN ?", ids).Find(&stored).Error; err != nil {
+		slog.Error("failed to load fit scores", "error", err)
+		return fits
+	}
+	for i := range stored {
+		fits[stored[i].JobApplicationID] = &stored[i]
+	}
+
+	var pending []uint
+	for _, id := range ids {
+		if fit := fits[id]; fit == nil || fit.Stale {
+			pending = append(pending, id)
+		}
+	}
+	if err := enqueueFits(DB, pending, false); err != nil {
+		slog.Error("failed to queue fit scores", "error", err)
+	}
+	return fits
+}
+
+// invalidateFits marks the fit scores of the applications matched by where as
+// stale and queues fresh ones. Call it whenever an input to the score changes.
+func invalidateFits(where string, args ...interface{}) error {
+	var ids []uint
+	if err := DB.Model(&models.JobApplication{}).Where(where, args...).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ApplicationFit{}).Where("job_application_id IN ?", ids).Update("stale", true).Error; err != nil {
+			return err
+		}
+		return enqueueFits(tx, ids, true)
+	})
+}
+
+// invalidateStudentFits refreshes a student's fit scores, e.g. after their
+// profile or completed projects change
+func invalidateStudentFits(studentID uint) {
+	if err := invalidateFits("user_id = ?", studentID); err != nil {
+		slog.Error("failed to reset fit scores", "user_id", studentID, "error", err)
+	}
+}
+
+// invalidateJobFits refreshes the fit scores of a job's applicants after the job changes
+func invalidateJobFits(jobID uint) {
+	if err := invalidateFits("job_listing_id = ?", jobID); err != nil {
+		slog.Error("failed to reset fit scores", "job_id", jobID, "error", err)
+	}
+}
+
+// RecomputeJobFits - Company queues a fresh fit score for every applicant to a job
+func RecomputeJobFits(c *gin.Context) {
+	companyID := c.GetUint("userID")
+
+	var job models.JobListing
+	if err := DB.Scopes(ownedByCompany("job_listings", companyID)).Where("id = ?", c.Param("id")).First(&job).Error; err != nil {
+		apierror.Abort(c, apierror.Forbidden("Unauthorized"))
+		return
+	}
+
+	var count int64
+	if err := DB.Model(&models.JobApplication{}).Where("job_listing_id = ?", job.ID).Count(&count).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch applications"))
+		return
+	}
+	if err := invalidateFits("job_listing_id = ?", job.ID); err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to queue fit scores"))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, refreshFitsResponse{
+		Message: "Fit scores are being recomputed",
+		Queued:  count,
+	})
+}
+
+type refreshFitsResponse struct {
+	Message string `json:"message"`
+	Queued  int64  `json:"queued"`
+}
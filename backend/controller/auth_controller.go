@@ -1,12 +1,15 @@
 package controller
 
 import (
+	"SkillBridge/apierror"
 	"SkillBridge/middleware"
 	"SkillBridge/models"
+	"SkillBridge/ratelimit"
 	"SkillBridge/utils"
 	"crypto/rand"
 	"encoding/base64"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,33 +23,47 @@ func InitAuth(db *gorm.DB) {
 	DB = db
 }
 
+// tokenResponse is returned by SignUp and RefreshToken
+type tokenResponse struct {
+	Message string `json:"message"`
+	Token   string `json:"token"`
+}
+
+type signUpInput struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role" binding:"required,oneof=student company guide"`
+}
+
 func SignUp(c *gin.Context) {
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+	var input signUpInput
+	if !bindJSON(c, &input) {
 		return
 	}
+	user := models.User{Name: input.Name, Email: input.Email, Password: input.Password, Role: input.Role}
 
 	// Check existing user
 	var existUser models.User
 	if err := DB.Where("email = ?", user.Email).First(&existUser).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email already registered"})
+		apierror.Abort(c, apierror.BadRequest("Email already registered"))
 		return
 	}
 
 	// Hash password (REMOVED LOGGING)
 	hashedPassword, err := utils.HashPassword(user.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
+		apierror.Abort(c, apierror.Internal("Registration failed"))
 		return
 	}
 	user.Password = hashedPassword
 
 	// Create user
 	if err := DB.Create(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
+		apierror.Abort(c, apierror.Internal("Registration failed"))
 		return
 	}
+	signupsTotal.Inc(user.Role, "password")
 
 	// Generate token with role claim (FIXED)
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
@@ -58,24 +75,45 @@ func SignUp(c *gin.Context) {
 	// Use consistent secret (FIXED)
 	tokenString, err := token.SignedString(middleware.JWT_SECRET)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
+		apierror.Abort(c, apierror.Internal("Registration failed"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "User created successfully",
-		"token":   tokenString,
-	})
+	c.JSON(http.StatusOK, tokenResponse{Message: "User created successfully", Token: tokenString})
+}
+
+// loginLockout locks an account after five failed logins in a row, for 30
+// seconds at first and doubling with each further failure up to 30 minutes
+var loginLockout = &ratelimit.Lockout{
+	Prefix:    "login:",
+	Threshold: 5,
+	Window:    time.Hour,
+	Base:      30 * time.Second,
+	Max:       30 * time.Minute,
+}
+
+type loginInput struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type loginResponse struct {
+	Token string `json:"token"`
 }
 
 func Login(c *gin.Context) {
-	var credentials struct {
-		Email    string `json:"email" binding:"required"`
-		Password string `json:"password" binding:"required"`
+	var credentials loginInput
+	if !bindJSON(c, &credentials) {
+		return
 	}
 
-	if err := c.ShouldBindJSON(&credentials); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid credentials"})
+	// Failures count against the address whether or not an account exists, so
+	// lockouts don't reveal which emails are registered
+	account := strings.ToLower(strings.TrimSpace(credentials.Email))
+	if wait, err := loginLockout.Locked(c.Request.Context(), account); err != nil {
+		middleware.Log(c).Error("failed to check login lockout", "error", err)
+	} else if wait > 0 {
+		middleware.AbortRateLimited(c, wait, "Too many failed login attempts, try again later")
 		return
 	}
 
@@ -84,14 +122,19 @@ func Login(c *gin.Context) {
 	errMsg := "Incorrect email or password"
 
 	if err := DB.Where("email = ?", credentials.Email).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": errMsg}) // Changed to 401
+		recordLoginFailure(c, account)
+		apierror.Abort(c, apierror.Unauthorized(errMsg)) // Changed to 401
 		return
 	}
 
 	if !utils.CheckPassword(user.Password, credentials.Password) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": errMsg}) // Consistent error
+		recordLoginFailure(c, account)
+		apierror.Abort(c, apierror.Unauthorized(errMsg)) // Consistent error
 		return
 	}
+	if err := loginLockout.Reset(c.Request.Context(), account); err != nil {
+		middleware.Log(c).Error("failed to reset login lockout", "error", err)
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id": user.ID,
@@ -101,79 +144,122 @@ func Login(c *gin.Context) {
 
 	tokenStr, err := token.SignedString(middleware.JWT_SECRET)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Login failed"})
+		apierror.Abort(c, apierror.Internal("Login failed"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": tokenStr})
+	c.JSON(http.StatusOK, loginResponse{Token: tokenStr})
+}
+
+func recordLoginFailure(c *gin.Context, account string) {
+	wait, err := loginLockout.Fail(c.Request.Context(), account)
+	if err != nil {
+		middleware.Log(c).Error("failed to record failed login", "error", err)
+	} else if wait > 0 {
+		middleware.Log(c).Warn("account locked after failed logins", "lock", wait)
+	}
 }
 
 func GetProfile(c *gin.Context) {
 	userID, exits := c.Get("userID")
 	if !exits {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User already exists"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	var user models.User
 	if err := DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		apierror.Abort(c, apierror.NotFound("User not found"))
 		return
 	}
 
 	// successfully profile got.
-	c.JSON(http.StatusOK, gin.H{
-		"id":            user.ID,
-		"name":          user.Name,
-		"email":         user.Email,
-		"role":          user.Role,
-		"picture":       user.Picture,
-		"bio":           user.Bio,
-		"github_url":    user.GithubURL,
-		"linkedin":      user.LinkedIn,
-		"phone":         user.Phone,
-		"university":    user.University,
-		"major":         user.Major,
-		"year":          user.Year,
-		"company_name":  user.CompanyName,
-		"position":      user.Position,
-		"portfolio_url": user.PortfolioURL,
-		"skills":        user.Skills,
-	})
+	c.JSON(http.StatusOK, newProfileResponse(user))
+}
+
+// profileResponse is the caller's own profile, contact details included
+type profileResponse struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	Picture      string `json:"picture"`
+	Bio          string `json:"bio"`
+	GithubURL    string `json:"github_url"`
+	LinkedIn     string `json:"linkedin"`
+	Phone        string `json:"phone"`
+	University   string `json:"university"`
+	Major        string `json:"major"`
+	Year         string `json:"year"`
+	CompanyName  string `json:"company_name"`
+	Position     string `json:"position"`
+	PortfolioURL string `json:"portfolio_url"`
+	Skills       string `json:"skills"`
 }
 
+func newProfileResponse(user models.User) profileResponse {
+	return profileResponse{
+		ID:           user.ID,
+		Name:         user.Name,
+		Email:        user.Email,
+		Role:         user.Role,
+		Picture:      user.Picture,
+		Bio:          user.Bio,
+		GithubURL:    user.GithubURL,
+		LinkedIn:     user.LinkedIn,
+		Phone:        user.Phone,
+		University:   user.University,
+		Major:        user.Major,
+		Year:         user.Year,
+		CompanyName:  user.CompanyName,
+		Position:     user.Position,
+		PortfolioURL: user.PortfolioURL,
+		Skills:       user.Skills,
+	}
+}
+
+type updateProfileResponse struct {
+	Message string          `json:"message"`
+	User    profileResponse `json:"user"`
+}
+
+type updateProfileInput struct {
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	Bio          string `json:"bio"`
+	GithubURL    string `json:"github_url" binding:"omitempty,httpurl"`
+	LinkedIn     string `json:"linkedin" binding:"omitempty,httpurl"`
+	Phone        string `json:"phone"`
+	University   string `json:"university"`
+	Major        string `json:"major"`
+	Year         string `json:"year"`
+	CompanyName  string `json:"company_name"`
+	Position     string `json:"position"`
+	PortfolioURL string `json:"portfolio_url" binding:"omitempty,httpurl"`
+	Skills       string `json:"skills"`
+}
+
+// profileURLFields are the profile columns holding links, which must be
+// http(s) URLs wherever they are set
+var profileURLFields = []string{"github_url", "linkedin", "portfolio_url"}
+
 func UpdateProfile(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		apierror.Abort(c, apierror.Unauthorized("User ID not found"))
 		return
 	}
 
-	var input struct {
-		Name         string `json:"name"`
-		Email        string `json:"email"`
-		Role         string `json:"role"`
-		Bio          string `json:"bio"`
-		GithubURL    string `json:"github_url"`
-		LinkedIn     string `json:"linkedin"`
-		Phone        string `json:"phone"`
-		University   string `json:"university"`
-		Major        string `json:"major"`
-		Year         string `json:"year"`
-		CompanyName  string `json:"company_name"`
-		Position     string `json:"position"`
-		PortfolioURL string `json:"portfolio_url"`
-		Skills       string `json:"skills"`
-	}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+	var input updateProfileInput
+	if !bindJSON(c, &input) {
 		return
 	}
 
 	// Check if the new email already exists for another user (only if email is being changed)
 	var existing models.User
 	if err := DB.Where("email = ? AND id != ?", input.Email, userID).First(&existing).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email is already in use"})
+		apierror.Abort(c, apierror.BadRequest("Email is already in use"))
 		return
 	}
 
@@ -196,55 +282,47 @@ func UpdateProfile(c *gin.Context) {
 	}
 
 	if err := DB.Model(&models.User{}).Where("id = ?", userID).Updates(updateData).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile: " + err.Error()})
+		apierror.Abort(c, apierror.Internal("Failed to update profile: "+err.Error()))
 		return
 	}
+	// Skills, bio and major feed the student's job fit scores
+	invalidateStudentFits(userID.(uint))
 
 	// Get updated user data
 	var updatedUser models.User
 	if err := DB.First(&updatedUser, userID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve updated profile"})
+		apierror.Abort(c, apierror.Internal("Failed to retrieve updated profile"))
 		return
 	}
 
 	// Return success with updated profile data
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Profile updated successfully",
-		"user": gin.H{
-			"id":            updatedUser.ID,
-			"name":          updatedUser.Name,
-			"email":         updatedUser.Email,
-			"role":          updatedUser.Role,
-			"picture":       updatedUser.Picture,
-			"bio":           updatedUser.Bio,
-			"github_url":    updatedUser.GithubURL,
-			"linkedin":      updatedUser.LinkedIn,
-			"phone":         updatedUser.Phone,
-			"university":    updatedUser.University,
-			"major":         updatedUser.Major,
-			"year":          updatedUser.Year,
-			"company_name":  updatedUser.CompanyName,
-			"position":      updatedUser.Position,
-			"portfolio_url": updatedUser.PortfolioURL,
-			"skills":        updatedUser.Skills,
-		},
+	c.JSON(http.StatusOK, updateProfileResponse{
+		Message: "Profile updated successfully",
+		User:    newProfileResponse(updatedUser),
 	})
 }
 
+type setGithubTokenInput struct {
+	GithubToken string `json:"github_token" binding:"required"`
+}
+
+// githubTokenResponse is returned when a GitHub token is connected or removed
+type githubTokenResponse struct {
+	Message        string `json:"message"`
+	GithubUser     string `json:"github_user,omitempty"` // the token's GitHub username, when connecting
+	CanCreateRepos bool   `json:"can_create_repos"`
+}
+
 // SetGithubToken allows users to securely set their GitHub token for repository creation
 func SetGithubToken(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		apierror.Abort(c, apierror.Unauthorized("User ID not found"))
 		return
 	}
 
-	var input struct {
-		GithubToken string `json:"github_token" binding:"required"`
-	}
-
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data: " + err.Error()})
+	var input setGithubTokenInput
+	if !bindJSON(c, &input) {
 		return
 	}
 
@@ -252,20 +330,22 @@ func SetGithubToken(c *gin.Context) {
 	githubService := utils.NewGitHubService(input.GithubToken)
 	userInfo, err := githubService.GetUserInfo()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid GitHub token or insufficient permissions"})
+		apierror.Abort(c, apierror.BadRequest("Invalid GitHub token or insufficient permissions"))
 		return
 	}
 
 	// Update user's GitHub token
 	if err := DB.Model(&models.User{}).Where("id = ?", userID).Update("github_token", input.GithubToken).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save GitHub token"})
+		apierror.Abort(c, apierror.Internal("Failed to save GitHub token"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":          "GitHub token saved successfully",
-		"github_user":      userInfo["login"], // Return GitHub username for confirmation
-		"can_create_repos": true,
+	// Return GitHub username for confirmation
+	login, _ := userInfo["login"].(string)
+	c.JSON(http.StatusOK, githubTokenResponse{
+		Message:        "GitHub token saved successfully",
+		GithubUser:     login,
+		CanCreateRepos: true,
 	})
 }
 
@@ -273,38 +353,54 @@ func SetGithubToken(c *gin.Context) {
 func RemoveGithubToken(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		apierror.Abort(c, apierror.Unauthorized("User ID not found"))
 		return
 	}
 
 	// Remove GitHub token
 	if err := DB.Model(&models.User{}).Where("id = ?", userID).Update("github_token", "").Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove GitHub token"})
+		apierror.Abort(c, apierror.Internal("Failed to remove GitHub token"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":          "GitHub token removed successfully",
-		"can_create_repos": false,
-	})
+	c.JSON(http.StatusOK, githubTokenResponse{Message: "GitHub token removed successfully"})
+}
+
+type googleOAuthInput struct {
+	GoogleToken string `json:"google_token" binding:"required"`
+	Role        string `json:"role"`
+}
+
+// oauthResponse logs in with a summary of the account
+type oauthResponse struct {
+	Message string    `json:"message"`
+	Token   string    `json:"token"`
+	User    oauthUser `json:"user"`
+}
+
+type oauthUser struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Role    string `json:"role"`
+	Picture string `json:"picture"`
+}
+
+func newOAuthUser(user models.User) oauthUser {
+	return oauthUser{ID: user.ID, Name: user.Name, Email: user.Email, Role: user.Role, Picture: user.Picture}
 }
 
 // GoogleOAuth handles Google OAuth authentication
 func GoogleOAuth(c *gin.Context) {
-	var input struct {
-		GoogleToken string `json:"google_token" binding:"required"`
-		Role        string `json:"role"`
-	}
-
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+	var input googleOAuthInput
+	if !bindJSON(c, &input) {
 		return
 	}
 
 	// Parse the Google JWT token to extract user info
 	userInfo, err := parseGoogleJWT(input.GoogleToken)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Google token"})
+		apierror.Abort(c, apierror.BadRequest("Invalid Google token"))
 		return
 	}
 
@@ -325,21 +421,11 @@ func GoogleOAuth(c *gin.Context) {
 
 		tokenString, err := token.SignedString(middleware.JWT_SECRET)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Login failed"})
+			apierror.Abort(c, apierror.Internal("Login failed"))
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message": "Login successful",
-			"token":   tokenString,
-			"user": gin.H{
-				"id":      existingUser.ID,
-				"name":    existingUser.Name,
-				"email":   existingUser.Email,
-				"role":    existingUser.Role,
-				"picture": existingUser.Picture,
-			},
-		})
+		c.JSON(http.StatusOK, oauthResponse{Message: "Login successful", Token: tokenString, User: newOAuthUser(existingUser)})
 		return
 	}
 
@@ -352,7 +438,7 @@ func GoogleOAuth(c *gin.Context) {
 	randomPassword := generateRandomPassword()
 	hashedPassword, err := utils.HashPassword(randomPassword)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
+		apierror.Abort(c, apierror.Internal("Registration failed"))
 		return
 	}
 
@@ -365,9 +451,10 @@ func GoogleOAuth(c *gin.Context) {
 	}
 
 	if err := DB.Create(&newUser).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
+		apierror.Abort(c, apierror.Internal("Registration failed"))
 		return
 	}
+	signupsTotal.Inc(newUser.Role, "google")
 
 	// Generate JWT token for new user
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
@@ -378,21 +465,11 @@ func GoogleOAuth(c *gin.Context) {
 
 	tokenString, err := token.SignedString(middleware.JWT_SECRET)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Registration failed"})
+		apierror.Abort(c, apierror.Internal("Registration failed"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "User created successfully",
-		"token":   tokenString,
-		"user": gin.H{
-			"id":      newUser.ID,
-			"name":    newUser.Name,
-			"email":   newUser.Email,
-			"role":    newUser.Role,
-			"picture": newUser.Picture,
-		},
-	})
+	c.JSON(http.StatusOK, oauthResponse{Message: "User created successfully", Token: tokenString, User: newOAuthUser(newUser)})
 }
 
 // Helper functions for Google OAuth
@@ -439,13 +516,13 @@ func generateRandomPassword() string {
 func RefreshToken(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		apierror.Abort(c, apierror.Unauthorized("User ID not found"))
 		return
 	}
 
 	role, exists := c.Get("role")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+		apierror.Abort(c, apierror.Unauthorized("User role not found"))
 		return
 	}
 
@@ -458,12 +535,9 @@ func RefreshToken(c *gin.Context) {
 
 	tokenString, err := token.SignedString(middleware.JWT_SECRET)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		apierror.Abort(c, apierror.Internal("Failed to generate token"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Token refreshed successfully",
-		"token":   tokenString,
-	})
+	c.JSON(http.StatusOK, tokenResponse{Message: "Token refreshed successfully", Token: tokenString})
 }
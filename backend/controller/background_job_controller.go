@@ -0,0 +1,311 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/models"
+	"SkillBridge/notify"
+	"SkillBridge/queue"
+	"SkillBridge/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Background job types
+const (
+	jobExpireListings      = "expire_listings"
+	jobInterviewReminders  = "send_interview_reminders"
+	jobSavedSearchAlerts   = "process_saved_searches"
+	jobNotifyUsers         = "notify_users"
+	jobRefreshGithubStats  = "refresh_github_stats"
+	jobCleanupFinishedJobs = "cleanup_jobs"
+)
+
+const (
+	// githubStatsMaxAge is how old cached portfolio GitHub stats may get before a refresh is queued
+	githubStatsMaxAge = 6 * time.Hour
+	// finishedJobRetention is how long succeeded jobs are kept for inspection
+	finishedJobRetention = 7 * 24 * time.Hour
+)
+
+// notifyUsersPayload sends the same notification to many users
+type notifyUsersPayload struct {
+	UserIDs []uint         `json:"user_ids"`
+	Message notify.Message `json:"message"`
+}
+
+type githubStatsPayload struct {
+	UserID uint `json:"user_id"`
+}
+
+// RegisterBackgroundJobs registers every job handler and recurring schedule.
+// It must be called before the queue is started.
+func RegisterBackgroundJobs() error {
+	queue.Register(jobExpireListings, queue.Definition{Handler: func(ctx context.Context, _ json.RawMessage) error {
+		return ExpireListings()
+	}})
+	queue.Register(jobInterviewReminders, queue.Definition{Handler: func(ctx context.Context, _ json.RawMessage) error {
+		return SendInterviewReminders()
+	}})
+	queue.Register(jobSavedSearchAlerts, queue.Definition{Handler: func(ctx context.Context, _ json.RawMessage) error {
+		return ProcessSavedSearches()
+	}, Timeout: 10 * time.Minute})
+	queue.Register(jobNotifyUsers, queue.Definition{Handler: notifyUsers})
+	queue.Register(jobRefreshGithubStats, queue.Definition{Handler: refreshGithubStats, MaxAttempts: 3})
+	queue.Register(jobCleanupFinishedJobs, queue.Definition{Handler: func(ctx context.Context, _ json.RawMessage) error {
+		return DB.Where("status = ? AND finished_at < ?", models.JobSucceeded, time.Now().Add(-finishedJobRetention)).
+			Delete(&models.BackgroundJob{}).Error
+	}})
+	queue.Register(jobComputeFit, queue.Definition{Handler: computeFit, MaxAttempts: 3})
+	queue.Register(jobDeliverWebhook, queue.Definition{Handler: deliverWebhook, MaxAttempts: webhookMaxAttempts, Timeout: 30 * time.Second})
+	if err := notify.RegisterJobs(); err != nil {
+		return err
+	}
+
+	schedules := []struct{ name, spec, jobType string }{
+		{"expire-listings", "*/15 * * * *", jobExpireListings},
+		// Interview reminders go out a day ahead; check every few minutes
+		{"interview-reminders", "*/5 * * * *", jobInterviewReminders},
+		{"saved-search-alerts", "*/10 * * * *", jobSavedSearchAlerts},
+		{"cleanup-jobs", "30 3 * * *", jobCleanupFinishedJobs},
+	}
+	for _, s := range schedules {
+		if err := queue.Every(s.name, s.spec, s.jobType, nil); err != nil {
+			return fmt.Errorf("scheduling %s: %w", s.name, err)
+		}
+	}
+	return nil
+}
+
+// enqueueNotification queues a notification to many users as one job. Pass a
+// transaction as db to send it only if the surrounding changes commit.
+func enqueueNotification(db *gorm.DB, userIDs []uint, message notify.Message) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	_, err := queue.Enqueue(db, jobNotifyUsers, notifyUsersPayload{UserIDs: userIDs, Message: message})
+	return err
+}
+
+// notifyUsers delivers a fan-out notification to each user. Failures are logged
+// rather than retried, so users who were reached are not notified twice.
+func notifyUsers(ctx context.Context, payload json.RawMessage) error {
+	var p notifyUsersPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return queue.Permanent(err)
+	}
+	for _, userID := range p.UserIDs {
+		if err := notify.Send(DB.WithContext(ctx), userID, p.Message); err != nil {
+			slog.Error("failed to send notification", "error", err)
+		}
+	}
+	return nil
+}
+
+// ExpireListings closes job listings and projects whose deadline has passed
+// and lets their owners know. It runs as a recurring background job.
+func ExpireListings() error {
+	now := time.Now()
+
+	var jobs []models.JobListing
+	if err := DB.Where("is_active = ? AND application_deadline < ?", true, now).Find(&jobs).Error; err != nil {
+		return fmt.Errorf("loading expired job listings: %w", err)
+	}
+	for _, job := range jobs {
+		result := DB.Model(&models.JobListing{}).Where("id = ? AND is_active = ?", job.ID, true).UpdateColumn("is_active", false)
+		if result.Error != nil {
+			return fmt.Errorf("closing job listing %d: %w", job.ID, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		message := fmt.Sprintf("Applications for %s have closed with %d applicant(s)", job.Title, job.ApplicantCount)
+		if err := utils.CreateNotification(DB, job.CompanyID, message); err != nil {
+			slog.Error("failed to send notification", "error", err)
+		}
+	}
+
+	var projects []models.Project
+	if err := DB.Where("closed_at IS NULL AND deadline < ?", now).Find(&projects).Error; err != nil {
+		return fmt.Errorf("loading expired projects: %w", err)
+	}
+	for _, project := range projects {
+		result := DB.Model(&models.Project{}).Where("id = ? AND closed_at IS NULL", project.ID).UpdateColumn("closed_at", now)
+		if result.Error != nil {
+			return fmt.Errorf("closing project %d: %w", project.ID, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		var applicants int64
+		DB.Model(&models.Application{}).Where("project_id = ?", project.ID).Count(&applicants)
+		message := fmt.Sprintf("The deadline for %s has passed with %d applicant(s)", project.Title, applicants)
+		if err := utils.CreateNotification(DB, project.CompanyID, message); err != nil {
+			slog.Error("failed to send notification", "error", err)
+		}
+	}
+	return nil
+}
+
+// cachedGithubStats returns the stats stored for a portfolio and queues a
+// refresh when they are missing or old
+func cachedGithubStats(settings models.PortfolioSettings) *utils.GitHubUserStats {
+	if settings.GithubStatsFetchedAt == nil || time.Since(*settings.GithubStatsFetchedAt) > githubStatsMaxAge {
+		_, err := queue.Enqueue(DB, jobRefreshGithubStats, githubStatsPayload{UserID: settings.UserID},
+			queue.Unique(fmt.Sprintf("github-stats:%d", settings.UserID)))
+		if err != nil && !errors.Is(err, queue.ErrDuplicate) {
+			slog.Error("failed to queue GitHub stats refresh", "error", err)
+		}
+	}
+	if len(settings.GithubStats) == 0 {
+		return nil
+	}
+	var stats utils.GitHubUserStats
+	if err := json.Unmarshal(settings.GithubStats, &stats); err != nil {
+		return nil
+	}
+	return &stats
+}
+
+// refreshGithubStats fetches a student's public GitHub stats and stores them with their portfolio settings
+func refreshGithubStats(ctx context.Context, payload json.RawMessage) error {
+	var p githubStatsPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return queue.Permanent(err)
+	}
+	var user models.User
+	if err := DB.First(&user, p.UserID).Error; err != nil {
+		return queue.Permanent(err)
+	}
+	username := extractGithubUsername(user.GithubURL)
+	if username == "" {
+		return nil
+	}
+
+	stats, err := utils.GetPublicUserStats(username)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(stats)
+	if err != nil {
+		return queue.Permanent(err)
+	}
+
+	now := time.Now()
+	settings := models.DefaultPortfolioSettings(user.ID)
+	settings.GithubStats = raw
+	settings.GithubStatsFetchedAt = &now
+	return DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"github_stats", "github_stats_fetched_at"}),
+	}).Create(&settings).Error
+}
+
+// GetBackgroundJobs - Admin lists background jobs, newest first, with counts per status
+func GetBackgroundJobs(c *gin.Context) {
+	query := DB.Model(&models.BackgroundJob{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if jobType := c.Query("type"); jobType != "" {
+		query = query.Where("type = ?", jobType)
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var jobs []models.BackgroundJob
+	if err := query.Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&jobs).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch background jobs"))
+		return
+	}
+
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	DB.Model(&models.BackgroundJob{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows)
+	counts := map[string]int64{}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	c.JSON(http.StatusOK, backgroundJobsResponse{
+		Jobs:   jobs,
+		Total:  total,
+		Page:   page,
+		Limit:  limit,
+		Counts: counts,
+	})
+}
+
+// backgroundJobsResponse is one page of jobs with counts per status across all jobs
+type backgroundJobsResponse struct {
+	Jobs   []models.BackgroundJob `json:"jobs"`
+	Total  int64                  `json:"total"`
+	Page   int                    `json:"page"`
+	Limit  int                    `json:"limit"`
+	Counts map[string]int64       `json:"counts"`
+}
+
+// GetBackgroundJob - Admin views one background job, including its last error
+func GetBackgroundJob(c *gin.Context) {
+	var job models.BackgroundJob
+	if err := DB.First(&job, c.Param("id")).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Background job not found"))
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// RetryBackgroundJob - Admin requeues a failed background job
+func RetryBackgroundJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid job ID"))
+		return
+	}
+	var job models.BackgroundJob
+	if err := DB.First(&job, id).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Background job not found"))
+		return
+	}
+	if err := queue.Retry(DB, job.ID); err != nil {
+		apierror.Abort(c, apierror.Conflict("Only failed jobs can be retried"))
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Job requeued"})
+}
+
+// DeleteBackgroundJob - Admin discards a pending or failed background job
+func DeleteBackgroundJob(c *gin.Context) {
+	result := DB.Where("id = ? AND status IN ?", c.Param("id"), []string{models.JobPending, models.JobFailed}).
+		Delete(&models.BackgroundJob{})
+	if result.Error != nil {
+		apierror.Abort(c, apierror.Internal("Failed to delete background job"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		apierror.Abort(c, apierror.NotFound("No pending or failed job with this ID"))
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Job deleted"})
+}
@@ -0,0 +1,302 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"SkillBridge/utils"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// certificateCredential is the signed JSON credential. Field order is fixed by
+// the struct so the same certificate always serialises to the same bytes.
+type certificateCredential struct {
+	ID          string           `json:"id"`
+	Type        string           `json:"type"`
+	Issuer      string           `json:"issuer"`
+	IssuedAt    time.Time        `json:"issued_at"`
+	CompletedAt time.Time        `json:"completed_at"`
+	Student     credentialParty  `json:"student"`
+	Project     credentialParty  `json:"project"`
+	Company     credentialParty  `json:"company"`
+	Guide       *credentialParty `json:"guide,omitempty"`
+}
+
+type credentialParty struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// completedAt is when the student completed the project: when the accepted
+// work was submitted, not when the certificate happens to be issued
+func completedAt(submission models.Submission) time.Time {
+	completed := submission.SubmittedAt
+	if completed.IsZero() {
+		completed = submission.CreatedAt
+	}
+	return completed.UTC().Truncate(time.Second)
+}
+
+// issueCertificate creates the certificate for an accepted submission, or
+// returns the existing one if it was already issued.
+func issueCertificate(submission models.Submission) (models.Certificate, error) {
+	var certificate models.Certificate
+	if err := DB.Where("submission_id = ?", submission.ID).First(&certificate).Error; err == nil {
+		return certificate, nil
+	}
+
+	var student, company models.User
+	var project models.Project
+	if err := DB.First(&student, submission.StudentID).Error; err != nil {
+		return certificate, err
+	}
+	if err := DB.First(&project, submission.ProjectID).Error; err != nil {
+		return certificate, err
+	}
+	if err := DB.First(&company, project.CompanyID).Error; err != nil {
+		return certificate, err
+	}
+	companyName := company.CompanyName
+	if companyName == "" {
+		companyName = company.Name
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	credential := certificateCredential{
+		ID:          utils.NewCertificateID(),
+		Type:        "SkillBridgeProjectCompletion",
+		Issuer:      "SkillBridge",
+		IssuedAt:    now,
+		CompletedAt: completedAt(submission),
+		Student:     credentialParty{ID: student.ID, Name: student.Name},
+		Project:     credentialParty{ID: project.ID, Name: project.Title},
+		Company:     credentialParty{ID: company.ID, Name: companyName},
+	}
+	if project.GuideID != nil {
+		var guide models.User
+		if err := DB.First(&guide, *project.GuideID).Error; err == nil {
+			credential.Guide = &credentialParty{ID: guide.ID, Name: guide.Name}
+		}
+	}
+
+	payload, err := json.Marshal(credential)
+	if err != nil {
+		return certificate, err
+	}
+	signature, err := utils.SignCredential(payload)
+	if err != nil {
+		return certificate, err
+	}
+
+	certificate = models.Certificate{
+		CertificateID: credential.ID,
+		SubmissionID:  submission.ID,
+		StudentID:     student.ID,
+		ProjectID:     project.ID,
+		CompanyID:     company.ID,
+		GuideID:       project.GuideID,
+		StudentName:   student.Name,
+		ProjectTitle:  project.Title,
+		CompanyName:   companyName,
+		CompletedAt:   credential.CompletedAt,
+		IssuedAt:      credential.IssuedAt,
+		Credential:    string(payload),
+		Signature:     signature,
+	}
+	if credential.Guide != nil {
+		certificate.GuideName = credential.Guide.Name
+	}
+	if err := DB.Create(&certificate).Error; err != nil {
+		return certificate, err
+	}
+
+	if err := utils.CreateNotification(DB, student.ID, "You earned a certificate of completion for "+project.Title); err != nil {
+		slog.Error("failed to send notification", "error", err)
+	}
+	return certificate, nil
+}
+
+// certificateStatus verifies the stored signature and revocation state
+func certificateStatus(certificate models.Certificate) (bool, string) {
+	if !utils.VerifyCredential([]byte(certificate.Credential), certificate.Signature) {
+		return false, "invalid_signature"
+	}
+	if certificate.Revoked {
+		return false, "revoked"
+	}
+	return true, "valid"
+}
+
+// verificationResponse reports whether a certificate is genuine and still valid.
+// The revocation fields are only set for revoked certificates.
+type verificationResponse struct {
+	Valid            bool            `json:"valid"`
+	Status           string          `json:"status"` // valid, revoked or invalid_signature
+	Credential       json.RawMessage `json:"credential"`
+	Signature        string          `json:"signature"`
+	Algorithm        string          `json:"algorithm"`
+	PublicKey        string          `json:"public_key"`
+	RevokedAt        *time.Time      `json:"revoked_at,omitempty"`
+	RevocationReason string          `json:"revocation_reason,omitempty"`
+}
+
+// VerifyCertificate - Public endpoint that checks a certificate's signature and revocation status
+func VerifyCertificate(c *gin.Context) {
+	var certificate models.Certificate
+	if err := DB.Where("certificate_id = ?", c.Param("certificate_id")).First(&certificate).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Certificate not found").WithDetails(gin.H{"valid": false, "status": "not_found"}))
+		return
+	}
+
+	valid, status := certificateStatus(certificate)
+	response := verificationResponse{
+		Valid:      valid,
+		Status:     status,
+		Credential: json.RawMessage(certificate.Credential),
+		Signature:  certificate.Signature,
+		Algorithm:  "Ed25519",
+		PublicKey:  utils.CertificatePublicKey(),
+	}
+	if certificate.Revoked {
+		response.RevokedAt = certificate.RevokedAt
+		response.RevocationReason = certificate.RevocationReason
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCertificatePDF - Download the printable certificate
+func GetCertificatePDF(c *gin.Context) {
+	var certificate models.Certificate
+	if err := DB.Where("certificate_id = ?", c.Param("certificate_id")).First(&certificate).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Certificate not found"))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", certificate.CertificateID+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", renderCertificatePDF(certificate))
+}
+
+type publicKeyResponse struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"`
+}
+
+// GetCertificatePublicKey - Public key for verifying credentials offline
+func GetCertificatePublicKey(c *gin.Context) {
+	c.JSON(http.StatusOK, publicKeyResponse{Algorithm: "Ed25519", PublicKey: utils.CertificatePublicKey()})
+}
+
+type certificatesResponse struct {
+	Certificates []models.Certificate `json:"certificates"`
+	Count        int                  `json:"count"`
+}
+
+// GetMyCertificates - Student lists the certificates they have earned
+func GetMyCertificates(c *gin.Context) {
+	studentID := c.GetUint("userID")
+
+	var certificates []models.Certificate
+	if err := DB.Where("student_id = ?", studentID).Order("issued_at DESC").Find(&certificates).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch certificates"))
+		return
+	}
+
+	c.JSON(http.StatusOK, certificatesResponse{Certificates: certificates, Count: len(certificates)})
+}
+
+type revokeCertificateInput struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type revokeCertificateResponse struct {
+	Message       string `json:"message"`
+	CertificateID string `json:"certificate_id"`
+}
+
+// RevokeCertificate - Issuing company or an admin revokes a certificate
+func RevokeCertificate(c *gin.Context) {
+	userID := c.GetUint("userID")
+	role := c.GetString("role")
+
+	var input revokeCertificateInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	var certificate models.Certificate
+	if err := DB.Where("certificate_id = ?", c.Param("certificate_id")).First(&certificate).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Certificate not found"))
+		return
+	}
+	var project models.Project
+	DB.First(&project, certificate.ProjectID)
+	if role != "admin" && !companyOwns(userID, certificate.CompanyID, project.OrganisationID) {
+		apierror.Abort(c, apierror.Forbidden("Only the issuing company or an admin can revoke this certificate"))
+		return
+	}
+	if certificate.Revoked {
+		apierror.Abort(c, apierror.Conflict("Certificate already revoked"))
+		return
+	}
+
+	now := time.Now()
+	if err := DB.Model(&certificate).Updates(map[string]interface{}{
+		"revoked":           true,
+		"revoked_at":        now,
+		"revoked_by_id":     userID,
+		"revocation_reason": input.Reason,
+	}).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to revoke certificate"))
+		return
+	}
+
+	if err := utils.CreateNotification(DB, certificate.StudentID, "Your certificate for "+certificate.ProjectTitle+" was revoked: "+input.Reason); err != nil {
+		middleware.Log(c).Error("failed to send notification", "error", err)
+	}
+
+	c.JSON(http.StatusOK, revokeCertificateResponse{
+		Message:       "Certificate revoked successfully",
+		CertificateID: certificate.CertificateID,
+	})
+}
+
+// renderCertificatePDF lays out a single landscape-style certificate on an A4 page
+func renderCertificatePDF(certificate models.Certificate) []byte {
+	doc := utils.NewPDFDocument()
+	doc.AddPage()
+
+	doc.Rect(30, 30, utils.PDFPageWidth-60, utils.PDFPageHeight-60, 3, 0, false)
+	doc.Rect(40, 40, utils.PDFPageWidth-80, utils.PDFPageHeight-80, 0.75, 0, false)
+
+	top := utils.PDFPageHeight - 160
+	doc.CenteredText(top, 30, true, "Certificate of Completion")
+	doc.CenteredText(top-50, 13, false, "This certifies that")
+	doc.CenteredText(top-95, 26, true, certificate.StudentName)
+	doc.Line(120, top-108, utils.PDFPageWidth-120, top-108, 0.75)
+	doc.CenteredText(top-145, 13, false, "has successfully completed the project")
+
+	y := top - 185
+	for _, line := range utils.PDFWrapText(certificate.ProjectTitle, 18, utils.PDFPageWidth-160) {
+		doc.CenteredText(y, 18, true, line)
+		y -= 24
+	}
+	doc.CenteredText(y-16, 13, false, "offered by "+certificate.CompanyName)
+	if certificate.GuideName != "" {
+		doc.CenteredText(y-38, 13, false, "under the guidance of "+certificate.GuideName)
+	}
+
+	doc.CenteredText(200, 12, false, "Completed on "+certificate.CompletedAt.Format("2 January 2006"))
+	doc.CenteredText(140, 10, false, "Certificate ID: "+certificate.CertificateID)
+	doc.CenteredText(124, 9, false, "Verify at /api/certificates/"+certificate.CertificateID+"/verify")
+	if certificate.Revoked {
+		doc.CenteredText(90, 16, true, "REVOKED")
+	}
+
+	return doc.Bytes()
+}
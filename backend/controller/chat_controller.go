@@ -1,35 +1,40 @@
 package controller
 
 import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
 	"SkillBridge/models"
+	"SkillBridge/notify"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
 	"time"
 )
 
+type sendMessageInput struct {
+	StudentID uint   `json:"student_id" binding:"required"`
+	GuideID   uint   `json:"guide_id" binding:"required"`
+	Message   string `json:"message" binding:"required"`
+}
+
 // SendMessage - Send a chat message
 func SendMessage(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		apierror.Abort(c, apierror.Unauthorized("User not authenticated"))
 		return
 	}
 
 	userRole, exists := c.Get("role")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+		apierror.Abort(c, apierror.Unauthorized("User role not found"))
 		return
 	}
 
-	var input struct {
-		StudentID uint   `json:"student_id" binding:"required"`
-		GuideID   uint   `json:"guide_id" binding:"required"`
-		Message   string `json:"message" binding:"required"`
-	}
+	var input sendMessageInput
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &input) {
 		return
 	}
 
@@ -38,11 +43,11 @@ func SendMessage(c *gin.Context) {
 	uid := userID.(uint)
 	
 	if role == "student" && uid != input.StudentID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only send messages as yourself"})
+		apierror.Abort(c, apierror.Forbidden("You can only send messages as yourself"))
 		return
 	}
 	if role == "guide" && uid != input.GuideID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only send messages as yourself"})
+		apierror.Abort(c, apierror.Forbidden("You can only send messages as yourself"))
 		return
 	}
 
@@ -57,24 +62,52 @@ func SendMessage(c *gin.Context) {
 	}
 
 	if err := DB.Create(&chat).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send message"})
+		apierror.Abort(c, apierror.Internal("Failed to send message"))
 		return
 	}
 
 	// Load sender information
 	DB.Preload("Sender").First(&chat, chat.ID)
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Message sent successfully",
-		"chat":    chat,
+	// Let the other side know if they are not around to see it
+	recipientID := input.StudentID
+	if uid == input.StudentID {
+		recipientID = input.GuideID
+	}
+	if !userOnline(recipientID) {
+		err := notify.Send(DB, recipientID, notify.Message{
+			Event:    notify.EventChatMessage,
+			Text:     "New message from " + chat.Sender.Name,
+			Data:     map[string]string{"sender": chat.Sender.Name, "preview": messagePreview(input.Message)},
+			Link:     fmt.Sprintf("/chat/%d/%d", input.StudentID, input.GuideID),
+			Coalesce: fmt.Sprintf("chat:%d:%d", input.StudentID, input.GuideID),
+		})
+		if err != nil {
+			middleware.Log(c).Error("failed to send notification", "error", err)
+		}
+	}
+
+	c.JSON(http.StatusCreated, chatMessageResponse{
+		Message: "Message sent successfully",
+		Chat:    chat,
 	})
 }
 
+type chatMessageResponse struct {
+	Message string      `json:"message"`
+	Chat    models.Chat `json:"chat"`
+}
+
+type chatHistoryResponse struct {
+	Chats []models.Chat `json:"chats"`
+	Count int           `json:"count"`
+}
+
 // GetChatHistory - Get chat history between student and guide
 func GetChatHistory(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		apierror.Abort(c, apierror.Unauthorized("User not authenticated"))
 		return
 	}
 
@@ -83,20 +116,20 @@ func GetChatHistory(c *gin.Context) {
 
 	studentID, err := strconv.Atoi(studentIDParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid student ID"))
 		return
 	}
 
 	guideID, err := strconv.Atoi(guideIDParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guide ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid guide ID"))
 		return
 	}
 
 	// Verify user has access to this conversation
 	uid := userID.(uint)
 	if uid != uint(studentID) && uid != uint(guideID) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		apierror.Abort(c, apierror.Forbidden("Access denied"))
 		return
 	}
 
@@ -105,7 +138,7 @@ func GetChatHistory(c *gin.Context) {
 		Preload("Sender").
 		Order("created_at ASC").
 		Find(&chats).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch chat history"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch chat history"))
 		return
 	}
 
@@ -115,9 +148,9 @@ func GetChatHistory(c *gin.Context) {
 			studentID, guideID, uid, false).
 		Update("is_read", true)
 
-	c.JSON(http.StatusOK, gin.H{
-		"chats": chats,
-		"count": len(chats),
+	c.JSON(http.StatusOK, chatHistoryResponse{
+		Chats: chats,
+		Count: len(chats),
 	})
 }
 
@@ -125,13 +158,13 @@ func GetChatHistory(c *gin.Context) {
 func GetUserConversations(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		apierror.Abort(c, apierror.Unauthorized("User not authenticated"))
 		return
 	}
 
 	userRole, exists := c.Get("role")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+		apierror.Abort(c, apierror.Unauthorized("User role not found"))
 		return
 	}
 
@@ -200,41 +233,57 @@ func GetUserConversations(c *gin.Context) {
 			ORDER BY c.created_at DESC
 		`, uid, uid, uid).Scan(&conversations).Error
 	} else {
-		c.JSON(http.StatusForbidden, gin.H{"error": "This endpoint is only available for students and guides"})
+		apierror.Abort(c, apierror.Forbidden("This endpoint is only available for students and guides"))
 		return
 	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch conversations: " + err.Error()})
+		apierror.Abort(c, apierror.Internal("Failed to fetch conversations: "+err.Error()))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"conversations": conversations,
-		"count":         len(conversations),
+	c.JSON(http.StatusOK, conversationsResponse{
+		Conversations: conversations,
+		Count:         len(conversations),
 	})
 }
 
+type conversationsResponse struct {
+	Conversations []models.ChatConversation `json:"conversations"`
+	Count         int                       `json:"count"`
+}
+
+type startConversationInput struct {
+	GuideID uint `json:"guide_id" binding:"required"`
+}
+
+// conversationResponse has a status while the guide has yet to accept the request
+type conversationResponse struct {
+	Message     string `json:"message"`
+	StudentID   uint   `json:"student_id"`
+	GuideID     uint   `json:"guide_id"`
+	GuideName   string `json:"guide_name"`
+	StudentName string `json:"student_name"`
+	Status      string `json:"status,omitempty"`
+}
+
 // StartConversation - Create a pending connection request from student to guide
 func StartConversation(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		apierror.Abort(c, apierror.Unauthorized("User not authenticated"))
 		return
 	}
 
 	userRole, exists := c.Get("role")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+		apierror.Abort(c, apierror.Unauthorized("User role not found"))
 		return
 	}
 
-	var input struct {
-		GuideID uint `json:"guide_id" binding:"required"`
-	}
+	var input startConversationInput
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &input) {
 		return
 	}
 
@@ -243,21 +292,21 @@ func StartConversation(c *gin.Context) {
 
 	// Only students can request connections with guides
 	if role != "student" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only students can request connections with guides"})
+		apierror.Abort(c, apierror.Forbidden("Only students can request connections with guides"))
 		return
 	}
 
 	// Verify the guide exists
 	var guide models.User
 	if err := DB.Where("id = ? AND role = ?", input.GuideID, "guide").First(&guide).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Guide not found"})
+		apierror.Abort(c, apierror.NotFound("Guide not found"))
 		return
 	}
 
 	// Get student info
 	var student models.User
 	if err := DB.First(&student, uid).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+		apierror.Abort(c, apierror.NotFound("Student not found"))
 		return
 	}
 
@@ -274,12 +323,12 @@ func StartConversation(c *gin.Context) {
 			
 			if chatErr == nil {
 				// Chat already exists
-				c.JSON(http.StatusOK, gin.H{
-					"message":     "Conversation already exists",
-					"student_id":  uid,
-					"guide_id":    input.GuideID,
-					"guide_name":  guide.Name,
-					"student_name": student.Name,
+				c.JSON(http.StatusOK, conversationResponse{
+					Message:     "Conversation already exists",
+					StudentID:   uid,
+					GuideID:     input.GuideID,
+					GuideName:   guide.Name,
+					StudentName: student.Name,
 				})
 				return
 			}
@@ -296,25 +345,23 @@ func StartConversation(c *gin.Context) {
 			}
 
 			if err := DB.Create(&initialChat).Error; err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start conversation"})
+				apierror.Abort(c, apierror.Internal("Failed to start conversation"))
 				return
 			}
 
-			c.JSON(http.StatusCreated, gin.H{
-				"message":      "Conversation started successfully",
-				"student_id":   uid,
-				"guide_id":     input.GuideID,
-				"guide_name":   guide.Name,
-				"student_name": student.Name,
+			c.JSON(http.StatusCreated, conversationResponse{
+				Message:     "Conversation started successfully",
+				StudentID:   uid,
+				GuideID:     input.GuideID,
+				GuideName:   guide.Name,
+				StudentName: student.Name,
 			})
 			return
 		}
 
 		// Request is pending or rejected
-		c.JSON(http.StatusConflict, gin.H{
-			"message": "Connection request already " + existingRequest.Status,
-			"status":  existingRequest.Status,
-		})
+		apierror.Abort(c, apierror.Conflict("Connection request already "+existingRequest.Status).
+			WithDetails(gin.H{"status": existingRequest.Status}))
 		return
 	}
 
@@ -327,31 +374,46 @@ func StartConversation(c *gin.Context) {
 	}
 
 	if err := DB.Create(&connectionRequest).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create connection request"})
+		apierror.Abort(c, apierror.Internal("Failed to create connection request"))
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message":      "Connection request sent to guide. Waiting for confirmation.",
-		"student_id":   uid,
-		"guide_id":     input.GuideID,
-		"guide_name":   guide.Name,
-		"student_name": student.Name,
-		"status":       "pending",
+	err = notify.Send(DB, guide.ID, notify.Message{
+		Event: notify.EventConnectionRequest,
+		Text:  student.Name + " sent you a connection request",
+		Data:  map[string]string{"student": student.Name},
+		Link:  "/guide/pending-confirmations",
+	})
+	if err != nil {
+		middleware.Log(c).Error("failed to send notification", "error", err)
+	}
+
+	c.JSON(http.StatusCreated, conversationResponse{
+		Message:     "Connection request sent to guide. Waiting for confirmation.",
+		StudentID:   uid,
+		GuideID:     input.GuideID,
+		GuideName:   guide.Name,
+		StudentName: student.Name,
+		Status:      "pending",
 	})
 }
 
+type connectedGuidesResponse struct {
+	ConnectedGuideIDs []uint `json:"connected_guide_ids"`
+	Count             int    `json:"count"`
+}
+
 // GetConnectedGuides - Get guides that student is already connected with
 func GetConnectedGuides(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		apierror.Abort(c, apierror.Unauthorized("User not authenticated"))
 		return
 	}
 
 	userRole, exists := c.Get("role")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+		apierror.Abort(c, apierror.Unauthorized("User role not found"))
 		return
 	}
 
@@ -360,7 +422,7 @@ func GetConnectedGuides(c *gin.Context) {
 
 	// Only students can get their connected guides
 	if role != "student" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only students can access this endpoint"})
+		apierror.Abort(c, apierror.Forbidden("Only students can access this endpoint"))
 		return
 	}
 
@@ -370,23 +432,28 @@ func GetConnectedGuides(c *gin.Context) {
 		Distinct("guide_id").
 		Pluck("guide_id", &connectedGuideIds)
 
-	c.JSON(http.StatusOK, gin.H{
-		"connected_guide_ids": connectedGuideIds,
-		"count":              len(connectedGuideIds),
+	c.JSON(http.StatusOK, connectedGuidesResponse{
+		ConnectedGuideIDs: connectedGuideIds,
+		Count:             len(connectedGuideIds),
 	})
 }
 
+type pendingConfirmationsResponse struct {
+	PendingRequests []models.GuideConnectionRequest `json:"pending_requests"`
+	Count           int                             `json:"count"`
+}
+
 // GetPendingConfirmations - Get pending student connection requests for a guide
 func GetPendingConfirmations(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		apierror.Abort(c, apierror.Unauthorized("User not authenticated"))
 		return
 	}
 
 	userRole, exists := c.Get("role")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+		apierror.Abort(c, apierror.Unauthorized("User role not found"))
 		return
 	}
 
@@ -395,7 +462,7 @@ func GetPendingConfirmations(c *gin.Context) {
 
 	// Only guides can see pending confirmations
 	if role != "guide" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only guides can access this endpoint"})
+		apierror.Abort(c, apierror.Forbidden("Only guides can access this endpoint"))
 		return
 	}
 
@@ -405,27 +472,38 @@ func GetPendingConfirmations(c *gin.Context) {
 		Where("guide_id = ? AND status = ?", guideID, "pending").
 		Order("created_at DESC").
 		Find(&requests).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pending requests"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch pending requests"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"pending_requests": requests,
-		"count":           len(requests),
+	c.JSON(http.StatusOK, pendingConfirmationsResponse{
+		PendingRequests: requests,
+		Count:           len(requests),
 	})
 }
 
+type confirmConnectionInput struct {
+	RequestID uint   `json:"request_id" binding:"required"`
+	Action    string `json:"action" binding:"required"` // "accept" or "reject"
+}
+
+type confirmConnectionResponse struct {
+	Message   string `json:"message"`
+	RequestID uint   `json:"request_id"`
+	Status    string `json:"status"`
+}
+
 // ConfirmConnection - Guide accepts or rejects a student connection request
 func ConfirmConnection(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		apierror.Abort(c, apierror.Unauthorized("User not authenticated"))
 		return
 	}
 
 	userRole, exists := c.Get("role")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User role not found"})
+		apierror.Abort(c, apierror.Unauthorized("User role not found"))
 		return
 	}
 
@@ -434,35 +512,31 @@ func ConfirmConnection(c *gin.Context) {
 
 	// Only guides can confirm connections
 	if role != "guide" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only guides can confirm connections"})
+		apierror.Abort(c, apierror.Forbidden("Only guides can confirm connections"))
 		return
 	}
 
-	var input struct {
-		RequestID uint   `json:"request_id" binding:"required"`
-		Action    string `json:"action" binding:"required"` // "accept" or "reject"
-	}
+	var input confirmConnectionInput
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &input) {
 		return
 	}
 
 	if input.Action != "accept" && input.Action != "reject" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Action must be 'accept' or 'reject'"})
+		apierror.Abort(c, apierror.BadRequest("Action must be 'accept' or 'reject'"))
 		return
 	}
 
 	// Get the connection request
 	var request models.GuideConnectionRequest
 	if err := DB.First(&request, input.RequestID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Request not found"})
+		apierror.Abort(c, apierror.NotFound("Request not found"))
 		return
 	}
 
 	// Verify this guide owns this request
 	if request.GuideID != guideID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "You can only confirm your own requests"})
+		apierror.Abort(c, apierror.Forbidden("You can only confirm your own requests"))
 		return
 	}
 
@@ -473,7 +547,7 @@ func ConfirmConnection(c *gin.Context) {
 	}
 
 	if err := DB.Model(&request).Update("status", newStatus).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update request"})
+		apierror.Abort(c, apierror.Internal("Failed to update request"))
 		return
 	}
 
@@ -496,15 +570,15 @@ func ConfirmConnection(c *gin.Context) {
 			}
 
 			if err := DB.Create(&initialChat).Error; err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create conversation"})
+				apierror.Abort(c, apierror.Internal("Failed to create conversation"))
 				return
 			}
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":   "Connection request " + newStatus,
-		"request_id": request.ID,
-		"status":    newStatus,
+	c.JSON(http.StatusOK, confirmConnectionResponse{
+		Message:   "Connection request " + newStatus,
+		RequestID: request.ID,
+		Status:    newStatus,
 	})
 }
\ No newline at end of file
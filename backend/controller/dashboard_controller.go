@@ -1,11 +1,24 @@
 package controller
 
 import (
+	"SkillBridge/apierror"
 	"SkillBridge/models"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
+type studentDashboardResponse struct {
+	Projects applicationCounts `json:"projects"`
+	Jobs     applicationCounts `json:"jobs"`
+}
+
+// applicationCounts are a student's applications and their outcomes
+type applicationCounts struct {
+	Applications int64 `json:"applications"`
+	Accepted     int64 `json:"accepted"`
+	Rejected     int64 `json:"rejected"`
+}
+
 func StudentDashboard(c *gin.Context) {
 	studentID := c.GetUint("userID")
 
@@ -29,77 +42,95 @@ func StudentDashboard(c *gin.Context) {
 	var jobRejected int64
 	DB.Model(&models.JobApplication{}).Where("user_id = ? AND status = ?", studentID, "Rejected").Count(&jobRejected)
 
-	c.JSON(http.StatusOK, gin.H{
-		"projects": gin.H{
-			"applications": projectApplications,
-			"accepted":      projectAccepted,
-			"rejected":      projectRejected,
+	c.JSON(http.StatusOK, studentDashboardResponse{
+		Projects: applicationCounts{
+			Applications: projectApplications,
+			Accepted:     projectAccepted,
+			Rejected:     projectRejected,
 		},
-		"jobs": gin.H{
-			"applications": jobApplications,
-			"accepted":      jobAccepted,
-			"rejected":      jobRejected,
+		Jobs: applicationCounts{
+			Applications: jobApplications,
+			Accepted:     jobAccepted,
+			Rejected:     jobRejected,
 		},
 	})
 }
 
+type companyDashboardResponse struct {
+	Projects listingCounts `json:"projects"`
+	Jobs     listingCounts `json:"jobs"`
+}
+
+// listingCounts are a company's listings and the applications they received
+type listingCounts struct {
+	Posted       int64 `json:"posted"`
+	Applications int64 `json:"applications"`
+}
+
 func CompanyDashboard(c *gin.Context) {
 	role := c.GetString("role")
 	if role != "company" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Forbidden("Only companies can view this dashboard"))
 		return
 	}
 
 	companyID := c.GetUint("userID")
 	if companyID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid company ID"))
 		return
 	}
 
 	// --- Projects section: posted, applications ---
 	var postedProjects int64
-	DB.Model(&models.Project{}).Where("company_id = ?", companyID).Count(&postedProjects)
+	DB.Model(&models.Project{}).Scopes(ownedByCompany("projects", companyID)).Count(&postedProjects)
 
 	var projectApplications int64
 	DB.Table("applications").
 		Joins("JOIN projects ON applications.project_id = projects.id").
-		Where("projects.company_id = ?", companyID).
+		Scopes(ownedByCompany("projects", companyID)).
 		Count(&projectApplications)
 
 	// --- Jobs section: posted, applications ---
 	var postedJobs int64
-	DB.Model(&models.JobListing{}).Where("company_id = ?", companyID).Count(&postedJobs)
+	DB.Model(&models.JobListing{}).Scopes(ownedByCompany("job_listings", companyID)).Count(&postedJobs)
 
 	var jobApplications int64
 	DB.Table("job_applications").
 		Joins("JOIN job_listings ON job_applications.job_listing_id = job_listings.id").
-		Where("job_listings.company_id = ?", companyID).
+		Scopes(ownedByCompany("job_listings", companyID)).
 		Count(&jobApplications)
 
-	c.JSON(http.StatusOK, gin.H{
-		"projects": gin.H{
-			"posted":       postedProjects,
-			"applications": projectApplications,
+	c.JSON(http.StatusOK, companyDashboardResponse{
+		Projects: listingCounts{
+			Posted:       postedProjects,
+			Applications: projectApplications,
 		},
-		"jobs": gin.H{
-			"posted":       postedJobs,
-			"applications": jobApplications,
+		Jobs: listingCounts{
+			Posted:       postedJobs,
+			Applications: jobApplications,
 		},
 	})
 }
 
+type guideDashboardResponse struct {
+	AssignedProjects int64 `json:"assigned_projects"`
+	TotalSubmissions int64 `json:"total_submissions"`
+	PendingReviews   int64 `json:"pending_reviews"`
+	CompletedReviews int64 `json:"completed_reviews"`
+}
+
 func GuideDashboard(c *gin.Context) {
 	// Ensure only guide users access this endpoint
 	role := c.GetString("role")
 	if role != "guide" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Forbidden("Only guides can view this dashboard"))
 		return
 	}
 
 	// Get logged-in guide ID from JWT middleware
 	guideID := c.GetUint("userID")
 	if guideID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guide ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid guide ID"))
 		return
 	}
 
@@ -114,7 +145,7 @@ func GuideDashboard(c *gin.Context) {
 		Model(&models.GuideConnectionRequest{}).
 		Where("guide_id = ? AND status = ?", guideID, "accepted").
 		Count(&assignedStudents).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count assigned students"})
+		apierror.Abort(c, apierror.Internal("Failed to count assigned students"))
 		return
 	}
 
@@ -123,7 +154,7 @@ func GuideDashboard(c *gin.Context) {
 		Model(&models.GuideConnectionRequest{}).
 		Where("guide_id = ? AND status = ?", guideID, "pending").
 		Count(&pendingReviews).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count pending reviews"})
+		apierror.Abort(c, apierror.Internal("Failed to count pending reviews"))
 		return
 	}
 
@@ -132,7 +163,7 @@ func GuideDashboard(c *gin.Context) {
 		Table("submissions").
 		Joins("JOIN guide_connection_requests ON submissions.student_id IN (SELECT student_id FROM guide_connection_requests WHERE guide_id = ? AND status = 'accepted')", guideID).
 		Count(&totalSubmissions).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count submissions"})
+		apierror.Abort(c, apierror.Internal("Failed to count submissions"))
 		return
 	}
 
@@ -141,19 +172,25 @@ func GuideDashboard(c *gin.Context) {
 		Model(&models.GuideConnectionRequest{}).
 		Where("guide_id = ? AND status = ?", guideID, "accepted").
 		Count(&completedReviews).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count completed reviews"})
+		apierror.Abort(c, apierror.Internal("Failed to count completed reviews"))
 		return
 	}
 
 	// Return the dashboard stats
-	c.JSON(http.StatusOK, gin.H{
-		"assigned_projects":  assignedStudents,
-		"total_submissions":  totalSubmissions,
-		"pending_reviews":    pendingReviews,
-		"completed_reviews":  completedReviews,
+	c.JSON(http.StatusOK, guideDashboardResponse{
+		AssignedProjects: assignedStudents,
+		TotalSubmissions: totalSubmissions,
+		PendingReviews:   pendingReviews,
+		CompletedReviews: completedReviews,
 	})
 }
 
+type adminDashboardResponse struct {
+	TotalStudents  int64 `json:"total_students"`
+	TotalCompanies int64 `json:"total_companies"`
+	TotalProjects  int64 `json:"total_projects"`
+}
+
 func AdminDashboard(c *gin.Context) {
 	var studentCount, companyCount, projectCount int64
 
@@ -161,9 +198,9 @@ func AdminDashboard(c *gin.Context) {
 	DB.Model(&models.User{}).Where("role = ?", "company").Count(&companyCount)
 	DB.Model(&models.Project{}).Count(&projectCount)
 
-	c.JSON(http.StatusOK, gin.H{
-		"total_students":  studentCount,
-		"total_companies": companyCount,
-		"total_projects":  projectCount,
+	c.JSON(http.StatusOK, adminDashboardResponse{
+		TotalStudents:  studentCount,
+		TotalCompanies: companyCount,
+		TotalProjects:  projectCount,
 	})
 }
@@ -0,0 +1,447 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"SkillBridge/utils"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errProjectHasGuide = errors.New("Project already has a guide assigned")
+	errGuideAtCapacity = errors.New("Guide has reached their project capacity")
+)
+
+// guideCapacity returns the maximum number of projects the guide can hold
+func guideCapacity(guide models.User) int {
+	if guide.GuideCapacity > 0 {
+		return guide.GuideCapacity
+	}
+	return models.DefaultGuideCapacity
+}
+
+// lockGuide loads the guide with a locking read, so concurrent assignments to
+// the same guide queue up behind each other's capacity check
+func lockGuide(tx *gorm.DB, guideID uint) (models.User, error) {
+	var guide models.User
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ? AND role = ?", guideID, "guide").First(&guide).Error
+	return guide, err
+}
+
+// guideHasCapacity reports whether the guide can take on one more project.
+// Before assigning, call it in the same transaction after lockGuide.
+func guideHasCapacity(tx *gorm.DB, guide models.User) (bool, error) {
+	var assigned int64
+	if err := tx.Model(&models.Project{}).Where("guide_id = ?", guide.ID).Count(&assigned).Error; err != nil {
+		return false, err
+	}
+	return int(assigned) < guideCapacity(guide), nil
+}
+
+type inviteGuideInput struct {
+	GuideID uint   `json:"guide_id" binding:"required"`
+	Message string `json:"message"`
+}
+
+// InviteGuideToProject - Company invites a guide to review a project
+func InviteGuideToProject(c *gin.Context) {
+	companyID := c.GetUint("userID")
+
+	projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid project ID"))
+		return
+	}
+
+	var input inviteGuideInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	var project models.Project
+	if err := DB.Scopes(ownedByCompany("projects", companyID)).Where("id = ?", projectID).First(&project).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Project not found or not owned by you"))
+		return
+	}
+
+	if project.GuideID != nil {
+		apierror.Abort(c, apierror.Conflict(errProjectHasGuide.Error()))
+		return
+	}
+
+	var guide models.User
+	if err := DB.Where("id = ? AND role = ?", input.GuideID, "guide").First(&guide).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Guide not found"))
+		return
+	}
+
+	var existing models.ProjectGuideInvitation
+	if err := DB.Where("project_id = ? AND guide_id = ? AND status = ?", project.ID, guide.ID, "pending").First(&existing).Error; err == nil {
+		apierror.Abort(c, apierror.Conflict("Guide has already been invited to this project"))
+		return
+	}
+
+	// Only an early answer for the company; accepting checks again under a lock
+	hasCapacity, err := guideHasCapacity(DB, guide)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to check guide capacity"))
+		return
+	}
+	if !hasCapacity {
+		apierror.Abort(c, apierror.Conflict(errGuideAtCapacity.Error()))
+		return
+	}
+
+	invitation := models.ProjectGuideInvitation{
+		ProjectID:   project.ID,
+		GuideID:     guide.ID,
+		InvitedByID: companyID,
+		Message:     input.Message,
+		Status:      "pending",
+	}
+	if err := DB.Create(&invitation).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to create invitation"))
+		return
+	}
+
+	if err := utils.CreateNotification(DB, guide.ID, "You have been invited to guide the project: "+project.Title); err != nil {
+		middleware.Log(c).Error("failed to send notification", "error", err)
+	}
+
+	c.JSON(http.StatusCreated, guideInvitationResponse{
+		Message:    "Guide invited successfully",
+		Invitation: invitation,
+	})
+}
+
+type guideInvitationResponse struct {
+	Message    string                        `json:"message"`
+	Invitation models.ProjectGuideInvitation `json:"invitation"`
+}
+
+type guideInvitationsResponse struct {
+	Invitations []models.ProjectGuideInvitation `json:"invitations"`
+	Count       int                             `json:"count"`
+}
+
+// GetProjectGuideInvitations - Company lists the guide invitations sent for a project
+func GetProjectGuideInvitations(c *gin.Context) {
+	companyID := c.GetUint("userID")
+	projectID := c.Param("id")
+
+	var project models.Project
+	if err := DB.Scopes(ownedByCompany("projects", companyID)).Where("id = ?", projectID).First(&project).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Project not found or not owned by you"))
+		return
+	}
+
+	var invitations []models.ProjectGuideInvitation
+	if err := DB.Preload("Guide", selectPublicUser).Where("project_id = ?", project.ID).Order("created_at DESC").Find(&invitations).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch invitations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, guideInvitationsResponse{Invitations: invitations, Count: len(invitations)})
+}
+
+// GetGuideInvitations - Guide lists invitations sent to them, optionally filtered by status
+func GetGuideInvitations(c *gin.Context) {
+	guideID := c.GetUint("userID")
+	status := c.DefaultQuery("status", "pending")
+
+	query := DB.Preload("Project").Where("guide_id = ?", guideID)
+	if status != "all" {
+		query = query.Where("status = ?", status)
+	}
+
+	var invitations []models.ProjectGuideInvitation
+	if err := query.Order("created_at DESC").Find(&invitations).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch invitations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, guideInvitationsResponse{Invitations: invitations, Count: len(invitations)})
+}
+
+type guideInvitationResponseInput struct {
+	Action string `json:"action" binding:"required"` // "accept" or "decline"
+}
+
+// respondInvitationResponse names the project the guide joined, if they accepted
+type respondInvitationResponse struct {
+	Message   string `json:"message"`
+	Status    string `json:"status"`
+	ProjectID uint   `json:"project_id,omitempty"`
+}
+
+// RespondToGuideInvitation - Guide accepts or declines a project invitation
+func RespondToGuideInvitation(c *gin.Context) {
+	guideID := c.GetUint("userID")
+
+	var input guideInvitationResponseInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if input.Action != "accept" && input.Action != "decline" {
+		apierror.Abort(c, apierror.BadRequest("Action must be 'accept' or 'decline'"))
+		return
+	}
+
+	var invitation models.ProjectGuideInvitation
+	if err := DB.Preload("Project").First(&invitation, c.Param("id")).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Invitation not found"))
+		return
+	}
+	if invitation.GuideID != guideID {
+		apierror.Abort(c, apierror.Forbidden("You can only respond to your own invitations"))
+		return
+	}
+	if invitation.Status != "pending" {
+		apierror.Abort(c, apierror.Conflict("Invitation already "+invitation.Status))
+		return
+	}
+
+	now := time.Now()
+	if input.Action == "decline" {
+		if err := DB.Model(&invitation).Updates(map[string]interface{}{"status": "declined", "responded_at": now}).Error; err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to update invitation"))
+			return
+		}
+		if err := utils.CreateNotification(DB, invitation.Project.CompanyID, "A guide declined your invitation for project: "+invitation.Project.Title); err != nil {
+			middleware.Log(c).Error("failed to send notification", "error", err)
+		}
+		c.JSON(http.StatusOK, respondInvitationResponse{Message: "Invitation declined", Status: "declined"})
+		return
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		var project models.Project
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&project, invitation.ProjectID).Error; err != nil {
+			return err
+		}
+		if project.GuideID != nil {
+			return errProjectHasGuide
+		}
+
+		guide, err := lockGuide(tx, guideID)
+		if err != nil {
+			return err
+		}
+		hasCapacity, err := guideHasCapacity(tx, guide)
+		if err != nil {
+			return err
+		}
+		if !hasCapacity {
+			return errGuideAtCapacity
+		}
+
+		if err := tx.Model(&project).Update("guide_id", guideID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&invitation).Updates(map[string]interface{}{"status": "accepted", "responded_at": now}).Error; err != nil {
+			return err
+		}
+		// Any other invitations for this project are no longer actionable
+		return tx.Model(&models.ProjectGuideInvitation{}).
+			Where("project_id = ? AND id != ? AND status = ?", project.ID, invitation.ID, "pending").
+			Updates(map[string]interface{}{"status": "cancelled", "responded_at": now}).Error
+	})
+	if errors.Is(err, errProjectHasGuide) || errors.Is(err, errGuideAtCapacity) {
+		apierror.Abort(c, apierror.Conflict(err.Error()))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to accept invitation"))
+		return
+	}
+
+	if err := utils.CreateNotification(DB, invitation.Project.CompanyID, "A guide accepted your invitation for project: "+invitation.Project.Title); err != nil {
+		middleware.Log(c).Error("failed to send notification", "error", err)
+	}
+
+	c.JSON(http.StatusOK, respondInvitationResponse{
+		Message:   "Invitation accepted",
+		Status:    "accepted",
+		ProjectID: invitation.ProjectID,
+	})
+}
+
+type reassignGuideInput struct {
+	GuideID *uint `json:"guide_id"` // null removes the current guide
+}
+
+// ReassignProjectGuide - Admin assigns, replaces or removes the guide on a project
+func ReassignProjectGuide(c *gin.Context) {
+	var input reassignGuideInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	var project models.Project
+	if err := DB.First(&project, c.Param("id")).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Project not found"))
+		return
+	}
+
+	previousGuideID := project.GuideID
+	if input.GuideID != nil && previousGuideID != nil && *previousGuideID == *input.GuideID {
+		c.JSON(http.StatusOK, projectResponse{Message: "Guide already assigned", Project: project})
+		return
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if input.GuideID != nil {
+			guide, err := lockGuide(tx, *input.GuideID)
+			if err != nil {
+				return err
+			}
+			hasCapacity, err := guideHasCapacity(tx, guide)
+			if err != nil {
+				return err
+			}
+			if !hasCapacity {
+				return errGuideAtCapacity
+			}
+		}
+		return tx.Model(&project).Update("guide_id", input.GuideID).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		apierror.Abort(c, apierror.NotFound("Guide not found"))
+		return
+	}
+	if errors.Is(err, errGuideAtCapacity) {
+		apierror.Abort(c, apierror.Conflict(err.Error()))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to reassign guide"))
+		return
+	}
+	project.GuideID = input.GuideID
+
+	if previousGuideID != nil {
+		if err := utils.CreateNotification(DB, *previousGuideID, "You have been unassigned from the project: "+project.Title); err != nil {
+			middleware.Log(c).Error("failed to send notification", "error", err)
+		}
+	}
+	if input.GuideID != nil {
+		if err := utils.CreateNotification(DB, *input.GuideID, "You have been assigned to guide the project: "+project.Title); err != nil {
+			middleware.Log(c).Error("failed to send notification", "error", err)
+		}
+	}
+	if err := utils.CreateNotification(DB, project.CompanyID, "The guide for your project was changed by an admin: "+project.Title); err != nil {
+		middleware.Log(c).Error("failed to send notification", "error", err)
+	}
+
+	c.JSON(http.StatusOK, projectResponse{
+		Message: "Project guide updated successfully",
+		Project: project,
+	})
+}
+
+// guideProjectsResponse lists a guide's projects against how many they may take on
+type guideProjectsResponse struct {
+	Projects []guideProject `json:"projects"`
+	Count    int            `json:"count"`
+	Capacity int            `json:"capacity"`
+}
+
+type guideProject struct {
+	Project         models.Project `json:"project"`
+	SubmissionCount int64          `json:"submission_count"`
+	PendingReviews  int64          `json:"pending_reviews"`
+}
+
+// GetGuideProjects - Guide lists the projects assigned to them with submission counts
+func GetGuideProjects(c *gin.Context) {
+	guideID := c.GetUint("userID")
+
+	var projects []models.Project
+	if err := DB.Where("guide_id = ?", guideID).Order("deadline ASC").Find(&projects).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch assigned projects"))
+		return
+	}
+
+	var guide models.User
+	if err := DB.First(&guide, guideID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Guide not found"))
+		return
+	}
+
+	projectIDs := make([]uint, 0, len(projects))
+	for _, project := range projects {
+		projectIDs = append(projectIDs, project.ID)
+	}
+	type submissionCounts struct {
+		ProjectID uint
+		Total     int64
+		Pending   int64
+	}
+	var counts []submissionCounts
+	if len(projectIDs) > 0 {
+		if err := DB.Model(&models.Submission{}).
+			Select("project_id, COUNT(*) AS total, SUM(CASE WHEN review_status = '' OR review_status IS NULL OR review_status = ? THEN 1 ELSE 0 END) AS pending", "pending").
+			Where("project_id IN ?", projectIDs).
+			Group("project_id").
+			Scan(&counts).Error; err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to count submissions"))
+			return
+		}
+	}
+	countsBy := make(map[uint]submissionCounts, len(counts))
+	for _, row := range counts {
+		countsBy[row.ProjectID] = row
+	}
+
+	result := make([]guideProject, 0, len(projects))
+	for _, project := range projects {
+		result = append(result, guideProject{
+			Project:         project,
+			SubmissionCount: countsBy[project.ID].Total,
+			PendingReviews:  countsBy[project.ID].Pending,
+		})
+	}
+
+	c.JSON(http.StatusOK, guideProjectsResponse{
+		Projects: result,
+		Count:    len(result),
+		Capacity: guideCapacity(guide),
+	})
+}
+
+type guideCapacityResponse struct {
+	Message  string `json:"message"`
+	Capacity int    `json:"capacity"`
+}
+
+type guideCapacityInput struct {
+	Capacity int `json:"capacity" binding:"required,min=1,max=50"`
+}
+
+// UpdateGuideCapacity - Guide sets how many projects they are willing to guide at once
+func UpdateGuideCapacity(c *gin.Context) {
+	guideID := c.GetUint("userID")
+
+	var input guideCapacityInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	if err := DB.Model(&models.User{}).Where("id = ?", guideID).Update("guide_capacity", input.Capacity).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to update capacity"))
+		return
+	}
+
+	c.JSON(http.StatusOK, guideCapacityResponse{
+		Message:  "Capacity updated successfully",
+		Capacity: input.Capacity,
+	})
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"SkillBridge/server"
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// readinessTimeout bounds how long a readiness probe waits on the database
+const readinessTimeout = 2 * time.Second
+
+// schemaCurrent is set once every table and column exists. Migrations only
+// add to the schema, so there is no need to check again after that.
+var schemaCurrent atomic.Bool
+
+// healthResponse is the body of the health checks. Liveness has no checks.
+type healthResponse struct {
+	Status string        `json:"status"`
+	Checks *healthChecks `json:"checks,omitempty"`
+}
+
+type healthChecks struct {
+	Database   string   `json:"database"`
+	Migrations string   `json:"migrations"`
+	Pending    []string `json:"pending,omitempty"` // tables and columns still to be migrated
+}
+
+// Liveness - The process is up and able to serve requests; it does not touch the database
+func Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// Readiness - Ready to take traffic: the server is not shutting down, the
+// database answers and no migrations are pending
+func Readiness(c *gin.Context) {
+	if server.Draining() {
+		apierror.Abort(c, apierror.Unavailable("Service is shutting down"))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	checks := healthChecks{Database: "ok", Migrations: "ok"}
+	ready := true
+	if err := pingDB(ctx); err != nil {
+		middleware.Log(c).Error("readiness: database ping failed", "error", err)
+		checks.Database = "unreachable"
+		checks.Migrations = "unknown"
+		ready = false
+	} else if pending, err := pendingMigrations(ctx); err != nil {
+		middleware.Log(c).Error("readiness: failed to check migrations", "error", err)
+		checks.Migrations = "unknown"
+		ready = false
+	} else if len(pending) > 0 {
+		checks.Migrations = "pending"
+		checks.Pending = pending
+		ready = false
+	}
+
+	if !ready {
+		apierror.Abort(c, apierror.Unavailable("Service is not ready").WithDetails(checks))
+		return
+	}
+	c.JSON(http.StatusOK, healthResponse{Status: "ok", Checks: &checks})
+}
+
+func pingDB(ctx context.Context) error {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// pendingMigrations lists the tables and table.column pairs from models.Tables
+// that are missing from the database
+func pendingMigrations(ctx context.Context) ([]string, error) {
+	if schemaCurrent.Load() {
+		return nil, nil
+	}
+	tx := DB.WithContext(ctx)
+	migrator := tx.Migrator()
+	pending := []string{}
+	for _, table := range models.Tables {
+		stmt := &gorm.Statement{DB: tx}
+		if err := stmt.Parse(table); err != nil {
+			return nil, fmt.Errorf("parse %T: %w", table, err)
+		}
+		if !migrator.HasTable(table) {
+			pending = append(pending, stmt.Schema.Table)
+			continue
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			if !migrator.HasColumn(table, field.DBName) {
+				pending = append(pending, stmt.Schema.Table+"."+field.DBName)
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		// A timed-out lookup reads as a missing table; don't report it as one
+		return nil, err
+	}
+	if len(pending) == 0 {
+		schemaCurrent.Store(true)
+	}
+	return pending, nil
+}
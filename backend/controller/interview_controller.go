@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"SkillBridge/apierror"
 	"SkillBridge/models"
 	"fmt"
 	"net/http"
@@ -13,14 +14,14 @@ import (
 func GetInterviewResources(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	// Fetch user to get skills
 	var user models.User
 	if err := DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User not found"})
+		apierror.Abort(c, apierror.NotFound("User not found"))
 		return
 	}
 
@@ -166,12 +167,17 @@ func GetInterviewResources(c *gin.Context) {
 		}
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"videos":    videos,
-		"questions": questions,
+	c.JSON(http.StatusOK, interviewPrepResponse{
+		Videos:    videos,
+		Questions: questions,
 	})
 }
 
+type interviewPrepResponse struct {
+	Videos    []models.InterviewResource `json:"videos"`
+	Questions []models.InterviewResource `json:"questions"`
+}
+
 // SeedInterviewResources populates the database with initial data
 func SeedInterviewResources() {
 	var count int64
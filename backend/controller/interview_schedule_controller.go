@@ -0,0 +1,772 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"SkillBridge/utils"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxInterviewLength = 8 * time.Hour
+	// interviewReminderWindow is how far ahead of an interview reminders go out
+	interviewReminderWindow = 24 * time.Hour
+)
+
+var (
+	errSlotUnavailable     = errors.New("this slot is no longer available")
+	errSlotInPast          = errors.New("this slot has already started")
+	errSlotWrongJob        = errors.New("this slot is not for the job you applied to")
+	errCandidateConflict   = errors.New("you already have an interview at this time")
+	errAlreadyInterviewing = errors.New("an interview is already scheduled for this application")
+)
+
+// interviewSlotResponse adds the slot's times in its own time zone
+type interviewSlotResponse struct {
+	models.InterviewSlot
+	LocalStart string            `json:"local_start"`
+	LocalEnd   string            `json:"local_end"`
+	Booked     bool              `json:"booked"`
+	Interview  *models.Interview `json:"interview,omitempty"`
+}
+
+func newSlotResponse(slot models.InterviewSlot, interview *models.Interview) interviewSlotResponse {
+	loc, err := time.LoadLocation(slot.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return interviewSlotResponse{
+		InterviewSlot: slot,
+		LocalStart:    slot.StartsAt.In(loc).Format(time.RFC3339),
+		LocalEnd:      slot.EndsAt.In(loc).Format(time.RFC3339),
+		Booked:        interview != nil,
+		Interview:     interview,
+	}
+}
+
+// parseSlotTime accepts RFC 3339 times, or local times without an offset
+// which are interpreted in the slot's time zone
+func parseSlotTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04", value, loc)
+	if err != nil {
+		return t, fmt.Errorf("invalid time %q (use RFC 3339 or YYYY-MM-DDTHH:MM)", value)
+	}
+	return t.UTC(), nil
+}
+
+// interviewEligible reports whether an application has progressed far enough to
+// be interviewed: it must be past the first stage and not in a closed stage.
+func interviewEligible(application models.JobApplication) (bool, error) {
+	stages, err := jobPipeline(DB, application.JobListingID)
+	if err != nil {
+		return false, err
+	}
+	stage, ok := findStage(stages, application.Status)
+	return ok && stage.Kind == models.StageKindOpen && stage.Position > stages[0].Position, nil
+}
+
+// candidateHasConflict reports whether the candidate has another scheduled
+// interview overlapping the given time range
+func candidateHasConflict(tx *gorm.DB, candidateID uint, start, end time.Time, excludeID uint) bool {
+	var count int64
+	tx.Model(&models.Interview{}).
+		Joins("JOIN interview_slots ON interview_slots.id = interviews.slot_id").
+		Where("interviews.candidate_id = ? AND interviews.status = ? AND interviews.id <> ?", candidateID, "scheduled", excludeID).
+		Where("interview_slots.starts_at < ? AND interview_slots.ends_at > ?", end, start).
+		Count(&count)
+	return count > 0
+}
+
+// claimSlot locks a slot and checks it can be booked for the application
+func claimSlot(tx *gorm.DB, slotID uint, application models.JobApplication, excludeInterviewID uint) (models.InterviewSlot, error) {
+	var slot models.InterviewSlot
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&slot, slotID).Error; err != nil {
+		return slot, errSlotUnavailable
+	}
+	if slot.JobListingID != application.JobListingID {
+		return slot, errSlotWrongJob
+	}
+	if !slot.StartsAt.After(time.Now()) {
+		return slot, errSlotInPast
+	}
+
+	var taken int64
+	tx.Model(&models.Interview{}).
+		Where("slot_id = ? AND status = ? AND id <> ?", slot.ID, "scheduled", excludeInterviewID).
+		Count(&taken)
+	if taken > 0 {
+		return slot, errSlotUnavailable
+	}
+	if candidateHasConflict(tx, application.UserID, slot.StartsAt, slot.EndsAt, excludeInterviewID) {
+		return slot, errCandidateConflict
+	}
+	return slot, nil
+}
+
+// loadInterviewForUser fetches an interview the caller takes part in: the candidate,
+// a member of the company that owns the job, or an admin
+func loadInterviewForUser(c *gin.Context, id string) (models.Interview, bool) {
+	userID := c.GetUint("userID")
+	role := c.GetString("role")
+
+	var interview models.Interview
+	if err := DB.Preload("Slot", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Preload("Slot.JobListing.Company", selectPublicUser).Preload("Candidate", selectPublicUser).First(&interview, id).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Interview not found"))
+		return interview, false
+	}
+
+	job := interview.Slot.JobListing
+	allowed := role == "admin" ||
+		(role == "student" && interview.CandidateID == userID) ||
+		(role == "company" && companyOwns(userID, job.CompanyID, job.OrganisationID))
+	if !allowed {
+		apierror.Abort(c, apierror.Forbidden("You do not have access to this interview"))
+		return interview, false
+	}
+	return interview, true
+}
+
+// notifyInterviewParties sends a notification to everyone on the interview except the actor
+func notifyInterviewParties(interview models.Interview, actorID uint, message string) {
+	for _, recipient := range []uint{interview.CandidateID, interview.Slot.CreatedByID} {
+		if recipient == actorID {
+			continue
+		}
+		if err := utils.CreateNotification(DB, recipient, message); err != nil {
+			slog.Error("failed to send notification", "error", err)
+		}
+	}
+}
+
+// interviewEvent converts an interview into a calendar event
+func interviewEvent(interview models.Interview) utils.ICSEvent {
+	slot := interview.Slot
+	companyName := slot.JobListing.Company.CompanyName
+	if companyName == "" {
+		companyName = slot.JobListing.Company.Name
+	}
+
+	description := fmt.Sprintf("Interview with %s for %s.\nCandidate: %s", companyName, slot.JobListing.Title, interview.Candidate.Name)
+	if slot.Notes != "" {
+		description += "\n\n" + slot.Notes
+	}
+	location := slot.Location
+	if location == "" {
+		location = slot.MeetingURL
+	}
+
+	return utils.ICSEvent{
+		UID:         interview.UID,
+		Sequence:    interview.Sequence,
+		Start:       slot.StartsAt,
+		End:         slot.EndsAt,
+		Summary:     "Interview: " + slot.JobListing.Title,
+		Description: description,
+		Location:    location,
+		URL:         slot.MeetingURL,
+		Cancelled:   interview.Status == "cancelled",
+		Updated:     interview.UpdatedAt,
+	}
+}
+
+func interviewTimeText(slot models.InterviewSlot) string {
+	loc, err := time.LoadLocation(slot.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return slot.StartsAt.In(loc).Format("Mon 2 Jan 2006 15:04 MST")
+}
+
+type interviewSlotsInput struct {
+	TimeZone   string `json:"time_zone"`
+	Location   string `json:"location"`
+	MeetingURL string `json:"meeting_url" binding:"omitempty,httpurl"`
+	Notes      string `json:"notes"`
+	Slots      []struct {
+		StartsAt string `json:"starts_at" binding:"required"`
+		EndsAt   string `json:"ends_at" binding:"required"`
+	} `json:"slots" binding:"required,min=1,max=50,dive"`
+}
+
+// CreateInterviewSlots - Company offers interview slots for a job
+func CreateInterviewSlots(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var job models.JobListing
+	if err := DB.Scopes(ownedByCompany("job_listings", userID)).Where("id = ?", c.Param("id")).First(&job).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Job listing not found or unauthorized"))
+		return
+	}
+
+	var input interviewSlotsInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if input.TimeZone == "" {
+		input.TimeZone = "UTC"
+	}
+	loc, err := time.LoadLocation(input.TimeZone)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Unknown time zone: "+input.TimeZone))
+		return
+	}
+
+	slots := make([]models.InterviewSlot, 0, len(input.Slots))
+	for _, s := range input.Slots {
+		start, err := parseSlotTime(s.StartsAt, loc)
+		if err != nil {
+			apierror.Abort(c, apierror.BadRequest(err.Error()))
+			return
+		}
+		end, err := parseSlotTime(s.EndsAt, loc)
+		if err != nil {
+			apierror.Abort(c, apierror.BadRequest(err.Error()))
+			return
+		}
+		if !end.After(start) || end.Sub(start) > maxInterviewLength {
+			apierror.Abort(c, apierror.BadRequest("Slots must end after they start and last at most 8 hours"))
+			return
+		}
+		if !start.After(time.Now()) {
+			apierror.Abort(c, apierror.BadRequest("Slots must be in the future"))
+			return
+		}
+		for _, other := range slots {
+			if start.Before(other.EndsAt) && end.After(other.StartsAt) {
+				apierror.Abort(c, apierror.BadRequest("Slots in the request overlap each other"))
+				return
+			}
+		}
+		slots = append(slots, models.InterviewSlot{
+			JobListingID: job.ID,
+			CreatedByID:  userID,
+			StartsAt:     start,
+			EndsAt:       end,
+			TimeZone:     loc.String(),
+			Location:     input.Location,
+			MeetingURL:   input.MeetingURL,
+			Notes:        input.Notes,
+		})
+	}
+
+	// The interviewer cannot be in two slots at once, across all their jobs
+	for _, slot := range slots {
+		var overlapping int64
+		DB.Model(&models.InterviewSlot{}).
+			Where("created_by_id = ? AND starts_at < ? AND ends_at > ?", userID, slot.EndsAt, slot.StartsAt).
+			Count(&overlapping)
+		if overlapping > 0 {
+			apierror.Abort(c, apierror.Conflict("You already offer a slot overlapping "+interviewTimeText(slot)))
+			return
+		}
+	}
+
+	if err := DB.Create(&slots).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to create interview slots"))
+		return
+	}
+
+	response := make([]interviewSlotResponse, len(slots))
+	for i, slot := range slots {
+		response[i] = newSlotResponse(slot, nil)
+	}
+	c.JSON(http.StatusCreated, createSlotsResponse{
+		Message: fmt.Sprintf("%d interview slot(s) created", len(slots)),
+		Slots:   response,
+	})
+}
+
+type createSlotsResponse struct {
+	Message string                  `json:"message"`
+	Slots   []interviewSlotResponse `json:"slots"`
+}
+
+// interviewSlotsResponse lists slots; ApplicationID is set when they are offered to a candidate
+type interviewSlotsResponse struct {
+	ApplicationID uint                    `json:"application_id,omitempty"`
+	Slots         []interviewSlotResponse `json:"slots"`
+	Count         int                     `json:"count"`
+}
+
+// GetJobInterviewSlots - Company lists a job's slots and who booked them
+func GetJobInterviewSlots(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var job models.JobListing
+	if err := DB.Scopes(ownedByCompany("job_listings", userID)).Where("id = ?", c.Param("id")).First(&job).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Job listing not found or unauthorized"))
+		return
+	}
+
+	query := DB.Where("job_listing_id = ?", job.ID)
+	if c.Query("include_past") != "true" {
+		query = query.Where("ends_at > ?", time.Now())
+	}
+	var slots []models.InterviewSlot
+	if err := query.Order("starts_at ASC").Find(&slots).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch interview slots"))
+		return
+	}
+
+	slotIDs := make([]uint, len(slots))
+	for i, slot := range slots {
+		slotIDs[i] = slot.ID
+	}
+	var interviews []models.Interview
+	DB.Preload("Candidate", selectPublicUser).Where("slot_id IN ? AND status = ?", slotIDs, "scheduled").Find(&interviews)
+	bySlot := map[uint]*models.Interview{}
+	for i := range interviews {
+		bySlot[interviews[i].SlotID] = &interviews[i]
+	}
+
+	response := make([]interviewSlotResponse, len(slots))
+	for i, slot := range slots {
+		response[i] = newSlotResponse(slot, bySlot[slot.ID])
+	}
+	c.JSON(http.StatusOK, interviewSlotsResponse{
+		Slots: response,
+		Count: len(response),
+	})
+}
+
+type deleteSlotResponse struct {
+	Message             string `json:"message"`
+	CancelledInterviews int    `json:"cancelled_interviews"`
+}
+
+// DeleteInterviewSlot - Company withdraws a slot, cancelling any interview booked in it
+func DeleteInterviewSlot(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var slot models.InterviewSlot
+	if err := DB.Preload("JobListing").First(&slot, c.Param("id")).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Interview slot not found"))
+		return
+	}
+	if !companyOwns(userID, slot.JobListing.CompanyID, slot.JobListing.OrganisationID) {
+		apierror.Abort(c, apierror.Forbidden("Unauthorized"))
+		return
+	}
+
+	var cancelled []models.Interview
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("slot_id = ? AND status = ?", slot.ID, "scheduled").Find(&cancelled).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		if err := tx.Model(&models.Interview{}).
+			Where("slot_id = ? AND status = ?", slot.ID, "scheduled").
+			Updates(map[string]interface{}{
+				"status":          "cancelled",
+				"cancelled_by_id": userID,
+				"cancel_reason":   "The interview slot was withdrawn",
+				"cancelled_at":    now,
+				"sequence":        gorm.Expr("sequence + 1"),
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&slot).Error
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to delete interview slot"))
+		return
+	}
+
+	for _, interview := range cancelled {
+		message := "Your interview for " + slot.JobListing.Title + " on " + interviewTimeText(slot) + " was cancelled by the company. Please pick another slot."
+		if err := utils.CreateNotification(DB, interview.CandidateID, message); err != nil {
+			middleware.Log(c).Error("failed to send notification", "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, deleteSlotResponse{
+		Message:             "Interview slot deleted successfully",
+		CancelledInterviews: len(cancelled),
+	})
+}
+
+// GetAvailableInterviewSlots - Candidate lists open slots for one of their applications
+func GetAvailableInterviewSlots(c *gin.Context) {
+	studentID := c.GetUint("userID")
+
+	var application models.JobApplication
+	if err := DB.Where("id = ? AND user_id = ?", c.Param("id"), studentID).First(&application).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Application not found"))
+		return
+	}
+	eligible, err := interviewEligible(application)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch pipeline"))
+		return
+	}
+	if !eligible {
+		apierror.Abort(c, apierror.Forbidden("Interviews open once your application is shortlisted"))
+		return
+	}
+
+	var slots []models.InterviewSlot
+	if err := DB.Where("job_listing_id = ? AND starts_at > ?", application.JobListingID, time.Now()).
+		Where("id NOT IN (?)", DB.Model(&models.Interview{}).Select("slot_id").Where("status = ?", "scheduled")).
+		Order("starts_at ASC").
+		Find(&slots).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch interview slots"))
+		return
+	}
+
+	response := make([]interviewSlotResponse, len(slots))
+	for i, slot := range slots {
+		response[i] = newSlotResponse(slot, nil)
+	}
+	c.JSON(http.StatusOK, interviewSlotsResponse{
+		ApplicationID: application.ID,
+		Slots:         response,
+		Count:         len(response),
+	})
+}
+
+// interviewResponse is returned when an interview is booked or moved
+type interviewResponse struct {
+	Message   string                `json:"message"`
+	Interview models.Interview      `json:"interview"`
+	Slot      interviewSlotResponse `json:"slot"`
+}
+
+type bookInterviewInput struct {
+	ApplicationID uint `json:"application_id" binding:"required"`
+	SlotID        uint `json:"slot_id" binding:"required"`
+}
+
+// BookInterview - Candidate books an open slot for a shortlisted application
+func BookInterview(c *gin.Context) {
+	studentID := c.GetUint("userID")
+
+	var input bookInterviewInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	var application models.JobApplication
+	if err := DB.Where("id = ? AND user_id = ?", input.ApplicationID, studentID).First(&application).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Application not found"))
+		return
+	}
+	eligible, err := interviewEligible(application)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch pipeline"))
+		return
+	}
+	if !eligible {
+		apierror.Abort(c, apierror.Forbidden("Interviews open once your application is shortlisted"))
+		return
+	}
+
+	interview := models.Interview{
+		UID:              utils.NewICSUID(),
+		SlotID:           input.SlotID,
+		JobApplicationID: application.ID,
+		CandidateID:      studentID,
+		Status:           "scheduled",
+	}
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		// Locking the application stops two bookings for it on different slots
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.JobApplication{}, application.ID).Error; err != nil {
+			return err
+		}
+		var existing int64
+		if err := tx.Model(&models.Interview{}).Where("job_application_id = ? AND status = ?", application.ID, "scheduled").Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return errAlreadyInterviewing
+		}
+		if _, err := claimSlot(tx, input.SlotID, application, 0); err != nil {
+			return err
+		}
+		return tx.Create(&interview).Error
+	})
+	if err != nil {
+		if errors.Is(err, errSlotUnavailable) || errors.Is(err, errCandidateConflict) || errors.Is(err, errAlreadyInterviewing) {
+			apierror.Abort(c, apierror.Conflict(err.Error()))
+			return
+		}
+		if errors.Is(err, errSlotInPast) || errors.Is(err, errSlotWrongJob) {
+			apierror.Abort(c, apierror.BadRequest(err.Error()))
+			return
+		}
+		apierror.Abort(c, apierror.Internal("Failed to book interview"))
+		return
+	}
+
+	DB.Preload("Slot.JobListing.Company", selectPublicUser).Preload("Candidate", selectPublicUser).First(&interview, interview.ID)
+	notifyInterviewParties(interview, studentID,
+		interview.Candidate.Name+" booked an interview for "+interview.Slot.JobListing.Title+" on "+interviewTimeText(interview.Slot))
+
+	c.JSON(http.StatusCreated, interviewResponse{
+		Message:   "Interview booked successfully",
+		Interview: interview,
+		Slot:      newSlotResponse(interview.Slot, nil),
+	})
+}
+
+type rescheduleInterviewInput struct {
+	SlotID uint   `json:"slot_id" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+// RescheduleInterview - Candidate or company moves an interview to another open slot
+func RescheduleInterview(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	interview, ok := loadInterviewForUser(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if interview.Status != "scheduled" {
+		apierror.Abort(c, apierror.Conflict("Only scheduled interviews can be rescheduled"))
+		return
+	}
+
+	var input rescheduleInterviewInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if input.SlotID == interview.SlotID {
+		apierror.Abort(c, apierror.BadRequest("The interview is already in this slot"))
+		return
+	}
+
+	var application models.JobApplication
+	if err := DB.First(&application, interview.JobApplicationID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Application not found"))
+		return
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := claimSlot(tx, input.SlotID, application, interview.ID); err != nil {
+			return err
+		}
+		return tx.Model(&interview).Updates(map[string]interface{}{
+			"slot_id":          input.SlotID,
+			"sequence":         interview.Sequence + 1,
+			"reminder_sent_at": nil,
+		}).Error
+	})
+	if err != nil {
+		if errors.Is(err, errSlotUnavailable) || errors.Is(err, errCandidateConflict) {
+			apierror.Abort(c, apierror.Conflict(err.Error()))
+			return
+		}
+		if errors.Is(err, errSlotInPast) || errors.Is(err, errSlotWrongJob) {
+			apierror.Abort(c, apierror.BadRequest(err.Error()))
+			return
+		}
+		apierror.Abort(c, apierror.Internal("Failed to reschedule interview"))
+		return
+	}
+
+	DB.Preload("Slot.JobListing.Company", selectPublicUser).Preload("Candidate", selectPublicUser).First(&interview, interview.ID)
+	message := "Your interview for " + interview.Slot.JobListing.Title + " was moved to " + interviewTimeText(interview.Slot)
+	if input.Reason != "" {
+		message += ": " + input.Reason
+	}
+	notifyInterviewParties(interview, userID, message)
+
+	c.JSON(http.StatusOK, interviewResponse{
+		Message:   "Interview rescheduled successfully",
+		Interview: interview,
+		Slot:      newSlotResponse(interview.Slot, nil),
+	})
+}
+
+type cancelInterviewInput struct {
+	Reason string `json:"reason"`
+}
+
+// CancelInterview - Candidate or company cancels a scheduled interview
+func CancelInterview(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	interview, ok := loadInterviewForUser(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	if interview.Status != "scheduled" {
+		apierror.Abort(c, apierror.Conflict("Interview is not scheduled"))
+		return
+	}
+
+	var input cancelInterviewInput
+	c.ShouldBindJSON(&input) // optional body
+
+	now := time.Now()
+	if err := DB.Model(&interview).Updates(map[string]interface{}{
+		"status":          "cancelled",
+		"cancelled_by_id": userID,
+		"cancel_reason":   input.Reason,
+		"cancelled_at":    now,
+		"sequence":        interview.Sequence + 1,
+	}).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to cancel interview"))
+		return
+	}
+
+	message := "The interview for " + interview.Slot.JobListing.Title + " on " + interviewTimeText(interview.Slot) + " was cancelled"
+	if input.Reason != "" {
+		message += ": " + input.Reason
+	}
+	notifyInterviewParties(interview, userID, message)
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Interview cancelled successfully"})
+}
+
+// interviewsForUser builds the query for interviews the user takes part in
+func interviewsForUser(userID uint, role string) *gorm.DB {
+	query := DB.Model(&models.Interview{}).
+		Joins("JOIN interview_slots ON interview_slots.id = interviews.slot_id").
+		Preload("Slot", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped() // withdrawn slots still describe their cancelled interviews
+		}).
+		Preload("Slot.JobListing.Company", selectPublicUser).
+		Preload("Candidate", selectPublicUser)
+	if role == "company" {
+		return query.Joins("JOIN job_listings ON job_listings.id = interview_slots.job_listing_id").
+			Scopes(ownedByCompany("job_listings", userID))
+	}
+	return query.Where("interviews.candidate_id = ?", userID)
+}
+
+// GetMyInterviews - Candidate or company lists their interviews, upcoming first
+func GetMyInterviews(c *gin.Context) {
+	query := interviewsForUser(c.GetUint("userID"), c.GetString("role"))
+	if c.Query("include_past") != "true" {
+		query = query.Where("interview_slots.ends_at > ?", time.Now())
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("interviews.status = ?", status)
+	}
+
+	var interviews []models.Interview
+	if err := query.Order("interview_slots.starts_at ASC").Find(&interviews).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch interviews"))
+		return
+	}
+
+	c.JSON(http.StatusOK, interviewsResponse{
+		Interviews: interviews,
+		Count:      len(interviews),
+	})
+}
+
+type interviewsResponse struct {
+	Interviews []models.Interview `json:"interviews"`
+	Count      int                `json:"count"`
+}
+
+type calendarFeedResponse struct {
+	FeedPath string `json:"feed_path"`
+}
+
+// GetInterviewICS - Download an interview as an iCalendar file
+func GetInterviewICS(c *gin.Context) {
+	interview, ok := loadInterviewForUser(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("interview-%d.ics", interview.ID)))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", utils.BuildICS("", "PUBLISH", []utils.ICSEvent{interviewEvent(interview)}))
+}
+
+// GetCalendarFeedURL - Returns the user's private interview calendar subscription URL.
+// Pass rotate=true to invalidate the previous URL.
+func GetCalendarFeedURL(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var feed models.CalendarFeed
+	err := DB.Where("user_id = ?", userID).First(&feed).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		feed = models.CalendarFeed{UserID: userID, Token: utils.RandomToken(24)}
+		err = DB.Create(&feed).Error
+	case err == nil && c.Query("rotate") == "true":
+		err = DB.Model(&feed).Update("token", utils.RandomToken(24)).Error
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch calendar feed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, calendarFeedResponse{
+		FeedPath: "/api/calendar/" + feed.Token + "/interviews.ics",
+	})
+}
+
+// GetCalendarFeed - Public iCalendar feed of a user's interviews, authorised by its secret token
+func GetCalendarFeed(c *gin.Context) {
+	var feed models.CalendarFeed
+	if err := DB.Where("token = ?", c.Param("token")).First(&feed).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Calendar not found"))
+		return
+	}
+	var user models.User
+	if err := DB.First(&user, feed.UserID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Calendar not found"))
+		return
+	}
+
+	// Recent cancellations stay in the feed so subscribed calendars remove them
+	var interviews []models.Interview
+	if err := interviewsForUser(user.ID, user.Role).
+		Where("interview_slots.ends_at > ?", time.Now().AddDate(0, 0, -30)).
+		Order("interview_slots.starts_at ASC").
+		Find(&interviews).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to build calendar"))
+		return
+	}
+
+	events := make([]utils.ICSEvent, len(interviews))
+	for i, interview := range interviews {
+		events[i] = interviewEvent(interview)
+	}
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", utils.BuildICS("SkillBridge interviews", "PUBLISH", events))
+}
+
+// SendInterviewReminders notifies both parties of interviews starting within the
+// reminder window. Each interview is reminded once per schedule.
+func SendInterviewReminders() error {
+	var interviews []models.Interview
+	if err := DB.Joins("JOIN interview_slots ON interview_slots.id = interviews.slot_id").
+		Preload("Slot.JobListing").
+		Where("interviews.status = ? AND interviews.reminder_sent_at IS NULL", "scheduled").
+		Where("interview_slots.starts_at > ? AND interview_slots.starts_at <= ?", time.Now(), time.Now().Add(interviewReminderWindow)).
+		Find(&interviews).Error; err != nil {
+		return fmt.Errorf("loading interview reminders: %w", err)
+	}
+
+	for _, interview := range interviews {
+		// Claim the reminder first so a concurrent run cannot send it twice
+		result := DB.Model(&models.Interview{}).
+			Where("id = ? AND reminder_sent_at IS NULL", interview.ID).
+			Update("reminder_sent_at", time.Now())
+		if result.Error != nil || result.RowsAffected == 0 {
+			continue
+		}
+		notifyInterviewParties(interview, 0,
+			"Reminder: interview for "+interview.Slot.JobListing.Title+" on "+interviewTimeText(interview.Slot))
+	}
+	return nil
+}
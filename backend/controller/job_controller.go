@@ -1,13 +1,18 @@
 package controller
 
 import (
+	"SkillBridge/apierror"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"SkillBridge/middleware"
 	"SkillBridge/models"
 )
 
@@ -20,28 +25,22 @@ func InitJobDB(database *gorm.DB) {
 
 // CreateJobListing - Company posts a new job
 func CreateJobListing(c *gin.Context) {
-	fmt.Printf("DEBUG CreateJobListing: Starting\n")
-	
 	var req models.CreateJobListingRequest
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	// Get company ID from JWT token
 	companyID, exists := c.Get("userID")
-	fmt.Printf("DEBUG CreateJobListing: Looking for 'userID', exists: %v, value: %v, type: %T\n", exists, companyID, companyID)
-	
 	if !exists {
-		fmt.Printf("DEBUG CreateJobListing: userID not found in context\n")
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	// Parse deadline
 	deadline, err := time.Parse("2006-01-02", req.ApplicationDeadline)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deadline format (use YYYY-MM-DD)"})
+		apierror.Abort(c, apierror.BadRequest("Invalid deadline format (use YYYY-MM-DD)"))
 		return
 	}
 
@@ -51,6 +50,7 @@ func CreateJobListing(c *gin.Context) {
 
 	jobListing := models.JobListing{
 		CompanyID:           companyID.(uint),
+		OrganisationID:      organisationIDFor(companyID.(uint)),
 		Title:               req.Title,
 		Description:         req.Description,
 		Category:            req.Category,
@@ -68,35 +68,45 @@ func CreateJobListing(c *gin.Context) {
 	}
 
 	if result := db.Create(&jobListing); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job listing"})
+		apierror.Abort(c, apierror.Internal("Failed to create job listing"))
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Job listing created successfully",
-		"job":     jobListing,
+	c.JSON(http.StatusCreated, jobListingResponse{
+		Message: "Job listing created successfully",
+		Job:     jobListing,
 	})
 }
 
+type jobListingResponse struct {
+	Message string            `json:"message"`
+	Job     models.JobListing `json:"job"`
+}
+
+type jobListingsResponse struct {
+	Total int                 `json:"total"`
+	Jobs  []models.JobListing `json:"jobs"`
+}
+
 // GetCompanyJobListings - Get all jobs posted by a company
 func GetCompanyJobListings(c *gin.Context) {
 	companyID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	var jobListings []models.JobListing
-	if result := db.Where("company_id = ?", companyID).
+	if result := db.Scopes(ownedByCompany("job_listings", companyID.(uint))).
 		Order("created_at DESC").
 		Find(&jobListings); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job listings"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch job listings"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"total": len(jobListings),
-		"jobs":  jobListings,
+	c.JSON(http.StatusOK, jobListingsResponse{
+		Total: len(jobListings),
+		Jobs:  jobListings,
 	})
 }
 
@@ -106,7 +116,7 @@ func GetJobListingByID(c *gin.Context) {
 	
 	var jobListing models.JobListing
 	if result := db.Preload("Company").Where("id = ?", jobID).First(&jobListing); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Job listing not found"})
+		apierror.Abort(c, apierror.NotFound("Job listing not found"))
 		return
 	}
 
@@ -118,24 +128,20 @@ func UpdateJobListing(c *gin.Context) {
 	jobID := c.Param("id")
 	companyID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	var jobListing models.JobListing
-	if result := db.Where("id = ? AND company_id = ?", jobID, companyID).First(&jobListing); result.Error != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Job listing not found or unauthorized"})
+	if result := db.Scopes(ownedByCompany("job_listings", companyID.(uint))).Where("id = ?", jobID).First(&jobListing); result.Error != nil {
+		apierror.Abort(c, apierror.Forbidden("Job listing not found or unauthorized"))
 		return
 	}
 
 	var req models.UpdateJobListingRequest
-	if err := c.BindJSON(&req); err != nil {
-		fmt.Printf("DEBUG UpdateJobListing: BindJSON error: %v\n", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+	if !bindJSON(c, &req) {
 		return
 	}
-	
-	fmt.Printf("DEBUG UpdateJobListing: Received request: %+v\n", req)
 
 	// Update fields if provided
 	updates := map[string]interface{}{}
@@ -176,13 +182,14 @@ func UpdateJobListing(c *gin.Context) {
 	updates["updated_at"] = time.Now()
 
 	if result := db.Model(&jobListing).Updates(updates); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job listing"})
+		apierror.Abort(c, apierror.Internal("Failed to update job listing"))
 		return
 	}
+	invalidateJobFits(jobListing.ID)
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Job listing updated successfully",
-		"job":     jobListing,
+	c.JSON(http.StatusOK, jobListingResponse{
+		Message: "Job listing updated successfully",
+		Job:     jobListing,
 	})
 }
 
@@ -191,16 +198,16 @@ func DeleteJobListing(c *gin.Context) {
 	jobID := c.Param("id")
 	companyID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
-	if result := db.Where("id = ? AND company_id = ?", jobID, companyID).Delete(&models.JobListing{}); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job listing"})
+	if result := db.Scopes(ownedByCompany("job_listings", companyID.(uint))).Where("id = ?", jobID).Delete(&models.JobListing{}); result.Error != nil {
+		apierror.Abort(c, apierror.Internal("Failed to delete job listing"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Job listing deleted successfully"})
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Job listing deleted successfully"})
 }
 
 // GetJobApplications - Get all applications for a job
@@ -208,14 +215,14 @@ func GetJobApplications(c *gin.Context) {
 	jobID := c.Param("id")
 	companyID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	// Verify company owns this job
 	var jobListing models.JobListing
-	if result := db.Where("id = ? AND company_id = ?", jobID, companyID).First(&jobListing); result.Error != nil {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized"})
+	if result := db.Scopes(ownedByCompany("job_listings", companyID.(uint))).Where("id = ?", jobID).First(&jobListing); result.Error != nil {
+		apierror.Abort(c, apierror.Forbidden("Unauthorized"))
 		return
 	}
 
@@ -230,10 +237,33 @@ func GetJobApplications(c *gin.Context) {
 	}
 
 	if result := query.Order("applied_at DESC").Find(&applications); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch applications"))
 		return
 	}
 
+	// Fit scores: ?sort=fit orders best matches first, ?min_fit= hides weaker ones
+	fits := applicationFits(applications)
+	if minFit, err := strconv.ParseFloat(c.Query("min_fit"), 64); err == nil {
+		filtered := applications[:0]
+		for _, app := range applications {
+			if fit := fits[app.ID]; fit != nil && fit.Score >= minFit {
+				filtered = append(filtered, app)
+			}
+		}
+		applications = filtered
+	}
+	if c.Query("sort") == "fit" {
+		score := func(app models.JobApplication) float64 {
+			if fit := fits[app.ID]; fit != nil {
+				return fit.Score
+			}
+			return -1
+		}
+		sort.SliceStable(applications, func(i, j int) bool {
+			return score(applications[i]) > score(applications[j])
+		})
+	}
+
 	// Format response
 	applicants := make([]models.JobApplicationResponse, 0) // Initialize as empty array, not nil
 	for _, app := range applications {
@@ -242,130 +272,179 @@ func GetJobApplications(c *gin.Context) {
 			User:        app.User,
 			Status:      app.Status,
 			Resume:      app.Resume,
+			ResumeFileID: app.ResumeFileID,
+			ResumeVersionID: app.ResumeVersionID,
 			CoverLetter: app.CoverLetter,
 			AppliedAt:   app.AppliedAt,
 			UpdatedAt:   app.UpdatedAt,
+			StageChangedAt:  app.StageChangedAt,
+			RejectionReason: app.RejectionReason,
+			Fit:         fits[app.ID],
 		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"job_id":     jobID,
-		"total":      len(applicants),
-		"applicants": applicants,
+	stages, err := jobPipeline(db, jobListing.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch pipeline"))
+		return
+	}
+
+	c.JSON(http.StatusOK, jobApplicantsResponse{
+		JobID:      jobID,
+		Total:      len(applicants),
+		Applicants: applicants,
+		Stages:     stages,
 	})
 }
 
+type jobApplicantsResponse struct {
+	JobID      string                          `json:"job_id"`
+	Total      int                             `json:"total"`
+	Applicants []models.JobApplicationResponse `json:"applicants"`
+	Stages     []models.PipelineStage          `json:"stages"`
+}
+
+type applicationStatusResponse struct {
+	Message        string     `json:"message"`
+	Status         string     `json:"status"`
+	StageChangedAt *time.Time `json:"stage_changed_at"`
+}
+
 // UpdateApplicationStatus - Update application status (Shortlist/Reject/Accept)
 func UpdateApplicationStatus(c *gin.Context) {
 	appID := c.Param("id")
 	companyID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	var req models.UpdateApplicationStatusRequest
-	if err := c.BindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
-		return
-	}
-
-	// Validate status
-	validStatuses := []string{"Applied", "Shortlisted", "Rejected", "Accepted"}
-	isValid := false
-	for _, valid := range validStatuses {
-		if req.Status == valid {
-			isValid = true
-			break
-		}
-	}
-	if !isValid {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Must be: Applied, Shortlisted, Rejected, or Accepted"})
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	var application models.JobApplication
 	if result := db.Preload("JobListing").Where("id = ?", appID).First(&application); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		apierror.Abort(c, apierror.NotFound("Application not found"))
 		return
 	}
 
 	// Verify company owns the job
-	if application.JobListing.CompanyID != companyID.(uint) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized"})
+	if !companyOwns(companyID.(uint), application.JobListing.CompanyID, application.JobListing.OrganisationID) {
+		apierror.Abort(c, apierror.Forbidden("Unauthorized"))
 		return
 	}
 
-	// Update status
-	if result := db.Model(&application).Update("status", req.Status).Update("updated_at", time.Now()); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application status"})
+	// Update status, validated against the pipeline as it is once the application is locked
+	var (
+		stage   models.PipelineStage
+		changed bool
+	)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var locked models.JobApplication
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").First(&locked, application.ID).Error; err != nil {
+			return err
+		}
+		application.Status = locked.Status
+
+		var err error
+		if stage, err = targetStage(tx, application.JobListingID, req.Status); err != nil {
+			return err
+		}
+		changed, err = moveApplication(tx, &application, stage, req.Reason, companyID.(uint))
+		return err
+	})
+	var unknown *unknownStageError
+	if errors.As(err, &unknown) {
+		apierror.Abort(c, apierror.BadRequest("Invalid status. "+unknown.Error()))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to update application status"))
 		return
 	}
 
-	// TODO: Send notification to user about status change
+	if changed {
+		notifyStageChange(application, application.JobListing, stage)
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Application status updated successfully",
-		"status":  req.Status,
+	c.JSON(http.StatusOK, applicationStatusResponse{
+		Message:        "Application status updated successfully",
+		Status:         stage.Name,
+		StageChangedAt: application.StageChangedAt,
 	})
 }
 
+// applicationStats counts a company's applications overall and per pipeline stage
+type applicationStats struct {
+	TotalApplications int          `json:"totalApplications"`
+	ShortlistedCount  int          `json:"shortlistedCount"`
+	RejectedCount     int          `json:"rejectedCount"`
+	AcceptedCount     int          `json:"acceptedCount"`
+	PendingCount      int          `json:"pendingCount"`
+	Stages            []stageCount `json:"stages"`
+}
+
 // GetApplicationStats - Get stats for company's job postings
 func GetApplicationStats(c *gin.Context) {
 	companyID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	jobID := c.Query("job_id")
 
-	type Stats struct {
-		TotalApplications int `json:"totalApplications"`
-		ShortlistedCount  int `json:"shortlistedCount"`
-		RejectedCount     int `json:"rejectedCount"`
-		AcceptedCount     int `json:"acceptedCount"`
-		PendingCount      int `json:"pendingCount"`
+	base := func() *gorm.DB {
+		query := db.Table("job_applications").
+			Joins("JOIN job_listings ON job_applications.job_listing_id = job_listings.id").
+			Where("job_applications.deleted_at IS NULL").
+			Scopes(ownedByCompany("job_listings", companyID.(uint)))
+		if jobID != "" {
+			query = query.Where("job_listings.id = ?", jobID)
+		}
+		return query
 	}
 
-	var stats Stats
-	var totalCount int64
-	var shortlistCount int64
-	var rejectCount int64
-	var acceptCount int64
-	var pendingCount int64
-
-	query := db.Table("job_applications").
-		Joins("JOIN job_listings ON job_applications.job_listing_id = job_listings.id").
-		Where("job_listings.company_id = ?", companyID)
-
+	// Per-stage funnel follows the job's pipeline, or the default one across all jobs
+	stages := models.DefaultPipelineStages(0)
 	if jobID != "" {
-		query = query.Where("job_listings.id = ?", jobID)
-	}
-
-	query.Count(&totalCount)
-	db.Table("job_applications").
-		Joins("JOIN job_listings ON job_applications.job_listing_id = job_listings.id").
-		Where("job_listings.company_id = ? AND job_applications.status = ?", companyID, "Shortlisted").
-		Count(&shortlistCount)
-	db.Table("job_applications").
-		Joins("JOIN job_listings ON job_applications.job_listing_id = job_listings.id").
-		Where("job_listings.company_id = ? AND job_applications.status = ?", companyID, "Rejected").
-		Count(&rejectCount)
-	db.Table("job_applications").
-		Joins("JOIN job_listings ON job_applications.job_listing_id = job_listings.id").
-		Where("job_listings.company_id = ? AND job_applications.status = ?", companyID, "Accepted").
-		Count(&acceptCount)
-	db.Table("job_applications").
-		Joins("JOIN job_listings ON job_applications.job_listing_id = job_listings.id").
-		Where("job_listings.company_id = ? AND job_applications.status = ?", companyID, "Applied").
-		Count(&pendingCount)
-
-	stats.TotalApplications = int(totalCount)
-	stats.ShortlistedCount = int(shortlistCount)
-	stats.RejectedCount = int(rejectCount)
-	stats.AcceptedCount = int(acceptCount)
-	stats.PendingCount = int(pendingCount)
+		var job models.JobListing
+		if result := db.Scopes(ownedByCompany("job_listings", companyID.(uint))).Where("id = ?", jobID).First(&job); result.Error != nil {
+			apierror.Abort(c, apierror.NotFound("Job listing not found or unauthorized"))
+			return
+		}
+		pipeline, err := jobPipeline(db, job.ID)
+		if err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to fetch pipeline"))
+			return
+		}
+		stages = pipeline
+	}
+
+	funnel, err := stageFunnel(base, stages)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch application stats"))
+		return
+	}
+
+	var stats applicationStats
+	stats.Stages = funnel
+	for _, row := range funnel {
+		count := int(row.Current)
+		stats.TotalApplications += count
+		switch row.Stage {
+		case "Shortlisted":
+			stats.ShortlistedCount = count
+		case "Rejected":
+			stats.RejectedCount = count
+		case "Accepted":
+			stats.AcceptedCount = count
+		case "Applied":
+			stats.PendingCount = count
+		}
+	}
 
 	c.JSON(http.StatusOK, stats)
 }
@@ -375,22 +454,26 @@ func GetApplicationDetail(c *gin.Context) {
 	appID := c.Param("id")
 	companyID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
 	var application models.JobApplication
-	if result := db.Preload("User").Preload("JobListing").Where("id = ?", appID).First(&application); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+	if result := db.Preload("User").Preload("JobListing").Preload("ResumeFile").Preload("ResumeVersion").Preload("StageHistory", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC, id ASC")
+	}).Where("id = ?", appID).First(&application); result.Error != nil {
+		apierror.Abort(c, apierror.NotFound("Application not found"))
 		return
 	}
 
 	// Verify company owns the job
-	if application.JobListing.CompanyID != companyID.(uint) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Unauthorized"})
+	if !companyOwns(companyID.(uint), application.JobListing.CompanyID, application.JobListing.OrganisationID) {
+		apierror.Abort(c, apierror.Forbidden("Unauthorized"))
 		return
 	}
 
+	application.Fit = applicationFits([]models.JobApplication{application})[application.ID]
+
 	c.JSON(http.StatusOK, application)
 }
 
@@ -399,98 +482,141 @@ func ApplyToJob(c *gin.Context) {
 	jobIDStr := c.Param("id")
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 	studentID := userID.(uint)
 
 	var req models.ApplyToJobRequest
-	c.BindJSON(&req) // optional body
+	c.ShouldBindJSON(&req) // optional body
 
 	var job models.JobListing
 	if result := db.Where("id = ? AND is_active = ?", jobIDStr, true).First(&job); result.Error != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found or no longer active"})
+		apierror.Abort(c, apierror.NotFound("Job not found or no longer active"))
 		return
 	}
 	if job.ApplicationDeadline.Before(time.Now()) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Application deadline has passed"})
+		apierror.Abort(c, apierror.BadRequest("Application deadline has passed"))
 		return
 	}
 
 	var existing models.JobApplication
 	if result := db.Where("job_listing_id = ? AND user_id = ?", job.ID, studentID).First(&existing); result.Error == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "You have already applied to this job"})
+		apierror.Abort(c, apierror.Conflict("You have already applied to this job"))
+		return
+	}
+
+	if req.ResumeFileID != nil {
+		var resume models.ResumeFile
+		if result := db.Where("id = ? AND user_id = ?", *req.ResumeFileID, studentID).First(&resume); result.Error != nil {
+			apierror.Abort(c, apierror.BadRequest("Resume not found"))
+			return
+		}
+	}
+	if req.ResumeVersionID != nil {
+		var version models.ResumeVersion
+		if result := db.Where("id = ? AND user_id = ?", *req.ResumeVersionID, studentID).First(&version); result.Error != nil {
+			apierror.Abort(c, apierror.BadRequest("Resume version not found"))
+			return
+		}
+		// The version's rendered PDF doubles as the downloadable resume
+		if req.ResumeFileID == nil {
+			req.ResumeFileID = version.ResumeFileID
+		}
+	}
+
+	stages, err := jobPipeline(db, job.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to submit application"))
 		return
 	}
 
+	now := time.Now()
 	application := models.JobApplication{
 		JobListingID: job.ID,
 		UserID:       studentID,
-		Status:       "Applied",
+		Status:       stages[0].Name,
 		CoverLetter:  req.CoverLetter,
 		Resume:       req.Resume,
-		AppliedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		ResumeFileID: req.ResumeFileID,
+		ResumeVersionID: req.ResumeVersionID,
+		AppliedAt:    now,
+		UpdatedAt:    now,
+		StageChangedAt: &now,
+		StageHistory: []models.ApplicationStageEvent{{ToStage: stages[0].Name}},
 	}
 	if result := db.Create(&application); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit application"})
+		apierror.Abort(c, apierror.Internal("Failed to submit application"))
 		return
 	}
+	applicationsTotal.Inc("job")
 
 	// Increment applicant count on job listing
-	db.Model(&models.JobListing{}).Where("id = ?", job.ID).Update("applicant_count", gorm.Expr("applicant_count + ?", 1))
+	db.Model(&models.JobListing{}).Where("id = ?", job.ID).UpdateColumn("applicant_count", gorm.Expr("applicant_count + ?", 1))
+
+	var applicant models.User
+	db.First(&applicant, studentID)
+	emitJobWebhook(db, job, webhookApplicationCreated, applicationWebhookData(application, applicant))
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Application submitted successfully",
-		"application_id": application.ID,
+	// Score the new application in the background so companies see it ranked
+	if err := enqueueFits(db, []uint{application.ID}, false); err != nil {
+		middleware.Log(c).Error("failed to queue fit score", "application_id", application.ID, "error", err)
+	}
+
+	c.JSON(http.StatusCreated, applyResponse{
+		Message:       "Application submitted successfully",
+		ApplicationID: application.ID,
 	})
 }
 
+type applyResponse struct {
+	Message       string `json:"message"`
+	ApplicationID uint   `json:"application_id"`
+}
+
+type myJobApplicationsResponse struct {
+	JobIDs []uint `json:"job_ids"`
+}
+
 // GetMyJobApplications - Get job IDs the student has applied to
 func GetMyJobApplications(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 	var applications []models.JobApplication
 	if result := db.Where("user_id = ?", userID.(uint)).Find(&applications); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch applications"))
 		return
 	}
 	jobIDs := make([]uint, 0, len(applications))
 	for _, app := range applications {
 		jobIDs = append(jobIDs, app.JobListingID)
 	}
-	c.JSON(http.StatusOK, gin.H{"job_ids": jobIDs})
+	c.JSON(http.StatusOK, myJobApplicationsResponse{JobIDs: jobIDs})
 }
 
 // GetAllJobListings - Get all job listings (public endpoint with filtering)
 func GetAllJobListings(c *gin.Context) {
-	domain := c.Query("domain")
-	location := c.Query("location")
-	category := c.Query("category")
-
-	var jobListings []models.JobListing
-	query := db.Where("is_active = ? AND application_deadline > ?", true, time.Now())
-
-	if domain != "" {
-		query = query.Where("domain = ?", domain)
-	}
-	if location != "" {
-		query = query.Where("location LIKE ?", "%"+location+"%")
-	}
-	if category != "" {
-		query = query.Where("category = ?", category)
+	// Same filters as saved searches, so saving a search alerts on exactly these results
+	minStipend, _ := strconv.Atoi(c.Query("min_stipend"))
+	filters := models.SavedSearch{
+		Domain:     c.Query("domain"),
+		Location:   c.Query("location"),
+		Category:   c.Query("category"),
+		Skills:     c.Query("skills"),
+		MinStipend: minStipend,
 	}
 
-	if result := query.Preload("Company").Order("created_at DESC").Find(&jobListings); result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job listings"})
+	jobListings, err := savedSearchJobs(filters, time.Time{}, time.Time{})
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch job listings"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"total": len(jobListings),
-		"jobs":  jobListings,
+	c.JSON(http.StatusOK, jobListingsResponse{
+		Total: len(jobListings),
+		Jobs:  jobListings,
 	})
 }
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/config"
+	"SkillBridge/metrics"
+	"crypto/subtle"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Business counters; HTTP, database and external call metrics live with the code they measure
+var (
+	signupsTotal = metrics.NewCounter("skillbridge_signups_total",
+		"Accounts created, by role and sign-up method.", "role", "method")
+	applicationsTotal = metrics.NewCounter("skillbridge_applications_total",
+		"Applications made by students, by kind (project or job).", "kind")
+	submissionsTotal = metrics.NewCounter("skillbridge_submissions_total",
+		"Final project submissions made by students.")
+)
+
+// metricsToken is the bearer token scrapers send; see config.Secret. It may
+// be left unset in development, which leaves /metrics open.
+func metricsToken() (string, error) {
+	return config.Secret("METRICS_TOKEN", "")
+}
+
+// CheckMetricsToken reports why /metrics cannot be protected, if it can't.
+// main calls it at startup so a missing token stops the server.
+func CheckMetricsToken() error {
+	_, err := metricsToken()
+	return err
+}
+
+// Metrics - Prometheus metrics. Scrapers send METRICS_TOKEN as a bearer token.
+func Metrics(c *gin.Context) {
+	token, err := metricsToken()
+	if err != nil {
+		apierror.Abort(c, apierror.Unavailable("Metrics are not configured"))
+		return
+	}
+	if token != "" {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+			apierror.Abort(c, apierror.Unauthorized("Invalid metrics token"))
+			return
+		}
+	}
+	metrics.Handler().ServeHTTP(c.Writer, c.Request)
+}
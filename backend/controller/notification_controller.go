@@ -0,0 +1,239 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/models"
+	"SkillBridge/notify"
+	"crypto/rand"
+	"encoding/hex"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// onlineWindow is how recently a user must have made a request to count as online
+	onlineWindow = 5 * time.Minute
+	// presenceInterval limits how often a user's last-seen time is written
+	presenceInterval = time.Minute
+	// maxPreviewLength is how much of a chat message goes into an email
+	maxPreviewLength = 200
+)
+
+var lastSeenWrites sync.Map // userID -> time.Time of the last write
+
+// TrackPresence records when each user was last active, for deciding whether
+// to email them about things they would otherwise see live
+func TrackPresence() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetUint("userID")
+		// Scripts using an API key are not the user being online
+		if userID != 0 && c.GetUint("apiKeyID") == 0 {
+			now := time.Now()
+			last, ok := lastSeenWrites.Load(userID)
+			if !ok || now.Sub(last.(time.Time)) >= presenceInterval {
+				lastSeenWrites.Store(userID, now)
+				DB.Model(&models.User{}).Where("id = ?", userID).UpdateColumn("last_seen_at", now)
+			}
+		}
+		c.Next()
+	}
+}
+
+// userOnline reports whether the user made a request within the online window
+func userOnline(userID uint) bool {
+	var user models.User
+	if err := DB.Select("id", "last_seen_at").First(&user, userID).Error; err != nil {
+		return false
+	}
+	return user.LastSeenAt != nil && time.Since(*user.LastSeenAt) < onlineWindow
+}
+
+// messagePreview shortens a chat message for notifications
+func messagePreview(message string) string {
+	if utf8.RuneCountInString(message) <= maxPreviewLength {
+		return message
+	}
+	runes := []rune(message)
+	return string(runes[:maxPreviewLength]) + "…"
+}
+
+// GetNotificationPreferences - User views how they are notified of each event
+func GetNotificationPreferences(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	descriptions := map[string]string{}
+	for _, e := range notify.Events() {
+		descriptions[e.Name] = e.Description
+	}
+	prefs := notify.Preferences(DB, userID)
+	entries := make([]eventPreference, 0, len(prefs))
+	for _, pref := range prefs {
+		entries = append(entries, eventPreference{
+			Event:       pref.Event,
+			Description: descriptions[pref.Event],
+			InApp:       pref.InApp,
+			Email:       pref.Email,
+			Webhook:     pref.Webhook,
+		})
+	}
+
+	settings := notify.Settings(DB, userID)
+	c.JSON(http.StatusOK, notificationPreferencesResponse{
+		Events:        entries,
+		EmailPaused:   settings.EmailPaused,
+		WebhookURL:    settings.WebhookURL,
+		WebhookSecret: settings.WebhookSecret,
+	})
+}
+
+// notificationPreferencesResponse is returned by both notification preference routes
+type notificationPreferencesResponse struct {
+	Events        []eventPreference `json:"events"`
+	EmailPaused   bool              `json:"email_paused"`
+	WebhookURL    string            `json:"webhook_url"`
+	WebhookSecret string            `json:"webhook_secret"`
+}
+
+type eventPreference struct {
+	Event       string `json:"event"`
+	Description string `json:"description"`
+	InApp       bool   `json:"in_app"`
+	Email       string `json:"email"`
+	Webhook     bool   `json:"webhook"`
+}
+
+type notificationPreferencesInput struct {
+	Events map[string]struct {
+		InApp   *bool   `json:"in_app"`
+		Email   *string `json:"email"`
+		Webhook *bool   `json:"webhook"`
+	} `json:"events"`
+	EmailPaused *bool   `json:"email_paused"`
+	WebhookURL  *string `json:"webhook_url"`
+}
+
+// UpdateNotificationPreferences - User changes per-event channels and their email/webhook settings
+func UpdateNotificationPreferences(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input notificationPreferencesInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	for event, change := range input.Events {
+		if !notify.ValidEvent(event) {
+			apierror.Abort(c, apierror.BadRequest("Unknown event: "+event))
+			return
+		}
+		if change.Email != nil && *change.Email != models.EmailOff && *change.Email != models.EmailInstant && *change.Email != models.EmailDigest {
+			apierror.Abort(c, apierror.BadRequest("email must be one of: off, instant, digest"))
+			return
+		}
+	}
+	if input.WebhookURL != nil && *input.WebhookURL != "" {
+		parsed, err := url.Parse(*input.WebhookURL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			apierror.Abort(c, apierror.BadRequest("webhook_url must be an http(s) URL"))
+			return
+		}
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		for event, change := range input.Events {
+			pref := notify.Preference(tx, userID, event)
+			if change.InApp != nil {
+				pref.InApp = *change.InApp
+			}
+			if change.Email != nil {
+				pref.Email = *change.Email
+			}
+			if change.Webhook != nil {
+				pref.Webhook = *change.Webhook
+			}
+			if err := notify.SavePreference(tx, pref); err != nil {
+				return err
+			}
+		}
+
+		if input.EmailPaused == nil && input.WebhookURL == nil {
+			return nil
+		}
+		settings := notify.Settings(tx, userID)
+		if input.EmailPaused != nil {
+			settings.EmailPaused = *input.EmailPaused
+		}
+		if input.WebhookURL != nil && *input.WebhookURL != settings.WebhookURL {
+			settings.WebhookURL = *input.WebhookURL
+			settings.WebhookSecret = ""
+			if settings.WebhookURL != "" {
+				// A new endpoint gets a new signing secret
+				secret := make([]byte, 24)
+				if _, err := rand.Read(secret); err != nil {
+					return err
+				}
+				settings.WebhookSecret = hex.EncodeToString(secret)
+			}
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"email_paused", "webhook_url", "webhook_secret", "updated_at"}),
+		}).Create(&settings).Error
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to save notification preferences"))
+		return
+	}
+
+	GetNotificationPreferences(c)
+}
+
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>SkillBridge email preferences</title></head>
+<body style="font-family:Helvetica,Arial,sans-serif;max-width:480px;margin:48px auto;color:#1f2933">
+{{if .Done}}<p>{{.Message}}</p>
+{{else}}<p>{{.Message}}</p>
+<form method="post"><button type="submit" style="padding:10px 16px">Unsubscribe</button></form>
+{{end}}</body></html>`))
+
+// UnsubscribeEmail - Public page behind the unsubscribe link in emails. GET asks
+// for confirmation so link scanners cannot unsubscribe anyone; POST (including
+// one-click unsubscribe from mail clients) applies it.
+func UnsubscribeEmail(c *gin.Context) {
+	token := c.Query("token")
+	_, event, ok := notify.ParseUnsubscribeToken(token)
+	if !ok {
+		apierror.Abort(c, apierror.BadRequest("Invalid unsubscribe link"))
+		return
+	}
+
+	what := "emails about this kind of update"
+	if event == notify.AllEvents {
+		what = "all SkillBridge emails"
+	}
+	page := struct {
+		Done    bool
+		Message string
+	}{Message: "Stop receiving " + what + "?"}
+
+	if c.Request.Method == http.MethodPost {
+		if _, err := notify.Unsubscribe(DB, token); err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to unsubscribe"))
+			return
+		}
+		page.Done = true
+		page.Message = "You will no longer receive " + what + ". You can change this any time in your notification settings."
+	}
+
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	unsubscribePage.Execute(c.Writer, page)
+}
@@ -0,0 +1,424 @@
+package controller
+
+import (
+	"SkillBridge/models"
+	"SkillBridge/openapi"
+	"net/http"
+)
+
+// reviewSubmissionRequest documents ReviewSubmission, which takes companyReviewInput
+// from companies and guideReviewInput from guides
+type reviewSubmissionRequest struct {
+	Status        string             `json:"status"`         // company: accepted, rejected, changes_requested
+	Feedback      string             `json:"feedback"`       // company
+	ReviewStatus  string             `json:"review_status"`  // guide: approved, rejected, pending
+	ReviewComment string             `json:"review_comment"` // guide
+	Scores        []RubricScoreInput `json:"scores"`
+}
+
+// DocumentAPI describes every route registered by the router
+func DocumentAPI(spec *openapi.Spec) {
+	message := models.MessageResponse{}
+
+	// Health
+	spec.Add("GET", "/health", openapi.Operation{Summary: "Readiness check (same as /health/ready)", Tag: "Health", Public: true,
+		Response: healthResponse{}})
+	spec.Add("GET", "/health/live", openapi.Operation{Summary: "Liveness check; does not touch the database", Tag: "Health", Public: true,
+		Response: healthResponse{}})
+	spec.Add("GET", "/health/ready", openapi.Operation{Summary: "Readiness check: not shutting down, database reachable and no pending migrations", Tag: "Health", Public: true,
+		Response: healthResponse{}})
+	spec.Add("GET", "/metrics", openapi.Operation{Summary: "Prometheus metrics; requires METRICS_TOKEN as a bearer token (optional in development)", Tag: "Health", Public: true,
+		Produces: "text/plain"})
+	spec.Add("GET", "/openapi.json", openapi.Operation{Summary: "This API specification", Tag: "Health", Public: true,
+		Produces: "application/json"})
+
+	// Auth
+	spec.Add("POST", "/api/signup", openapi.Operation{Summary: "Create an account", Tag: "Auth", Public: true,
+		Request: signUpInput{}, Response: tokenResponse{}})
+	spec.Add("POST", "/api/login", openapi.Operation{Summary: "Log in with email and password", Tag: "Auth", Public: true,
+		Request: loginInput{}, Response: loginResponse{}})
+	spec.Add("POST", "/api/google-oauth", openapi.Operation{Summary: "Log in or sign up with a Google account", Tag: "Auth", Public: true,
+		Request: googleOAuthInput{}, Response: oauthResponse{}})
+	spec.Add("POST", "/api/refresh-token", openapi.Operation{Summary: "Issue a fresh token", Tag: "Auth",
+		Response: tokenResponse{}})
+
+	// Profile
+	spec.Add("GET", "/api/profile", openapi.Operation{Summary: "The caller's profile", Tag: "Profile",
+		Response: profileResponse{}})
+	spec.Add("PUT", "/api/profile", openapi.Operation{Summary: "Update the caller's profile", Tag: "Profile",
+		Request: updateProfileInput{}, Response: updateProfileResponse{}})
+	spec.Add("POST", "/api/github/token", openapi.Operation{Summary: "Connect a GitHub personal access token", Tag: "Profile",
+		Request: setGithubTokenInput{}, Response: githubTokenResponse{}})
+	spec.Add("DELETE", "/api/github/token", openapi.Operation{Summary: "Disconnect GitHub", Tag: "Profile",
+		Response: githubTokenResponse{}})
+
+	// Portfolio
+	spec.Add("GET", "/api/student/:id", openapi.Operation{Summary: "A student's public portfolio", Tag: "Portfolio", Public: true,
+		Response: studentPortfolio{}})
+	spec.Add("GET", "/api/company/:id", openapi.Operation{Summary: "A company's public profile", Tag: "Portfolio", Public: true,
+		Response: publicCompanyProfile{}})
+	spec.Add("GET", "/api/guides", openapi.Operation{Summary: "List guides", Tag: "Portfolio", Public: true,
+		Response: guidesResponse{}})
+	spec.Add("GET", "/api/portfolio/settings", openapi.Operation{Summary: "The student's portfolio visibility settings", Tag: "Portfolio",
+		Response: portfolioSettingsResponse{}})
+	spec.Add("PUT", "/api/portfolio/settings", openapi.Operation{Summary: "Change portfolio visibility settings", Tag: "Portfolio",
+		Request: portfolioSettingsInput{}, Response: portfolioSettingsResponse{}})
+	spec.Add("POST", "/api/student/:id/endorsements", openapi.Operation{Summary: "Endorse a student the guide has mentored", Tag: "Portfolio",
+		Request: endorsementInput{}, Status: http.StatusCreated, Response: endorsementResponse{}})
+	spec.Add("DELETE", "/api/endorsements/:id", openapi.Operation{Summary: "Remove an endorsement", Tag: "Portfolio",
+		Response: message})
+
+	// Projects
+	spec.Add("GET", "/api/projects", openapi.Operation{Summary: "List projects, best skill matches first when logged in", Tag: "Projects", Public: true,
+		Response: projectsResponse{}})
+	spec.Add("GET", "/api/projects/:id", openapi.Operation{Summary: "A project", Tag: "Projects", Public: true,
+		Response: projectDetailResponse{}})
+	spec.Add("POST", "/api/projects", openapi.Operation{Summary: "Post a project", Tag: "Projects",
+		Request: postProjectInput{}, Response: projectResponse{}})
+	spec.Add("DELETE", "/api/projects/:id", openapi.Operation{Summary: "Delete a project", Tag: "Projects",
+		Response: message})
+	spec.Add("GET", "/api/company/projects", openapi.Operation{Summary: "The company's projects", Tag: "Projects",
+		Response: projectsResponse{}})
+	spec.Add("POST", "/api/projects/apply", openapi.Operation{Summary: "Apply to a project", Tag: "Projects",
+		Request: applyToProjectInput{}, Response: applicationResponse{}})
+	spec.Add("DELETE", "/api/projects/:id/apply", openapi.Operation{Summary: "Withdraw a project application", Tag: "Projects",
+		Response: message})
+	spec.Add("GET", "/api/projects/:id/applicants", openapi.Operation{Summary: "Applicants to a project", Tag: "Projects",
+		Response: applicantsResponse{}})
+	spec.Add("GET", "/api/company/applications", openapi.Operation{Summary: "Applications to all of the company's projects", Tag: "Projects",
+		Response: applicationsResponse{}})
+	spec.Add("GET", "/api/my-applications", openapi.Operation{Summary: "The student's project applications", Tag: "Projects",
+		Response: applicationsResponse{}})
+	spec.Add("POST", "/api/projects/submit-github", openapi.Operation{Summary: "Attach a GitHub repository to an application", Tag: "Projects",
+		Request: submitRepoInput{}, Response: submitRepoResponse{}})
+	spec.Add("PUT", "/api/projects/:id/rubric", openapi.Operation{Summary: "Set the project's review rubric", Tag: "Projects",
+		Request: rubricInput{}, Response: rubricResponse{}})
+	spec.Add("GET", "/api/projects/:id/rubric", openapi.Operation{Summary: "The project's review rubric", Tag: "Projects",
+		Response: rubricResponse{}})
+	spec.Add("GET", "/api/projects/:id/review-scores", openapi.Operation{Summary: "Compare rubric scores across submissions", Tag: "Projects",
+		Response: reviewScoresResponse{}})
+
+	// Submissions
+	spec.Add("POST", "/api/projects/:id/submit", openapi.Operation{Summary: "Submit work for a project", Tag: "Submissions",
+		Request: submitProjectInput{}, Response: submissionResponse{}})
+	spec.Add("GET", "/api/projects/:id/submissions", openapi.Operation{Summary: "Submissions to a project", Tag: "Submissions",
+		Response: submissionsResponse{}})
+	spec.Add("GET", "/api/my-submissions", openapi.Operation{Summary: "The student's submissions", Tag: "Submissions",
+		Response: submissionsResponse{}})
+	spec.Add("GET", "/api/guide/submissions", openapi.Operation{Summary: "Submissions to the guide's projects", Tag: "Submissions",
+		Response: submissionsResponse{}})
+	review := openapi.Operation{Summary: "Review a submission as its company or guide", Tag: "Submissions",
+		Request: reviewSubmissionRequest{}, Response: reviewSubmissionResponse{}}
+	spec.Add("POST", "/api/submissions/:id/review", review)
+	spec.Add("PUT", "/api/submissions/:id/review", review)
+	spec.Add("GET", "/api/submissions/:id", openapi.Operation{Summary: "A submission", Tag: "Submissions",
+		Response: submissionResponse{}})
+	spec.Add("GET", "/api/submissions/:id/comments", openapi.Operation{Summary: "Review comment threads on a submission", Tag: "Submissions",
+		Query: []openapi.Param{
+			{Name: "file_path", Description: "Only threads on this file"},
+			{Name: "resolved", Description: "true or false"},
+		},
+		Response: commentsResponse{}})
+	spec.Add("POST", "/api/submissions/:id/comments", openapi.Operation{Summary: "Comment on a submission or reply to a thread", Tag: "Submissions",
+		Request: submissionCommentInput{}, Status: http.StatusCreated, Response: commentResponse{}})
+	spec.Add("PATCH", "/api/comments/:id/resolve", openapi.Operation{Summary: "Resolve or reopen a comment thread", Tag: "Submissions",
+		Request: resolveCommentInput{}, Response: resolveCommentResponse{}})
+	spec.Add("DELETE", "/api/comments/:id", openapi.Operation{Summary: "Delete a comment", Tag: "Submissions",
+		Response: message})
+
+	// Certificates
+	spec.Add("GET", "/api/certificates/public-key", openapi.Operation{Summary: "The key certificates are signed with", Tag: "Certificates", Public: true,
+		Response: publicKeyResponse{}})
+	spec.Add("GET", "/api/certificates/:certificate_id/verify", openapi.Operation{Summary: "Verify a certificate", Tag: "Certificates", Public: true,
+		Response: verificationResponse{}})
+	spec.Add("GET", "/api/certificates/:certificate_id/pdf", openapi.Operation{Summary: "Download a certificate", Tag: "Certificates", Public: true,
+		Produces: "application/pdf"})
+	spec.Add("GET", "/api/my-certificates", openapi.Operation{Summary: "The student's certificates", Tag: "Certificates",
+		Response: certificatesResponse{}})
+	spec.Add("POST", "/api/certificates/:certificate_id/revoke", openapi.Operation{Summary: "Revoke a certificate", Tag: "Certificates",
+		Request: revokeCertificateInput{}, Response: revokeCertificateResponse{}})
+
+	// Dashboards
+	spec.Add("GET", "/api/dashboard/admin", openapi.Operation{Summary: "Platform totals", Tag: "Dashboards",
+		Response: adminDashboardResponse{}})
+	spec.Add("GET", "/api/dashboard/student", openapi.Operation{Summary: "The student's application counts", Tag: "Dashboards",
+		Response: studentDashboardResponse{}})
+	spec.Add("GET", "/api/dashboard/company", openapi.Operation{Summary: "The company's posting and application counts", Tag: "Dashboards",
+		Response: companyDashboardResponse{}})
+	spec.Add("GET", "/api/dashboard/guide", openapi.Operation{Summary: "The guide's review counts", Tag: "Dashboards",
+		Response: guideDashboardResponse{}})
+
+	// Guide assignment
+	spec.Add("POST", "/api/projects/:id/guide-invitations", openapi.Operation{Summary: "Invite a guide to a project", Tag: "Guides",
+		Request: inviteGuideInput{}, Status: http.StatusCreated, Response: guideInvitationResponse{}})
+	spec.Add("GET", "/api/projects/:id/guide-invitations", openapi.Operation{Summary: "Guide invitations for a project", Tag: "Guides",
+		Response: guideInvitationsResponse{}})
+	spec.Add("GET", "/api/guide/invitations", openapi.Operation{Summary: "The guide's project invitations", Tag: "Guides",
+		Query:    []openapi.Param{{Name: "status", Description: "pending (default), accepted, declined, or all"}},
+		Response: guideInvitationsResponse{}})
+	spec.Add("POST", "/api/guide/invitations/:id/respond", openapi.Operation{Summary: "Accept or decline a project invitation", Tag: "Guides",
+		Request: guideInvitationResponseInput{}, Response: respondInvitationResponse{}})
+	spec.Add("GET", "/api/guide/projects", openapi.Operation{Summary: "Projects the guide is assigned to", Tag: "Guides",
+		Response: guideProjectsResponse{}})
+	spec.Add("PUT", "/api/guide/capacity", openapi.Operation{Summary: "Set how many projects the guide can take on", Tag: "Guides",
+		Request: guideCapacityInput{}, Response: guideCapacityResponse{}})
+	spec.Add("PUT", "/api/admin/projects/:id/guide", openapi.Operation{Summary: "Reassign a project's guide", Tag: "Guides",
+		Request: reassignGuideInput{}, Response: projectResponse{}})
+
+	// Chat
+	spec.Add("POST", "/api/chat/start", openapi.Operation{Summary: "Start a conversation with a guide", Tag: "Chat",
+		Request: startConversationInput{}, Status: http.StatusCreated, Response: conversationResponse{}})
+	spec.Add("POST", "/api/chat/send", openapi.Operation{Summary: "Send a chat message", Tag: "Chat",
+		Request: sendMessageInput{}, Status: http.StatusCreated, Response: chatMessageResponse{}})
+	spec.Add("GET", "/api/chat/history/:student_id/:guide_id", openapi.Operation{Summary: "Messages between a student and a guide", Tag: "Chat",
+		Response: chatHistoryResponse{}})
+	spec.Add("GET", "/api/chat/conversations", openapi.Operation{Summary: "The caller's conversations", Tag: "Chat",
+		Response: conversationsResponse{}})
+	spec.Add("GET", "/api/chat/connected-guides", openapi.Operation{Summary: "Guides the student can message", Tag: "Chat",
+		Response: connectedGuidesResponse{}})
+	spec.Add("GET", "/api/guide/pending-confirmations", openapi.Operation{Summary: "Connection requests awaiting the guide", Tag: "Chat",
+		Response: pendingConfirmationsResponse{}})
+	spec.Add("POST", "/api/guide/confirm-connection", openapi.Operation{Summary: "Accept or reject a connection request", Tag: "Chat",
+		Request: confirmConnectionInput{}, Response: confirmConnectionResponse{}})
+
+	// Jobs
+	spec.Add("GET", "/api/jobs", openapi.Operation{Summary: "Search job listings", Tag: "Jobs", Public: true,
+		Query: []openapi.Param{
+			{Name: "category"}, {Name: "domain"}, {Name: "location"},
+			{Name: "skills", Description: "Comma-separated"},
+			{Name: "min_stipend"},
+		},
+		Response: jobListingsResponse{}})
+	spec.Add("GET", "/api/jobs/:id", openapi.Operation{Summary: "A job listing", Tag: "Jobs", Public: true,
+		Response: models.JobListing{}})
+	spec.Add("POST", "/api/jobs", openapi.Operation{Summary: "Post a job listing", Tag: "Jobs",
+		Request: models.CreateJobListingRequest{}, Status: http.StatusCreated, Response: jobListingResponse{}})
+	spec.Add("PUT", "/api/jobs/:id", openapi.Operation{Summary: "Update a job listing", Tag: "Jobs",
+		Request: models.UpdateJobListingRequest{}, Response: jobListingResponse{}})
+	spec.Add("DELETE", "/api/jobs/:id", openapi.Operation{Summary: "Delete a job listing", Tag: "Jobs",
+		Response: message})
+	spec.Add("GET", "/api/company/jobs", openapi.Operation{Summary: "The company's job listings", Tag: "Jobs",
+		Response: jobListingsResponse{}})
+	spec.Add("GET", "/api/jobs/:id/pipeline", openapi.Operation{Summary: "The job's hiring pipeline", Tag: "Jobs",
+		Response: pipelineResponse{}})
+	spec.Add("PUT", "/api/jobs/:id/pipeline", openapi.Operation{Summary: "Replace the job's hiring pipeline", Tag: "Jobs",
+		Request: models.UpdatePipelineRequest{}, Response: updatePipelineResponse{}})
+
+	// Job applications
+	spec.Add("POST", "/api/jobs/:id/apply", openapi.Operation{Summary: "Apply to a job", Tag: "Applications",
+		Request: models.ApplyToJobRequest{}, Status: http.StatusCreated, Response: applyResponse{}})
+	spec.Add("GET", "/api/my-job-applications", openapi.Operation{Summary: "IDs of the jobs the student applied to", Tag: "Applications",
+		Response: myJobApplicationsResponse{}})
+	spec.Add("GET", "/api/jobs/:id/applications", openapi.Operation{Summary: "Applicants to a job with fit scores", Tag: "Applications",
+		Query: []openapi.Param{
+			{Name: "status", Description: "Only applications in this pipeline stage"},
+			{Name: "sort", Description: "fit to order by fit score"},
+			{Name: "min_fit", Description: "Only applicants scoring at least this"},
+		},
+		Response: jobApplicantsResponse{}})
+	spec.Add("GET", "/api/jobs/:id/applications/export", openapi.Operation{Summary: "Export a job's applicants as CSV", Tag: "Applications",
+		Query:    []openapi.Param{{Name: "status", Description: "Only applications in this pipeline stage"}},
+		Produces: "text/csv"})
+	spec.Add("POST", "/api/jobs/:id/applications/fit", openapi.Operation{Summary: "Queue fresh fit scores for a job's applicants", Tag: "Applications",
+		Status: http.StatusAccepted, Response: refreshFitsResponse{}})
+	spec.Add("POST", "/api/jobs/:id/applications/move", openapi.Operation{Summary: "Move several applications to a stage", Tag: "Applications",
+		Request: models.BulkMoveApplicationsRequest{}, Response: bulkMoveResponse{}})
+	spec.Add("GET", "/api/applications/:id", openapi.Operation{Summary: "A job application", Tag: "Applications",
+		Response: models.JobApplication{}})
+	spec.Add("PATCH", "/api/applications/:id/status", openapi.Operation{Summary: "Move an application to a pipeline stage", Tag: "Applications",
+		Request: models.UpdateApplicationStatusRequest{}, Response: applicationStatusResponse{}})
+	spec.Add("GET", "/api/applications/:id/history", openapi.Operation{Summary: "An application's stage changes", Tag: "Applications",
+		Response: applicationHistoryResponse{}})
+	spec.Add("GET", "/api/applications/:id/resume-url", openapi.Operation{Summary: "A short-lived link to the attached resume", Tag: "Applications",
+		Response: signedURLResponse{}})
+	spec.Add("GET", "/api/applications/:id/resume-version", openapi.Operation{Summary: "The resume version attached to an application", Tag: "Applications",
+		Response: applicationResumeVersionResponse{}})
+	spec.Add("GET", "/api/company/application-stats", openapi.Operation{Summary: "Application counts and pipeline funnel", Tag: "Applications",
+		Query:    []openapi.Param{{Name: "job_id", Description: "Limit to one job"}},
+		Response: applicationStats{}})
+
+	// Resumes
+	spec.Add("POST", "/api/resumes/upload", openapi.Operation{Summary: "Upload a resume as the multipart field \"file\"", Tag: "Resumes",
+		Status: http.StatusCreated, Response: resumeFileResponse{}})
+	spec.Add("GET", "/api/resumes", openapi.Operation{Summary: "The student's resumes", Tag: "Resumes",
+		Response: resumeFilesResponse{}})
+	spec.Add("GET", "/api/resumes/:id/download", openapi.Operation{Summary: "Download one of the student's resumes", Tag: "Resumes",
+		Produces: "application/octet-stream"})
+	spec.Add("DELETE", "/api/resumes/:id", openapi.Operation{Summary: "Delete a resume", Tag: "Resumes",
+		Response: message})
+	spec.Add("GET", "/api/files/resumes/:id", openapi.Operation{Summary: "Download a resume through a signed link", Tag: "Resumes", Public: true,
+		Query: []openapi.Param{
+			{Name: "expires", Required: true},
+			{Name: "signature", Required: true},
+		},
+		Produces: "application/octet-stream"})
+	spec.Add("POST", "/api/resume/generate", openapi.Operation{Summary: "Generate a resume from the profile", Tag: "Resumes",
+		Request: ResumeRequestBody{}, Response: generatedResumeResponse{}})
+	spec.Add("GET", "/api/resume/templates", openapi.Operation{Summary: "Resume templates and formats", Tag: "Resumes",
+		Response: resumeTemplatesResponse{}})
+	spec.Add("POST", "/api/resume/parse", openapi.Operation{Summary: "Parse an uploaded or stored resume into profile changes", Tag: "Resumes",
+		Query:    []openapi.Param{{Name: "resume_file_id", Description: "Parse a stored resume instead of an upload"}},
+		Response: parsedResumeResponse{}})
+	spec.Add("POST", "/api/resume/parse/apply", openapi.Operation{Summary: "Apply parsed resume details to the profile or a draft", Tag: "Resumes",
+		Request: applyParsedResumeInput{}, Response: applyParsedResumeResponse{}})
+
+	// Resume drafts
+	draft := resumeDraftResponse{}
+	spec.Add("POST", "/api/resume/drafts", openapi.Operation{Summary: "Create a resume draft", Tag: "Resume drafts",
+		Request: createResumeDraftInput{}, Status: http.StatusCreated, Response: draft})
+	spec.Add("GET", "/api/resume/drafts", openapi.Operation{Summary: "The student's resume drafts", Tag: "Resume drafts",
+		Response: resumeDraftsResponse{}})
+	spec.Add("GET", "/api/resume/drafts/:id", openapi.Operation{Summary: "A resume draft", Tag: "Resume drafts",
+		Response: draft})
+	spec.Add("PATCH", "/api/resume/drafts/:id", openapi.Operation{Summary: "Update a resume draft", Tag: "Resume drafts",
+		Request: updateResumeDraftInput{}, Response: draft})
+	spec.Add("DELETE", "/api/resume/drafts/:id", openapi.Operation{Summary: "Delete a resume draft", Tag: "Resume drafts",
+		Response: message})
+	spec.Add("POST", "/api/resume/drafts/:id/duplicate", openapi.Operation{Summary: "Copy a resume draft, optionally tailored to a job", Tag: "Resume drafts",
+		Request: duplicateResumeDraftInput{}, OptionalBody: true, Status: http.StatusCreated, Response: draft})
+	spec.Add("POST", "/api/resume/drafts/:id/render", openapi.Operation{Summary: "Render a draft without saving it", Tag: "Resume drafts",
+		Query:    []openapi.Param{{Name: "format", Description: "pdf (default) or html"}},
+		Produces: "application/pdf"})
+	spec.Add("POST", "/api/resume/drafts/:id/versions", openapi.Operation{Summary: "Save a numbered version of a draft", Tag: "Resume drafts",
+		Request: resumeVersionInput{}, OptionalBody: true, Status: http.StatusCreated, Response: resumeVersionResponse{}})
+	spec.Add("GET", "/api/resume/drafts/:id/versions/:version", openapi.Operation{Summary: "A saved version of a draft", Tag: "Resume drafts",
+		Response: resumeVersionResponse{}})
+	spec.Add("POST", "/api/resume/drafts/:id/versions/:version/restore", openapi.Operation{Summary: "Restore a draft to a saved version", Tag: "Resume drafts",
+		Response: draft})
+
+	// Interviews
+	spec.Add("POST", "/api/jobs/:id/interview-slots", openapi.Operation{Summary: "Publish interview slots for a job", Tag: "Interviews",
+		Request: interviewSlotsInput{}, Status: http.StatusCreated, Response: createSlotsResponse{}})
+	spec.Add("GET", "/api/jobs/:id/interview-slots", openapi.Operation{Summary: "A job's interview slots and bookings", Tag: "Interviews",
+		Query:    []openapi.Param{{Name: "include_past", Description: "true to include slots that have ended"}},
+		Response: interviewSlotsResponse{}})
+	spec.Add("DELETE", "/api/interview-slots/:id", openapi.Operation{Summary: "Delete a slot, cancelling its interview", Tag: "Interviews",
+		Response: deleteSlotResponse{}})
+	spec.Add("GET", "/api/my-job-applications/:id/interview-slots", openapi.Operation{Summary: "Slots the student can book for an application", Tag: "Interviews",
+		Response: interviewSlotsResponse{}})
+	spec.Add("POST", "/api/interviews", openapi.Operation{Summary: "Book an interview slot", Tag: "Interviews",
+		Request: bookInterviewInput{}, Status: http.StatusCreated, Response: interviewResponse{}})
+	spec.Add("GET", "/api/interviews", openapi.Operation{Summary: "The caller's interviews", Tag: "Interviews",
+		Query: []openapi.Param{
+			{Name: "status"},
+			{Name: "include_past", Description: "true to include interviews that have ended"},
+		},
+		Response: interviewsResponse{}})
+	spec.Add("PUT", "/api/interviews/:id/reschedule", openapi.Operation{Summary: "Move an interview to another slot", Tag: "Interviews",
+		Request: rescheduleInterviewInput{}, Response: interviewResponse{}})
+	spec.Add("POST", "/api/interviews/:id/cancel", openapi.Operation{Summary: "Cancel an interview", Tag: "Interviews",
+		Request: cancelInterviewInput{}, OptionalBody: true, Response: message})
+	spec.Add("GET", "/api/interviews/:id/ics", openapi.Operation{Summary: "An interview as a calendar event", Tag: "Interviews",
+		Produces: "text/calendar"})
+	spec.Add("GET", "/api/interviews/calendar-feed", openapi.Operation{Summary: "The caller's private calendar feed link", Tag: "Interviews",
+		Query:    []openapi.Param{{Name: "rotate", Description: "true to issue a new link"}},
+		Response: calendarFeedResponse{}})
+	spec.Add("GET", "/api/calendar/:token/interviews.ics", openapi.Operation{Summary: "Calendar feed of upcoming interviews", Tag: "Interviews", Public: true,
+		Produces: "text/calendar"})
+	spec.Add("GET", "/api/interview-prep", openapi.Operation{Summary: "Interview preparation resources for the caller's skills", Tag: "Interviews",
+		Response: interviewPrepResponse{}})
+
+	// Saved searches
+	savedSearch := savedSearchResponse{}
+	spec.Add("POST", "/api/saved-searches", openapi.Operation{Summary: "Save a search and get alerts for new matches", Tag: "Saved searches",
+		Request: savedSearchInput{}, Status: http.StatusCreated, Response: savedSearch})
+	spec.Add("GET", "/api/saved-searches", openapi.Operation{Summary: "The student's saved searches", Tag: "Saved searches",
+		Response: savedSearchesResponse{}})
+	spec.Add("PUT", "/api/saved-searches/:id", openapi.Operation{Summary: "Update a saved search", Tag: "Saved searches",
+		Request: savedSearchInput{}, Response: savedSearch})
+	spec.Add("DELETE", "/api/saved-searches/:id", openapi.Operation{Summary: "Delete a saved search", Tag: "Saved searches",
+		Response: message})
+	spec.Add("GET", "/api/saved-searches/:id/results", openapi.Operation{Summary: "Run a saved search now", Tag: "Saved searches",
+		Response: savedSearchResultsResponse{}})
+	spec.Add("GET", "/api/saved-searches/:id/matches", openapi.Operation{Summary: "Postings a saved search has alerted about", Tag: "Saved searches",
+		Response: savedSearchMatchesResponse{}})
+
+	// Notifications
+	spec.Add("GET", "/api/notifications/preferences", openapi.Operation{Summary: "The caller's notification channels per event", Tag: "Notifications",
+		Response: notificationPreferencesResponse{}})
+	spec.Add("PUT", "/api/notifications/preferences", openapi.Operation{Summary: "Change notification channels and settings", Tag: "Notifications",
+		Request: notificationPreferencesInput{}, Response: notificationPreferencesResponse{}})
+	unsubscribe := openapi.Operation{Summary: "Turn off emails for an event from an email link", Tag: "Notifications", Public: true,
+		Query:    []openapi.Param{{Name: "token", Required: true}},
+		Produces: "text/html"}
+	spec.Add("GET", "/api/notifications/unsubscribe", unsubscribe)
+	spec.Add("POST", "/api/notifications/unsubscribe", unsubscribe)
+
+	// Webhooks
+	spec.Add("GET", "/api/webhooks/events", openapi.Operation{Summary: "Events webhooks can subscribe to", Tag: "Webhooks",
+		Response: webhookEventsResponse{}})
+	spec.Add("GET", "/api/webhooks", openapi.Operation{Summary: "The company's webhooks", Tag: "Webhooks",
+		Response: webhooksResponse{}})
+	spec.Add("POST", "/api/webhooks", openapi.Operation{Summary: "Register a webhook; the signing secret is shown once", Tag: "Webhooks",
+		Request: webhookInput{}, Status: http.StatusCreated, Response: webhookSecretResponse{}})
+	spec.Add("GET", "/api/webhooks/:id", openapi.Operation{Summary: "A webhook", Tag: "Webhooks",
+		Response: models.WebhookEndpoint{}})
+	spec.Add("PUT", "/api/webhooks/:id", openapi.Operation{Summary: "Update a webhook", Tag: "Webhooks",
+		Request: webhookInput{}, Response: models.WebhookEndpoint{}})
+	spec.Add("DELETE", "/api/webhooks/:id", openapi.Operation{Summary: "Delete a webhook", Tag: "Webhooks",
+		Response: message})
+	spec.Add("POST", "/api/webhooks/:id/rotate-secret", openapi.Operation{Summary: "Issue a new signing secret", Tag: "Webhooks",
+		Response: webhookSecretResponse{}})
+	spec.Add("POST", "/api/webhooks/:id/ping", openapi.Operation{Summary: "Send a test event and report the outcome", Tag: "Webhooks",
+		Response: pingResponse{}})
+	spec.Add("GET", "/api/webhooks/:id/deliveries", openapi.Operation{Summary: "A webhook's delivery log", Tag: "Webhooks",
+		Query: []openapi.Param{
+			{Name: "status"}, {Name: "event"},
+			{Name: "page", Description: "Defaults to 1"},
+			{Name: "limit", Description: "Defaults to 20"},
+		},
+		Response: deliveriesResponse{}})
+	spec.Add("GET", "/api/webhooks/:id/deliveries/:delivery_id", openapi.Operation{Summary: "A delivery with its request and response", Tag: "Webhooks",
+		Response: models.WebhookDelivery{}})
+	spec.Add("POST", "/api/webhooks/:id/deliveries/:delivery_id/redeliver", openapi.Operation{Summary: "Send a delivery again", Tag: "Webhooks",
+		Status: http.StatusAccepted, Response: redeliverResponse{}})
+
+	// API keys
+	spec.Add("GET", "/api/api-keys/scopes", openapi.Operation{Summary: "Scopes an API key can be granted", Tag: "API keys",
+		Response: apiKeyScopesResponse{}})
+	spec.Add("GET", "/api/api-keys", openapi.Operation{Summary: "The caller's API keys, or the organisation's for its owner", Tag: "API keys",
+		Response: apiKeysResponse{}})
+	spec.Add("POST", "/api/api-keys", openapi.Operation{Summary: "Create an API key; the key is shown once", Tag: "API keys",
+		Request: createAPIKeyInput{}, Status: http.StatusCreated, Response: createAPIKeyResponse{}})
+	spec.Add("DELETE", "/api/api-keys/:id", openapi.Operation{Summary: "Revoke an API key", Tag: "API keys",
+		Response: message})
+
+	// Background jobs
+	spec.Add("GET", "/api/admin/jobs", openapi.Operation{Summary: "Background jobs with counts per status", Tag: "Admin",
+		Query: []openapi.Param{
+			{Name: "status"}, {Name: "type"},
+			{Name: "page", Description: "Defaults to 1"},
+			{Name: "limit", Description: "Defaults to 50"},
+		},
+		Response: backgroundJobsResponse{}})
+	spec.Add("GET", "/api/admin/jobs/:id", openapi.Operation{Summary: "A background job", Tag: "Admin",
+		Response: models.BackgroundJob{}})
+	spec.Add("POST", "/api/admin/jobs/:id/retry", openapi.Operation{Summary: "Requeue a failed job", Tag: "Admin",
+		Response: message})
+	spec.Add("DELETE", "/api/admin/jobs/:id", openapi.Operation{Summary: "Delete a pending or failed job", Tag: "Admin",
+		Response: message})
+
+	// Organisations
+	spec.Add("GET", "/api/organisations/:id", openapi.Operation{Summary: "An organisation's public profile, by ID or slug", Tag: "Organisations", Public: true,
+		Response: publicOrganisation{}})
+	spec.Add("POST", "/api/organisations", openapi.Operation{Summary: "Create an organisation and become its owner", Tag: "Organisations",
+		Request: organisationProfileInput{}, Status: http.StatusCreated, Response: organisationResponse{}})
+	spec.Add("GET", "/api/organisation", openapi.Operation{Summary: "The caller's organisation and its members", Tag: "Organisations",
+		Response: myOrganisationResponse{}})
+	spec.Add("PUT", "/api/organisation", openapi.Operation{Summary: "Update the organisation profile", Tag: "Organisations",
+		Request: organisationProfileInput{}, Response: organisationResponse{}})
+	spec.Add("POST", "/api/organisation/invitations", openapi.Operation{Summary: "Invite a member by email", Tag: "Organisations",
+		Request: inviteMemberInput{}, Status: http.StatusCreated, Response: invitationCreatedResponse{}})
+	spec.Add("GET", "/api/organisation/invitations", openapi.Operation{Summary: "The organisation's pending invitations", Tag: "Organisations",
+		Response: organisationInvitationsResponse{}})
+	spec.Add("DELETE", "/api/organisation/invitations/:id", openapi.Operation{Summary: "Revoke an invitation", Tag: "Organisations",
+		Response: message})
+	spec.Add("POST", "/api/organisation/invitations/accept", openapi.Operation{Summary: "Join an organisation", Tag: "Organisations",
+		Request: acceptInvitationInput{}, Response: acceptInvitationResponse{}})
+	spec.Add("GET", "/api/my-organisation-invitations", openapi.Operation{Summary: "Invitations sent to the caller", Tag: "Organisations",
+		Response: organisationInvitationsResponse{}})
+	spec.Add("PUT", "/api/organisation/members/:user_id", openapi.Operation{Summary: "Change a member's role", Tag: "Organisations",
+		Request: memberRoleInput{}, Response: memberRoleResponse{}})
+	spec.Add("DELETE", "/api/organisation/members/:user_id", openapi.Operation{Summary: "Remove a member, or leave the organisation", Tag: "Organisations",
+		Response: message})
+}
@@ -0,0 +1,718 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"SkillBridge/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// organisationInviteTTL is how long an invitation token stays valid
+const organisationInviteTTL = 7 * 24 * time.Hour
+
+// organisationMembership returns the user's organisation membership, if any
+func organisationMembership(userID uint) (models.OrganisationMember, bool) {
+	member, ok, err := lookupMembership(userID)
+	return member, ok && err == nil
+}
+
+// lookupMembership is organisationMembership for callers that must tell a user
+// without an organisation apart from a failed lookup
+func lookupMembership(userID uint) (models.OrganisationMember, bool, error) {
+	var member models.OrganisationMember
+	err := DB.Where("user_id = ?", userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return member, false, nil
+	}
+	return member, err == nil, err
+}
+
+// organisationIDFor returns the ID of the user's organisation, or nil when they have none
+func organisationIDFor(userID uint) *uint {
+	if member, ok := organisationMembership(userID); ok {
+		return &member.OrganisationID
+	}
+	return nil
+}
+
+// ownedByCompany scopes a query on projects, job_listings or webhook_endpoints to
+// rows the company user may see: their own rows outside any organisation plus
+// anything owned by their current organisation. Creating a row does not keep
+// access to it once it belongs to an organisation the user has left.
+func ownedByCompany(table string, userID uint) func(*gorm.DB) *gorm.DB {
+	orgID := organisationIDFor(userID)
+	return func(db *gorm.DB) *gorm.DB {
+		if orgID == nil {
+			return db.Where(table+".company_id = ? AND "+table+".organisation_id IS NULL", userID)
+		}
+		return db.Where("(("+table+".company_id = ? AND "+table+".organisation_id IS NULL) OR "+table+".organisation_id = ?)", userID, *orgID)
+	}
+}
+
+// companyOwns reports whether the company user may act on a row with the given
+// owners; see ownedByCompany
+func companyOwns(userID, companyID uint, organisationID *uint) bool {
+	if organisationID != nil {
+		member, ok := organisationMembership(userID)
+		return ok && member.OrganisationID == *organisationID
+	}
+	return companyID == userID
+}
+
+// canManageListings reports whether the company user may create, edit or delete
+// projects and jobs. Organisation reviewers are limited to reviewing work, and a
+// failed membership lookup denies rather than treating the user as independent.
+// Users outside any organisation manage only their own listings, which
+// ownedByCompany and companyOwns enforce.
+func canManageListings(userID uint) bool {
+	member, ok, err := lookupMembership(userID)
+	if err != nil {
+		return false
+	}
+	return !ok || member.Role != models.OrgRoleReviewer
+}
+
+// RequireListingManager blocks organisation reviewers from listing management routes
+func RequireListingManager() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !canManageListings(c.GetUint("userID")) {
+			apierror.Abort(c, apierror.Forbidden("Reviewers cannot manage projects or jobs"))
+			return
+		}
+		c.Next()
+	}
+}
+
+var slugCleaner = regexp.MustCompile(`[^a-z0-9]+`)
+
+// uniqueOrganisationSlug derives a URL slug from the name, suffixing it when taken
+func uniqueOrganisationSlug(name string) string {
+	base := strings.Trim(slugCleaner.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if base == "" {
+		base = "organisation"
+	}
+	slug := base
+	for i := 2; ; i++ {
+		var count int64
+		DB.Unscoped().Model(&models.Organisation{}).Where("slug = ?", slug).Count(&count)
+		if count == 0 {
+			return slug
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+type organisationProfileInput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	LogoURL     string   `json:"logo_url"`
+	Website     string   `json:"website"`
+	Size        string   `json:"size"`
+	Industry    string   `json:"industry"`
+	Locations   []string `json:"locations"`
+}
+
+// CreateOrganisation - Company user creates an organisation and becomes its owner.
+// Their existing projects and job listings move to the organisation.
+func CreateOrganisation(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	if _, ok := organisationMembership(userID); ok {
+		apierror.Abort(c, apierror.Conflict("You already belong to an organisation"))
+		return
+	}
+
+	var input organisationProfileInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		apierror.Abort(c, apierror.BadRequest("Organisation name is required"))
+		return
+	}
+
+	locations, _ := json.Marshal(input.Locations)
+	organisation := models.Organisation{
+		Name:        strings.TrimSpace(input.Name),
+		Slug:        uniqueOrganisationSlug(input.Name),
+		Description: input.Description,
+		LogoURL:     input.LogoURL,
+		Website:     input.Website,
+		Size:        input.Size,
+		Industry:    input.Industry,
+		Locations:   json.RawMessage(locations),
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&organisation).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.OrganisationMember{
+			OrganisationID: organisation.ID,
+			UserID:         userID,
+			Role:           models.OrgRoleOwner,
+		}).Error; err != nil {
+			return err
+		}
+		return adoptListings(tx, organisation.ID, userID)
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to create organisation"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, organisationResponse{
+		Message:      "Organisation created successfully",
+		Organisation: organisation,
+	})
+}
+
+type organisationResponse struct {
+	Message      string              `json:"message"`
+	Organisation models.Organisation `json:"organisation"`
+}
+
+// adoptListings moves a member's unowned projects and jobs into the organisation
+func adoptListings(tx *gorm.DB, organisationID, userID uint) error {
+	if err := tx.Model(&models.Project{}).
+		Where("company_id = ? AND organisation_id IS NULL", userID).
+		Update("organisation_id", organisationID).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.JobListing{}).
+		Where("company_id = ? AND organisation_id IS NULL", userID).
+		Update("organisation_id", organisationID).Error
+}
+
+// GetMyOrganisation - Company user views their organisation and its members
+func GetMyOrganisation(c *gin.Context) {
+	member, ok := organisationMembership(c.GetUint("userID"))
+	if !ok {
+		apierror.Abort(c, apierror.NotFound("You do not belong to an organisation"))
+		return
+	}
+
+	var organisation models.Organisation
+	if err := DB.Preload("Members.User").First(&organisation, member.OrganisationID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Organisation not found"))
+		return
+	}
+
+	members := make([]organisationMemberResponse, 0, len(organisation.Members))
+	for _, m := range organisation.Members {
+		members = append(members, organisationMemberResponse{
+			UserID:   m.UserID,
+			Name:     m.User.Name,
+			Email:    m.User.Email,
+			Position: m.User.Position,
+			Role:     m.Role,
+			JoinedAt: m.CreatedAt,
+		})
+	}
+	organisation.Members = nil
+
+	c.JSON(http.StatusOK, myOrganisationResponse{
+		Organisation: organisation,
+		Members:      members,
+		MyRole:       member.Role,
+	})
+}
+
+// myOrganisationResponse is the caller's organisation as its members see it
+type myOrganisationResponse struct {
+	Organisation models.Organisation          `json:"organisation"`
+	Members      []organisationMemberResponse `json:"members"`
+	MyRole       string                       `json:"my_role"`
+}
+
+type organisationMemberResponse struct {
+	UserID   uint      `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Position string    `json:"position"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// GetPublicOrganisation - Public organisation profile with its open listings
+func GetPublicOrganisation(c *gin.Context) {
+	var organisation models.Organisation
+	query := DB.Where("slug = ?", c.Param("id"))
+	if id, err := utils.ParseID(c.Param("id")); err == nil {
+		query = DB.Where("id = ?", id)
+	}
+	if err := query.First(&organisation).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Organisation not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, publicOrganisationProfile(organisation))
+}
+
+// publicOrganisation is the part of an organisation anyone may see
+type publicOrganisation struct {
+	ID           uint            `json:"id"`
+	Name         string          `json:"name"`
+	Slug         string          `json:"slug"`
+	Description  string          `json:"description"`
+	LogoURL      string          `json:"logo_url"`
+	Website      string          `json:"website"`
+	Size         string          `json:"size"`
+	Industry     string          `json:"industry"`
+	Locations    json.RawMessage `json:"locations"` // JSON array
+	ActiveJobs   int64           `json:"active_jobs"`
+	OpenProjects int64           `json:"open_projects"`
+	MemberCount  int64           `json:"member_count"`
+}
+
+// publicOrganisationProfile renders the fields of an organisation that anyone may see
+func publicOrganisationProfile(organisation models.Organisation) publicOrganisation {
+	var activeJobs, openProjects, memberCount int64
+	DB.Model(&models.JobListing{}).
+		Where("organisation_id = ? AND is_active = ? AND application_deadline > ?", organisation.ID, true, time.Now()).
+		Count(&activeJobs)
+	DB.Model(&models.Project{}).
+		Where("organisation_id = ? AND deadline > ?", organisation.ID, time.Now()).
+		Count(&openProjects)
+	DB.Model(&models.OrganisationMember{}).Where("organisation_id = ?", organisation.ID).Count(&memberCount)
+
+	return publicOrganisation{
+		ID:           organisation.ID,
+		Name:         organisation.Name,
+		Slug:         organisation.Slug,
+		Description:  organisation.Description,
+		LogoURL:      organisation.LogoURL,
+		Website:      organisation.Website,
+		Size:         organisation.Size,
+		Industry:     organisation.Industry,
+		Locations:    organisation.Locations,
+		ActiveJobs:   activeJobs,
+		OpenProjects: openProjects,
+		MemberCount:  memberCount,
+	}
+}
+
+// requireOrganisationOwner loads the caller's membership and checks they are an owner
+func requireOrganisationOwner(c *gin.Context) (models.OrganisationMember, bool) {
+	member, ok := organisationMembership(c.GetUint("userID"))
+	if !ok {
+		apierror.Abort(c, apierror.NotFound("You do not belong to an organisation"))
+		return member, false
+	}
+	if member.Role != models.OrgRoleOwner {
+		apierror.Abort(c, apierror.Forbidden("Only organisation owners can do this"))
+		return member, false
+	}
+	return member, true
+}
+
+// UpdateOrganisation - Owner edits the organisation profile; omitted fields are unchanged
+func UpdateOrganisation(c *gin.Context) {
+	member, ok := requireOrganisationOwner(c)
+	if !ok {
+		return
+	}
+
+	var input organisationProfileInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.Name != "" {
+		updates["name"] = strings.TrimSpace(input.Name)
+	}
+	if input.Description != "" {
+		updates["description"] = input.Description
+	}
+	if input.LogoURL != "" {
+		updates["logo_url"] = input.LogoURL
+	}
+	if input.Website != "" {
+		updates["website"] = input.Website
+	}
+	if input.Size != "" {
+		updates["size"] = input.Size
+	}
+	if input.Industry != "" {
+		updates["industry"] = input.Industry
+	}
+	if input.Locations != nil {
+		locations, _ := json.Marshal(input.Locations)
+		updates["locations"] = json.RawMessage(locations)
+	}
+
+	var organisation models.Organisation
+	if err := DB.First(&organisation, member.OrganisationID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Organisation not found"))
+		return
+	}
+	if err := DB.Model(&organisation).Updates(updates).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to update organisation"))
+		return
+	}
+
+	c.JSON(http.StatusOK, organisationResponse{
+		Message:      "Organisation updated successfully",
+		Organisation: organisation,
+	})
+}
+
+func validOrganisationRole(role string) bool {
+	return role == models.OrgRoleOwner || role == models.OrgRoleRecruiter || role == models.OrgRoleReviewer
+}
+
+// invitationCreatedResponse carries the invite token, which is only shown once
+type invitationCreatedResponse struct {
+	Message    string                        `json:"message"`
+	Invitation models.OrganisationInvitation `json:"invitation"`
+	Token      string                        `json:"token"`
+}
+
+type organisationInvitationsResponse struct {
+	Invitations []models.OrganisationInvitation `json:"invitations"`
+	Count       int                             `json:"count"`
+}
+
+type inviteMemberInput struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+// InviteOrganisationMember - Owner invites a company user by email
+func InviteOrganisationMember(c *gin.Context) {
+	member, ok := requireOrganisationOwner(c)
+	if !ok {
+		return
+	}
+
+	var input inviteMemberInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if !validOrganisationRole(input.Role) {
+		apierror.Abort(c, apierror.BadRequest("Role must be owner, recruiter or reviewer"))
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+
+	var pending int64
+	DB.Model(&models.OrganisationInvitation{}).
+		Where("organisation_id = ? AND email = ? AND status = ? AND expires_at > ?", member.OrganisationID, email, "pending", time.Now()).
+		Count(&pending)
+	if pending > 0 {
+		apierror.Abort(c, apierror.Conflict("An invitation is already pending for this email"))
+		return
+	}
+
+	var invitee models.User
+	inviteeExists := DB.Where("email = ?", email).First(&invitee).Error == nil
+	if inviteeExists {
+		if invitee.Role != "company" {
+			apierror.Abort(c, apierror.BadRequest("Only company accounts can join an organisation"))
+			return
+		}
+		if _, ok := organisationMembership(invitee.ID); ok {
+			apierror.Abort(c, apierror.Conflict("This user already belongs to an organisation"))
+			return
+		}
+	}
+
+	token := make([]byte, 24)
+	rand.Read(token)
+	invitation := models.OrganisationInvitation{
+		OrganisationID: member.OrganisationID,
+		Email:          email,
+		Role:           input.Role,
+		Token:          hex.EncodeToString(token),
+		InvitedByID:    member.UserID,
+		Status:         "pending",
+		ExpiresAt:      time.Now().Add(organisationInviteTTL),
+	}
+	if err := DB.Create(&invitation).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to create invitation"))
+		return
+	}
+
+	if inviteeExists {
+		var organisation models.Organisation
+		DB.First(&organisation, member.OrganisationID)
+		if err := utils.CreateNotification(DB, invitee.ID, "You have been invited to join "+organisation.Name+" as "+input.Role); err != nil {
+			middleware.Log(c).Error("failed to send notification", "error", err)
+		}
+	}
+
+	// The token is returned once so the owner can share the invite link
+	c.JSON(http.StatusCreated, invitationCreatedResponse{
+		Message:    "Invitation created successfully",
+		Invitation: invitation,
+		Token:      invitation.Token,
+	})
+}
+
+// GetOrganisationInvitations - Owner lists invitations for their organisation
+func GetOrganisationInvitations(c *gin.Context) {
+	member, ok := requireOrganisationOwner(c)
+	if !ok {
+		return
+	}
+
+	var invitations []models.OrganisationInvitation
+	if err := DB.Where("organisation_id = ?", member.OrganisationID).Order("created_at DESC").Find(&invitations).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch invitations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, organisationInvitationsResponse{Invitations: invitations, Count: len(invitations)})
+}
+
+// GetMyOrganisationInvitations - Company user lists pending invitations addressed to them
+func GetMyOrganisationInvitations(c *gin.Context) {
+	var user models.User
+	if err := DB.First(&user, c.GetUint("userID")).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("User not found"))
+		return
+	}
+
+	var invitations []models.OrganisationInvitation
+	if err := DB.Preload("Organisation").
+		Where("email = ? AND status = ? AND expires_at > ?", strings.ToLower(user.Email), "pending", time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch invitations"))
+		return
+	}
+
+	c.JSON(http.StatusOK, organisationInvitationsResponse{Invitations: invitations, Count: len(invitations)})
+}
+
+// RevokeOrganisationInvitation - Owner cancels a pending invitation
+func RevokeOrganisationInvitation(c *gin.Context) {
+	member, ok := requireOrganisationOwner(c)
+	if !ok {
+		return
+	}
+
+	result := DB.Model(&models.OrganisationInvitation{}).
+		Where("id = ? AND organisation_id = ? AND status = ?", c.Param("id"), member.OrganisationID, "pending").
+		Update("status", "revoked")
+	if result.Error != nil {
+		apierror.Abort(c, apierror.Internal("Failed to revoke invitation"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		apierror.Abort(c, apierror.NotFound("Pending invitation not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Invitation revoked successfully"})
+}
+
+type acceptInvitationResponse struct {
+	Message        string `json:"message"`
+	OrganisationID uint   `json:"organisation_id"`
+	Role           string `json:"role"`
+}
+
+type acceptInvitationInput struct {
+	Token        string `json:"token"`
+	InvitationID uint   `json:"invitation_id"`
+}
+
+// AcceptOrganisationInvitation - Company user joins an organisation. The invitation can be
+// identified by its token (from an invite link) or by ID when addressed to the user's email.
+func AcceptOrganisationInvitation(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input acceptInvitationInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if input.Token == "" && input.InvitationID == 0 {
+		apierror.Abort(c, apierror.BadRequest("token or invitation_id is required"))
+		return
+	}
+
+	var user models.User
+	if err := DB.First(&user, userID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("User not found"))
+		return
+	}
+	if _, ok := organisationMembership(userID); ok {
+		apierror.Abort(c, apierror.Conflict("You already belong to an organisation"))
+		return
+	}
+
+	var invitation models.OrganisationInvitation
+	query := DB.Preload("Organisation").Where("status = ?", "pending")
+	if input.Token != "" {
+		query = query.Where("token = ?", input.Token)
+	} else {
+		query = query.Where("id = ?", input.InvitationID)
+	}
+	if err := query.First(&invitation).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Invitation not found"))
+		return
+	}
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		apierror.Abort(c, apierror.Forbidden("This invitation was sent to a different email address"))
+		return
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		apierror.Abort(c, apierror.Gone("Invitation has expired"))
+		return
+	}
+
+	now := time.Now()
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.OrganisationMember{
+			OrganisationID: invitation.OrganisationID,
+			UserID:         userID,
+			Role:           invitation.Role,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&invitation).Updates(map[string]interface{}{"status": "accepted", "accepted_at": now}).Error; err != nil {
+			return err
+		}
+		return adoptListings(tx, invitation.OrganisationID, userID)
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to join organisation"))
+		return
+	}
+
+	if err := utils.CreateNotification(DB, invitation.InvitedByID, user.Name+" joined "+invitation.Organisation.Name); err != nil {
+		middleware.Log(c).Error("failed to send notification", "error", err)
+	}
+
+	c.JSON(http.StatusOK, acceptInvitationResponse{
+		Message:        "Joined organisation successfully",
+		OrganisationID: invitation.OrganisationID,
+		Role:           invitation.Role,
+	})
+}
+
+// ownerCount returns how many owners the organisation has
+func ownerCount(organisationID uint) int64 {
+	var count int64
+	DB.Model(&models.OrganisationMember{}).Where("organisation_id = ? AND role = ?", organisationID, models.OrgRoleOwner).Count(&count)
+	return count
+}
+
+type memberRoleResponse struct {
+	Message string `json:"message"`
+	UserID  uint   `json:"user_id"`
+	Role    string `json:"role"`
+}
+
+type memberRoleInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// UpdateOrganisationMemberRole - Owner changes a member's role
+func UpdateOrganisationMemberRole(c *gin.Context) {
+	owner, ok := requireOrganisationOwner(c)
+	if !ok {
+		return
+	}
+
+	var input memberRoleInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if !validOrganisationRole(input.Role) {
+		apierror.Abort(c, apierror.BadRequest("Role must be owner, recruiter or reviewer"))
+		return
+	}
+
+	var member models.OrganisationMember
+	if err := DB.Where("organisation_id = ? AND user_id = ?", owner.OrganisationID, c.Param("user_id")).First(&member).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Member not found"))
+		return
+	}
+	if member.Role == models.OrgRoleOwner && input.Role != models.OrgRoleOwner && ownerCount(owner.OrganisationID) <= 1 {
+		apierror.Abort(c, apierror.Conflict("An organisation must keep at least one owner"))
+		return
+	}
+
+	if err := DB.Model(&member).Update("role", input.Role).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to update member role"))
+		return
+	}
+
+	c.JSON(http.StatusOK, memberRoleResponse{
+		Message: "Member role updated successfully",
+		UserID:  member.UserID,
+		Role:    input.Role,
+	})
+}
+
+// RemoveOrganisationMember - Owner removes a member, or a member leaves.
+// Listings stay with the organisation; the author keeps their authorship.
+func RemoveOrganisationMember(c *gin.Context) {
+	userID := c.GetUint("userID")
+	caller, ok := organisationMembership(userID)
+	if !ok {
+		apierror.Abort(c, apierror.NotFound("You do not belong to an organisation"))
+		return
+	}
+
+	targetID, err := utils.ParseID(c.Param("user_id"))
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Invalid user ID"))
+		return
+	}
+	if targetID != userID && caller.Role != models.OrgRoleOwner {
+		apierror.Abort(c, apierror.Forbidden("Only organisation owners can remove other members"))
+		return
+	}
+
+	var member models.OrganisationMember
+	if err := DB.Where("organisation_id = ? AND user_id = ?", caller.OrganisationID, targetID).First(&member).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Member not found"))
+		return
+	}
+	if member.Role == models.OrgRoleOwner && ownerCount(caller.OrganisationID) <= 1 {
+		apierror.Abort(c, apierror.Conflict("An organisation must keep at least one owner"))
+		return
+	}
+
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&member).Error; err != nil {
+			return err
+		}
+		// Keys made for the organisation stop working with the membership
+		if err := tx.Model(&models.APIKey{}).
+			Where("user_id = ? AND organisation_id = ? AND revoked_at IS NULL", member.UserID, member.OrganisationID).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+		// The organisation's webhooks keep running, owned by one of its owners
+		var owner models.OrganisationMember
+		if err := tx.Where("organisation_id = ? AND role = ?", member.OrganisationID, models.OrgRoleOwner).Order("id ASC").First(&owner).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.WebhookEndpoint{}).
+			Where("company_id = ? AND organisation_id = ?", member.UserID, member.OrganisationID).
+			Update("company_id", owner.UserID).Error
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to remove member"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Member removed successfully"})
+}
@@ -0,0 +1,520 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/models"
+	"SkillBridge/notify"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxBulkMove caps how many applications can be moved in one request
+const maxBulkMove = 200
+
+// jobPipeline returns the job's stages in order, falling back to the default pipeline
+func jobPipeline(tx *gorm.DB, jobListingID uint) ([]models.PipelineStage, error) {
+	var stages []models.PipelineStage
+	if err := tx.Where("job_listing_id = ?", jobListingID).Order("position ASC").Find(&stages).Error; err != nil {
+		return nil, err
+	}
+	if len(stages) == 0 {
+		return models.DefaultPipelineStages(jobListingID), nil
+	}
+	return stages, nil
+}
+
+// findStage looks a stage up by name, ignoring case
+func findStage(stages []models.PipelineStage, name string) (models.PipelineStage, bool) {
+	name = strings.TrimSpace(name)
+	for _, stage := range stages {
+		if strings.EqualFold(stage.Name, name) {
+			return stage, true
+		}
+	}
+	return models.PipelineStage{}, false
+}
+
+func stageNames(stages []models.PipelineStage) string {
+	names := make([]string, len(stages))
+	for i, stage := range stages {
+		names[i] = stage.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// unknownStageError rejects a move to a stage the job's pipeline doesn't have
+type unknownStageError struct {
+	Stages []models.PipelineStage
+}
+
+func (e *unknownStageError) Error() string {
+	return "Must be one of: " + stageNames(e.Stages)
+}
+
+// errApplicationsNotInJob rejects a bulk move naming another job's applications
+var errApplicationsNotInJob = errors.New("Some applications do not belong to this job")
+
+// targetStage reads the job's pipeline inside tx and finds the named stage.
+// Callers lock the applications being moved first; UpdateJobPipeline takes the
+// same locks, so the stage cannot be removed before the move commits.
+func targetStage(tx *gorm.DB, jobListingID uint, name string) (models.PipelineStage, error) {
+	stages, err := jobPipeline(tx, jobListingID)
+	if err != nil {
+		return models.PipelineStage{}, err
+	}
+	stage, ok := findStage(stages, name)
+	if !ok {
+		return models.PipelineStage{}, &unknownStageError{Stages: stages}
+	}
+	return stage, nil
+}
+
+// moveApplication puts an application into a stage and records the move in its history.
+// Moving to the stage it is already in is a no-op and returns false.
+func moveApplication(tx *gorm.DB, application *models.JobApplication, stage models.PipelineStage, reason string, movedByID uint) (bool, error) {
+	if application.Status == stage.Name {
+		return false, nil
+	}
+
+	now := time.Now()
+	rejectionReason := ""
+	if stage.Kind == models.StageKindRejected {
+		rejectionReason = reason
+	}
+	if err := tx.Model(application).Updates(map[string]interface{}{
+		"status":           stage.Name,
+		"stage_changed_at": now,
+		"rejection_reason": rejectionReason,
+		"updated_at":       now,
+	}).Error; err != nil {
+		return false, err
+	}
+
+	event := models.ApplicationStageEvent{
+		JobApplicationID: application.ID,
+		FromStage:        application.Status,
+		ToStage:          stage.Name,
+		Reason:           reason,
+		MovedByID:        &movedByID,
+	}
+	if err := tx.Create(&event).Error; err != nil {
+		return false, err
+	}
+
+	job := application.JobListing
+	if job.ID == 0 {
+		tx.Select("id", "company_id", "organisation_id").First(&job, application.JobListingID)
+	}
+	emitJobWebhook(tx, job, webhookApplicationStatus, gin.H{
+		"application_id": application.ID,
+		"job_id":         application.JobListingID,
+		"applicant_id":   application.UserID,
+		"from_status":    application.Status,
+		"to_status":      stage.Name,
+		"stage_kind":     stage.Kind,
+		"reason":         reason,
+		"changed_at":     now,
+	})
+
+	application.Status = stage.Name
+	application.StageChangedAt = &now
+	application.RejectionReason = rejectionReason
+	return true, nil
+}
+
+// notifyStageChange tells the applicant their application moved
+func notifyStageChange(application models.JobApplication, job models.JobListing, stage models.PipelineStage) {
+	if err := notify.Send(DB, application.UserID, stageChangeNotice(job, stage)); err != nil {
+		slog.Error("failed to send notification", "error", err)
+	}
+}
+
+// stageChangeNotice is the notification a candidate gets when their application changes stage
+func stageChangeNotice(job models.JobListing, stage models.PipelineStage) notify.Message {
+	var text string
+	switch stage.Kind {
+	case models.StageKindHired:
+		text = "Congratulations! Your application for " + job.Title + " was accepted"
+	case models.StageKindRejected:
+		text = "Your application for " + job.Title + " was not taken forward"
+	default:
+		text = "Your application for " + job.Title + " moved to " + stage.Name
+	}
+	return notify.Message{
+		Event: notify.EventApplicationStatus,
+		Text:  text,
+		Data:  map[string]string{"job": job.Title, "stage": stage.Name},
+		Link:  "/jobs",
+	}
+}
+
+// GetJobPipeline - Company views the hiring stages of one of its jobs
+func GetJobPipeline(c *gin.Context) {
+	companyID := c.GetUint("userID")
+
+	var job models.JobListing
+	if err := DB.Scopes(ownedByCompany("job_listings", companyID)).Where("id = ?", c.Param("id")).First(&job).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Job listing not found or unauthorized"))
+		return
+	}
+
+	stages, err := jobPipeline(DB, job.ID)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch pipeline"))
+		return
+	}
+
+	var custom int64
+	DB.Model(&models.PipelineStage{}).Where("job_listing_id = ?", job.ID).Count(&custom)
+
+	c.JSON(http.StatusOK, pipelineResponse{
+		JobID:     job.ID,
+		Stages:    stages,
+		IsDefault: custom == 0,
+	})
+}
+
+type pipelineResponse struct {
+	JobID     uint                   `json:"job_id"`
+	Stages    []models.PipelineStage `json:"stages"`
+	IsDefault bool                   `json:"is_default"`
+}
+
+type updatePipelineResponse struct {
+	Message string                 `json:"message"`
+	Stages  []models.PipelineStage `json:"stages"`
+}
+
+// stageInUseError stops a pipeline update that would remove a stage still
+// holding applications
+type stageInUseError struct {
+	Stage string
+	Count int64
+}
+
+func (e *stageInUseError) Error() string {
+	return fmt.Sprintf("Stage %q still has %d application(s); move them before removing it", e.Stage, e.Count)
+}
+
+// UpdateJobPipeline - Company replaces the hiring stages of a job. The first stage
+// receives new applicants. Stages that still hold applications cannot be removed.
+func UpdateJobPipeline(c *gin.Context) {
+	companyID := c.GetUint("userID")
+
+	var job models.JobListing
+	if err := DB.Scopes(ownedByCompany("job_listings", companyID)).Where("id = ?", c.Param("id")).First(&job).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Job listing not found or unauthorized"))
+		return
+	}
+
+	var req models.UpdatePipelineRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if len(req.Stages) < 2 {
+		apierror.Abort(c, apierror.BadRequest("A pipeline needs at least two stages"))
+		return
+	}
+
+	stages := make([]models.PipelineStage, 0, len(req.Stages))
+	seen := map[string]bool{}
+	hasRejected := false
+	for i, input := range req.Stages {
+		name := strings.TrimSpace(input.Name)
+		if name == "" || len(name) > 100 {
+			apierror.Abort(c, apierror.BadRequest("Stage names must be between 1 and 100 characters"))
+			return
+		}
+		if seen[strings.ToLower(name)] {
+			apierror.Abort(c, apierror.BadRequest("Duplicate stage: "+name))
+			return
+		}
+		seen[strings.ToLower(name)] = true
+
+		kind := input.Kind
+		if kind == "" {
+			kind = models.StageKindOpen
+		}
+		if kind != models.StageKindOpen && kind != models.StageKindHired && kind != models.StageKindRejected {
+			apierror.Abort(c, apierror.BadRequest("Stage kind must be open, hired or rejected"))
+			return
+		}
+		if i == 0 && kind != models.StageKindOpen {
+			apierror.Abort(c, apierror.BadRequest("The first stage must be an open stage"))
+			return
+		}
+		if kind == models.StageKindRejected {
+			hasRejected = true
+		}
+		stages = append(stages, models.PipelineStage{JobListingID: job.ID, Name: name, Kind: kind, Position: i})
+	}
+	if !hasRejected {
+		apierror.Abort(c, apierror.BadRequest("A pipeline needs a rejected stage"))
+		return
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the applications so none moves into a stage while it is removed
+		var applications []models.JobApplication
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			Where("job_listing_id = ?", job.ID).
+			Find(&applications).Error; err != nil {
+			return err
+		}
+
+		// Applications must always sit in a stage that exists
+		var inUse *stageInUseError
+		recase := map[string][]uint{}
+		for _, application := range applications {
+			stage, ok := findStage(stages, application.Status)
+			if !ok {
+				if inUse == nil {
+					inUse = &stageInUseError{Stage: application.Status}
+				}
+				if application.Status == inUse.Stage {
+					inUse.Count++
+				}
+				continue
+			}
+			if application.Status != stage.Name {
+				recase[stage.Name] = append(recase[stage.Name], application.ID)
+			}
+		}
+		if inUse != nil {
+			return inUse
+		}
+
+		if err := tx.Where("job_listing_id = ?", job.ID).Delete(&models.PipelineStage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&stages).Error; err != nil {
+			return err
+		}
+		// Keep stored stage names in the pipeline's casing
+		for name, ids := range recase {
+			if err := tx.Model(&models.JobApplication{}).Where("id IN ?", ids).Update("status", name).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	var inUse *stageInUseError
+	if errors.As(err, &inUse) {
+		apierror.Abort(c, apierror.Conflict(inUse.Error()).WithDetails(gin.H{"stage": inUse.Stage, "applications": inUse.Count}))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to update pipeline"))
+		return
+	}
+
+	c.JSON(http.StatusOK, updatePipelineResponse{
+		Message: "Pipeline updated successfully",
+		Stages:  stages,
+	})
+}
+
+type bulkMoveResponse struct {
+	Message string `json:"message"`
+	Status  string `json:"status"`
+	Moved   int    `json:"moved"`
+	Skipped int    `json:"skipped"`
+}
+
+// BulkMoveApplications - Company moves several applications of a job to one stage
+func BulkMoveApplications(c *gin.Context) {
+	companyID := c.GetUint("userID")
+
+	var job models.JobListing
+	if err := DB.Scopes(ownedByCompany("job_listings", companyID)).Where("id = ?", c.Param("id")).First(&job).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Job listing not found or unauthorized"))
+		return
+	}
+
+	var req models.BulkMoveApplicationsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+	if len(req.ApplicationIDs) == 0 || len(req.ApplicationIDs) > maxBulkMove {
+		apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("Provide between 1 and %d application IDs", maxBulkMove)))
+		return
+	}
+
+	var (
+		stage        models.PipelineStage
+		applications []models.JobApplication
+		moved        []models.JobApplication
+	)
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("job_listing_id = ? AND id IN ?", job.ID, req.ApplicationIDs).
+			Find(&applications).Error; err != nil {
+			return err
+		}
+		if len(applications) != len(uniqueIDs(req.ApplicationIDs)) {
+			return errApplicationsNotInJob
+		}
+		var err error
+		if stage, err = targetStage(tx, job.ID, req.Status); err != nil {
+			return err
+		}
+
+		for i := range applications {
+			changed, err := moveApplication(tx, &applications[i], stage, req.Reason, companyID)
+			if err != nil {
+				return err
+			}
+			if changed {
+				moved = append(moved, applications[i])
+			}
+		}
+		// Candidates are notified in the background, and only if the move commits
+		userIDs := make([]uint, 0, len(moved))
+		for _, application := range moved {
+			userIDs = append(userIDs, application.UserID)
+		}
+		return enqueueNotification(tx, userIDs, stageChangeNotice(job, stage))
+	})
+	var unknown *unknownStageError
+	if errors.As(err, &unknown) {
+		apierror.Abort(c, apierror.BadRequest("Invalid stage. "+unknown.Error()))
+		return
+	}
+	if errors.Is(err, errApplicationsNotInJob) {
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to move applications"))
+		return
+	}
+
+	c.JSON(http.StatusOK, bulkMoveResponse{
+		Message: fmt.Sprintf("Moved %d application(s) to %s", len(moved), stage.Name),
+		Status:  stage.Name,
+		Moved:   len(moved),
+		Skipped: len(applications) - len(moved),
+	})
+}
+
+type applicationHistoryResponse struct {
+	ApplicationID uint                           `json:"application_id"`
+	Status        string                         `json:"status"`
+	History       []models.ApplicationStageEvent `json:"history"`
+}
+
+// GetApplicationHistory - Company views the stage history of an application
+func GetApplicationHistory(c *gin.Context) {
+	companyID := c.GetUint("userID")
+
+	var application models.JobApplication
+	if err := DB.Preload("JobListing").First(&application, c.Param("id")).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Application not found"))
+		return
+	}
+	if !companyOwns(companyID, application.JobListing.CompanyID, application.JobListing.OrganisationID) {
+		apierror.Abort(c, apierror.Forbidden("Unauthorized"))
+		return
+	}
+
+	var events []models.ApplicationStageEvent
+	if err := DB.Preload("MovedBy", selectPublicUser).
+		Where("job_application_id = ?", application.ID).
+		Order("created_at ASC, id ASC").
+		Find(&events).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch history"))
+		return
+	}
+
+	c.JSON(http.StatusOK, applicationHistoryResponse{
+		ApplicationID: application.ID,
+		Status:        application.Status,
+		History:       events,
+	})
+}
+
+// stageCount is one row of the application funnel
+type stageCount struct {
+	Stage   string `json:"stage"`
+	Kind    string `json:"kind"`
+	Current int64  `json:"current"`
+	Entered int64  `json:"entered"`
+}
+
+// stageFunnel counts applications currently in, and ever moved into, each stage.
+// base must select from job_applications joined to job_listings.
+func stageFunnel(base func() *gorm.DB, stages []models.PipelineStage) ([]stageCount, error) {
+	var current []struct {
+		Status string
+		Count  int64
+	}
+	if err := base().Select("job_applications.status AS status, COUNT(*) AS count").
+		Group("job_applications.status").
+		Scan(&current).Error; err != nil {
+		return nil, err
+	}
+
+	var entered []struct {
+		ToStage string
+		Count   int64
+	}
+	if err := base().Joins("JOIN application_stage_events ON application_stage_events.job_application_id = job_applications.id").
+		Select("application_stage_events.to_stage AS to_stage, COUNT(DISTINCT job_applications.id) AS count").
+		Group("application_stage_events.to_stage").
+		Scan(&entered).Error; err != nil {
+		return nil, err
+	}
+
+	currentBy := map[string]int64{}
+	for _, row := range current {
+		currentBy[row.Status] = row.Count
+	}
+	enteredBy := map[string]int64{}
+	for _, row := range entered {
+		enteredBy[row.ToStage] = row.Count
+	}
+
+	// Stages that exist only on some jobs, or only in old data, are listed after the pipeline
+	funnel := make([]stageCount, 0, len(stages))
+	listed := map[string]bool{}
+	addStage := func(name, kind string) {
+		listed[name] = true
+		funnel = append(funnel, stageCount{
+			Stage:   name,
+			Kind:    kind,
+			Current: currentBy[name],
+			Entered: enteredBy[name],
+		})
+	}
+	for _, stage := range stages {
+		addStage(stage.Name, stage.Kind)
+	}
+	for _, row := range current {
+		if !listed[row.Status] {
+			addStage(row.Status, "")
+		}
+	}
+	return funnel, nil
+}
+
+// uniqueIDs removes duplicate IDs
+func uniqueIDs(ids []uint) []uint {
+	seen := map[uint]bool{}
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
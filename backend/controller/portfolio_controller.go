@@ -0,0 +1,431 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"SkillBridge/utils"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loadPortfolioSettings returns the student's saved settings or the defaults
+func loadPortfolioSettings(userID uint) models.PortfolioSettings {
+	var settings models.PortfolioSettings
+	if err := DB.Where("user_id = ?", userID).First(&settings).Error; err != nil {
+		return models.DefaultPortfolioSettings(userID)
+	}
+	return settings
+}
+
+// studentPortfolio is a student's public profile. Sections the student has
+// hidden are left out (nil).
+type studentPortfolio struct {
+	ID           uint                    `json:"id"`
+	Name         string                  `json:"name"`
+	Bio          string                  `json:"bio"`
+	Picture      string                  `json:"picture"`
+	Email        *string                 `json:"email,omitempty"`
+	Phone        *string                 `json:"phone,omitempty"`
+	GithubURL    *string                 `json:"github_url,omitempty"`
+	LinkedInURL  *string                 `json:"linkedIn_url,omitempty"`
+	PortfolioURL *string                 `json:"portfolio_url,omitempty"`
+	University   *string                 `json:"university,omitempty"`
+	Major        *string                 `json:"major,omitempty"`
+	Year         *string                 `json:"year,omitempty"`
+	Projects     *[]portfolioProject     `json:"projects,omitempty"`
+	Certificates *[]portfolioCertificate `json:"certificates,omitempty"`
+	Skills       *[]skillEvidence        `json:"skills,omitempty"`
+	Endorsements *[]portfolioEndorsement `json:"endorsements,omitempty"`
+	GithubStats  *utils.GitHubUserStats  `json:"github_stats,omitempty"`
+	// Privacy is only shown to the student themselves
+	Privacy *models.PortfolioSettings `json:"privacy,omitempty"`
+}
+
+// portfolioProject is an accepted submission. Scores are left out when hidden
+// or not reviewed yet.
+type portfolioProject struct {
+	ProjectID    uint      `json:"project_id"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Skills       []string  `json:"skills"`
+	GithubURL    string    `json:"github_url"`
+	DemoURL      string    `json:"demo_url"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+	AverageScore *float64  `json:"average_score,omitempty"`
+	ReviewCount  *int      `json:"review_count,omitempty"`
+}
+
+type portfolioCertificate struct {
+	CertificateID string    `json:"certificate_id"`
+	ProjectTitle  string    `json:"project_title"`
+	CompanyName   string    `json:"company_name"`
+	CompletedAt   time.Time `json:"completed_at"`
+	VerifyURL     string    `json:"verify_url"`
+}
+
+type portfolioEndorsement struct {
+	ID        uint       `json:"id"`
+	Skill     string     `json:"skill"`
+	Comment   string     `json:"comment"`
+	ProjectID *uint      `json:"project_id"`
+	Guide     endorsedBy `json:"guide"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type endorsedBy struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Picture string `json:"picture"`
+}
+
+// skillEvidence is a skill with the accepted projects and endorsements that demonstrate it
+type skillEvidence struct {
+	Name             string         `json:"name"`
+	DemonstratedIn   []skillProject `json:"demonstrated_in"`
+	VerifiedByWork   bool           `json:"verified_by_work"`
+	EndorsementCount *int           `json:"endorsement_count,omitempty"` // left out when endorsements are hidden
+}
+
+type skillProject struct {
+	ProjectID uint   `json:"project_id"`
+	Title     string `json:"title"`
+}
+
+// buildPortfolio aggregates a student's completed work, honouring their privacy settings.
+// The student themselves always sees every section.
+func buildPortfolio(c *gin.Context, user models.User) studentPortfolio {
+	viewerID, _, _ := middleware.OptionalUser(c)
+	settings := loadPortfolioSettings(user.ID)
+	isOwner := viewerID == user.ID
+	show := func(visible bool) bool { return isOwner || visible }
+
+	portfolio := studentPortfolio{
+		ID:      user.ID,
+		Name:    user.Name,
+		Bio:     user.Bio,
+		Picture: user.Picture,
+	}
+	if show(settings.ShowEmail) {
+		portfolio.Email = &user.Email
+	}
+	if show(settings.ShowPhone) {
+		portfolio.Phone = &user.Phone
+	}
+	if show(settings.ShowLinks) {
+		portfolio.GithubURL = &user.GithubURL
+		portfolio.LinkedInURL = &user.LinkedIn
+		portfolio.PortfolioURL = &user.PortfolioURL
+	}
+	if show(settings.ShowEducation) {
+		portfolio.University = &user.University
+		portfolio.Major = &user.Major
+		portfolio.Year = &user.Year
+	}
+
+	// Accepted submissions are the evidence everything else is built on
+	var submissions []models.Submission
+	if err := DB.Preload("Project").Preload("Reviews").
+		Where("student_id = ? AND status = ?", user.ID, "accepted").
+		Order("submitted_at DESC").
+		Find(&submissions).Error; err != nil {
+		middleware.Log(c).Error("failed to load portfolio submissions", "user_id", user.ID, "error", err)
+	}
+
+	if show(settings.ShowProjects) {
+		projects := make([]portfolioProject, 0, len(submissions))
+		for _, submission := range submissions {
+			entry := portfolioProject{
+				ProjectID:   submission.ProjectID,
+				Title:       submission.Project.Title,
+				Description: submission.Project.Description,
+				Skills:      utils.ParseSkills(submission.Project.Skills),
+				GithubURL:   submission.GithubURL,
+				DemoURL:     submission.DemoURL,
+				SubmittedAt: submission.SubmittedAt,
+			}
+			if show(settings.ShowScores) && len(submission.Reviews) > 0 {
+				var total float64
+				for _, review := range submission.Reviews {
+					total += review.OverallScore
+				}
+				average := total / float64(len(submission.Reviews))
+				count := len(submission.Reviews)
+				entry.AverageScore = &average
+				entry.ReviewCount = &count
+			}
+			projects = append(projects, entry)
+		}
+		portfolio.Projects = &projects
+	}
+
+	if show(settings.ShowCertificates) {
+		var certificates []models.Certificate
+		DB.Where("student_id = ? AND revoked = ?", user.ID, false).Order("issued_at DESC").Find(&certificates)
+		entries := make([]portfolioCertificate, 0, len(certificates))
+		for _, certificate := range certificates {
+			entries = append(entries, portfolioCertificate{
+				CertificateID: certificate.CertificateID,
+				ProjectTitle:  certificate.ProjectTitle,
+				CompanyName:   certificate.CompanyName,
+				CompletedAt:   certificate.CompletedAt,
+				VerifyURL:     "/api/certificates/" + certificate.CertificateID + "/verify",
+			})
+		}
+		portfolio.Certificates = &entries
+	}
+
+	var endorsements []models.GuideEndorsement
+	if show(settings.ShowSkills) || show(settings.ShowEndorsements) {
+		DB.Preload("Guide").Where("student_id = ?", user.ID).Order("created_at DESC").Find(&endorsements)
+	}
+
+	if show(settings.ShowSkills) {
+		skills := collectSkillEvidence(user, submissions, endorsements, show(settings.ShowEndorsements))
+		portfolio.Skills = &skills
+	}
+
+	if show(settings.ShowEndorsements) {
+		entries := make([]portfolioEndorsement, 0, len(endorsements))
+		for _, endorsement := range endorsements {
+			entries = append(entries, portfolioEndorsement{
+				ID:        endorsement.ID,
+				Skill:     endorsement.Skill,
+				Comment:   endorsement.Comment,
+				ProjectID: endorsement.ProjectID,
+				Guide:     endorsedBy{ID: endorsement.GuideID, Name: endorsement.Guide.Name, Picture: endorsement.Guide.Picture},
+				CreatedAt: endorsement.CreatedAt,
+			})
+		}
+		portfolio.Endorsements = &entries
+	}
+
+	if show(settings.ShowGithubStats) {
+		// Stats are fetched in the background; the first view may not have them yet
+		if extractGithubUsername(user.GithubURL) != "" {
+			portfolio.GithubStats = cachedGithubStats(settings)
+		}
+	}
+
+	if isOwner {
+		portfolio.Privacy = &settings
+	}
+	return portfolio
+}
+
+// collectSkillEvidence maps each skill to the accepted projects and endorsements that demonstrate it
+func collectSkillEvidence(user models.User, submissions []models.Submission, endorsements []models.GuideEndorsement, includeEndorsements bool) []skillEvidence {
+	type evidence struct {
+		name         string
+		projects     []skillProject
+		endorsements int
+	}
+	var order []string
+	bySkill := map[string]*evidence{}
+	add := func(skill string) *evidence {
+		key := strings.ToLower(skill)
+		if e, ok := bySkill[key]; ok {
+			return e
+		}
+		e := &evidence{name: skill, projects: []skillProject{}}
+		bySkill[key] = e
+		order = append(order, key)
+		return e
+	}
+
+	for _, skill := range utils.ParseSkills(user.Skills) {
+		add(skill)
+	}
+	for _, submission := range submissions {
+		for _, skill := range utils.ParseSkills(submission.Project.Skills) {
+			e := add(skill)
+			e.projects = append(e.projects, skillProject{ProjectID: submission.ProjectID, Title: submission.Project.Title})
+		}
+	}
+	if includeEndorsements {
+		for _, endorsement := range endorsements {
+			add(endorsement.Skill).endorsements++
+		}
+	}
+
+	result := make([]skillEvidence, 0, len(order))
+	for _, key := range order {
+		e := bySkill[key]
+		entry := skillEvidence{
+			Name:           e.name,
+			DemonstratedIn: e.projects,
+			VerifiedByWork: len(e.projects) > 0,
+		}
+		if includeEndorsements {
+			entry.EndorsementCount = &e.endorsements
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// GetPortfolioSettings - Student reads their portfolio privacy settings
+func GetPortfolioSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, portfolioSettingsResponse{Settings: loadPortfolioSettings(c.GetUint("userID"))})
+}
+
+type portfolioSettingsResponse struct {
+	Message  string                   `json:"message,omitempty"`
+	Settings models.PortfolioSettings `json:"settings"`
+}
+
+type portfolioSettingsInput struct {
+	ShowEmail        *bool `json:"show_email"`
+	ShowPhone        *bool `json:"show_phone"`
+	ShowEducation    *bool `json:"show_education"`
+	ShowLinks        *bool `json:"show_links"`
+	ShowProjects     *bool `json:"show_projects"`
+	ShowScores       *bool `json:"show_scores"`
+	ShowCertificates *bool `json:"show_certificates"`
+	ShowSkills       *bool `json:"show_skills"`
+	ShowGithubStats  *bool `json:"show_github_stats"`
+	ShowEndorsements *bool `json:"show_endorsements"`
+}
+
+// UpdatePortfolioSettings - Student chooses which portfolio sections are public.
+// Fields left out of the request keep their current value.
+func UpdatePortfolioSettings(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input portfolioSettingsInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	settings := loadPortfolioSettings(userID)
+	apply := func(target *bool, value *bool) {
+		if value != nil {
+			*target = *value
+		}
+	}
+	apply(&settings.ShowEmail, input.ShowEmail)
+	apply(&settings.ShowPhone, input.ShowPhone)
+	apply(&settings.ShowEducation, input.ShowEducation)
+	apply(&settings.ShowLinks, input.ShowLinks)
+	apply(&settings.ShowProjects, input.ShowProjects)
+	apply(&settings.ShowScores, input.ShowScores)
+	apply(&settings.ShowCertificates, input.ShowCertificates)
+	apply(&settings.ShowSkills, input.ShowSkills)
+	apply(&settings.ShowGithubStats, input.ShowGithubStats)
+	apply(&settings.ShowEndorsements, input.ShowEndorsements)
+
+	// Select every column so false values are written rather than skipped as zero values
+	columns := []string{"user_id", "show_email", "show_phone", "show_education", "show_links", "show_projects",
+		"show_scores", "show_certificates", "show_skills", "show_github_stats", "show_endorsements", "updated_at"}
+	var err error
+	if settings.ID == 0 {
+		err = DB.Select(columns).Create(&settings).Error
+	} else {
+		err = DB.Select(columns).Save(&settings).Error
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to save portfolio settings"))
+		return
+	}
+
+	c.JSON(http.StatusOK, portfolioSettingsResponse{
+		Message:  "Portfolio settings updated successfully",
+		Settings: settings,
+	})
+}
+
+type endorsementResponse struct {
+	Message     string                  `json:"message"`
+	Endorsement models.GuideEndorsement `json:"endorsement"`
+}
+
+type endorsementInput struct {
+	Skill     string `json:"skill" binding:"required"`
+	Comment   string `json:"comment"`
+	ProjectID *uint  `json:"project_id"`
+}
+
+// EndorseStudent - Guide endorses a skill of a student they have worked with
+func EndorseStudent(c *gin.Context) {
+	guideID := c.GetUint("userID")
+
+	var student models.User
+	if err := DB.Where("id = ? AND role = ?", c.Param("id"), "student").First(&student).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Student not found"))
+		return
+	}
+
+	var input endorsementInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	input.Skill = strings.TrimSpace(input.Skill)
+
+	// Guides can only endorse students they are connected to or have guided on a project
+	var connections, guidedSubmissions int64
+	DB.Model(&models.GuideConnectionRequest{}).
+		Where("guide_id = ? AND student_id = ? AND status = ?", guideID, student.ID, "accepted").
+		Count(&connections)
+	guided := DB.Model(&models.Submission{}).
+		Joins("JOIN projects ON submissions.project_id = projects.id").
+		Where("projects.guide_id = ? AND submissions.student_id = ?", guideID, student.ID)
+	if input.ProjectID != nil {
+		guided = guided.Where("projects.id = ?", *input.ProjectID)
+	}
+	guided.Count(&guidedSubmissions)
+	if input.ProjectID != nil && guidedSubmissions == 0 {
+		apierror.Abort(c, apierror.Forbidden("You did not guide this student on that project"))
+		return
+	}
+	if connections == 0 && guidedSubmissions == 0 {
+		apierror.Abort(c, apierror.Forbidden("You can only endorse students you have guided"))
+		return
+	}
+
+	var existing models.GuideEndorsement
+	if err := DB.Where("guide_id = ? AND student_id = ? AND skill = ?", guideID, student.ID, input.Skill).First(&existing).Error; err == nil {
+		apierror.Abort(c, apierror.Conflict("You have already endorsed this skill"))
+		return
+	}
+
+	endorsement := models.GuideEndorsement{
+		GuideID:   guideID,
+		StudentID: student.ID,
+		Skill:     input.Skill,
+		ProjectID: input.ProjectID,
+		Comment:   input.Comment,
+	}
+	if err := DB.Create(&endorsement).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to save endorsement"))
+		return
+	}
+
+	if err := utils.CreateNotification(DB, student.ID, "A guide endorsed your skill: "+input.Skill); err != nil {
+		middleware.Log(c).Error("failed to send notification", "error", err)
+	}
+
+	c.JSON(http.StatusCreated, endorsementResponse{
+		Message:     "Endorsement added successfully",
+		Endorsement: endorsement,
+	})
+}
+
+// DeleteEndorsement - Guide withdraws one of their endorsements
+func DeleteEndorsement(c *gin.Context) {
+	guideID := c.GetUint("userID")
+
+	result := DB.Where("id = ? AND guide_id = ?", c.Param("id"), guideID).Delete(&models.GuideEndorsement{})
+	if result.Error != nil {
+		apierror.Abort(c, apierror.Internal("Failed to delete endorsement"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		apierror.Abort(c, apierror.NotFound("Endorsement not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Endorsement deleted successfully"})
+}
@@ -1,12 +1,11 @@
 package controller
 
 import (
+	"SkillBridge/apierror"
 	"SkillBridge/middleware"
 	"SkillBridge/models"
-	"SkillBridge/utils"
-	"fmt"
-	"io"
-	"log"
+	"SkillBridge/notify"
+	"errors"
 	"net/http"
 	"sort"
 	"strconv"
@@ -14,147 +13,129 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 	"gorm.io/gorm"
 )
 
+type projectResponse struct {
+	Message string         `json:"message"`
+	Project models.Project `json:"project"`
+}
+
+type projectsResponse struct {
+	Projects []models.Project `json:"projects"`
+}
+
+type postProjectInput struct {
+	Title        string    `json:"title" binding:"required"`
+	Description  string    `json:"description" binding:"required"`
+	Requirements string    `json:"requirements"`
+	Skills       string    `json:"skills"`
+	Budget       string    `json:"budget"`
+	Deadline     time.Time `json:"deadline" binding:"required"`
+	Difficulty   string    `json:"difficulty"`
+	Duration     string    `json:"duration"`
+	TeamSize     string    `json:"team_size"`
+	Location     string    `json:"location"`
+}
+
 func PostProject(c *gin.Context) {
 	// Get company ID from context (set by middleware)
 	companyID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
 		return
 	}
 
-	var input models.Project
-	if err := c.ShouldBindJSON(&input); err != nil {
-		log.Printf("PostProject - JSON binding error: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid JSON data: %v", err)})
+	var input postProjectInput
+	if !bindJSON(c, &input) {
 		return
 	}
 
-	input.CompanyID = companyID.(uint)
-	if err := DB.Create(&input).Error; err != nil {
-		log.Printf("PostProject - Database error: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not post project"})
+	project := models.Project{
+		Title:          input.Title,
+		Description:    input.Description,
+		Requirements:   input.Requirements,
+		Skills:         input.Skills,
+		Budget:         input.Budget,
+		Deadline:       input.Deadline,
+		Difficulty:     input.Difficulty,
+		Duration:       input.Duration,
+		TeamSize:       input.TeamSize,
+		Location:       input.Location,
+		CompanyID:      companyID.(uint),
+		OrganisationID: organisationIDFor(companyID.(uint)),
+	}
+	if err := DB.Create(&project).Error; err != nil {
+		middleware.Log(c).Error("failed to create project", "error", err)
+		apierror.Abort(c, apierror.Internal("Could not post project"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Project posted successfully", "project": input})
+	c.JSON(http.StatusOK, projectResponse{Message: "Project posted successfully", Project: project})
+}
+
+type submitRepoResponse struct {
+	Message       string `json:"message"`
+	ApplicationID uint   `json:"application_id"`
+	GithubRepoURL string `json:"github_repo_url"`
+}
+
+type submitRepoInput struct {
+	ApplicationID uint   `json:"application_id" binding:"required"`
+	GithubRepoURL string `json:"github_repo_url" binding:"required"`
 }
 
 // SubmitGithubRepo allows students to submit their GitHub repository URL for an application
 func SubmitGithubRepo(c *gin.Context) {
-	fmt.Println("=== SubmitGithubRepo called ===")
-
 	studentID := c.GetUint("userID")
 	role := c.GetString("role")
 	if role != "student" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Forbidden("Only students can submit repositories"))
 		return
 	}
 
-	var input struct {
-		ApplicationID uint   `json:"application_id" binding:"required"`
-		GithubRepoURL string `json:"github_repo_url" binding:"required"`
-	}
-
-	// Print raw request body for debugging
-	body, _ := c.GetRawData()
-	fmt.Printf("Raw request body: %s\n", string(body))
-
-	// Reset the body so it can be read again by ShouldBindJSON
-	c.Request.Body = io.NopCloser(strings.NewReader(string(body)))
-
-	fmt.Printf("Attempting to bind JSON to struct: %+v\n", input)
-
-	if err := c.ShouldBindJSON(&input); err != nil {
-		fmt.Printf("JSON binding error: %v\n", err)
-
-		// Provide more specific error messages
-		if strings.Contains(err.Error(), "application_id") {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Missing or invalid application_id. Please ensure you have selected a valid application.",
-				"details": err.Error(),
-			})
-		} else if strings.Contains(err.Error(), "github_repo_url") {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Missing or invalid github_repo_url. Please provide a valid GitHub repository URL.",
-				"details": err.Error(),
-			})
-		} else {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid request format. Please check your input.",
-				"details": err.Error(),
-			})
-		}
+	var input submitRepoInput
+	if !bindJSON(c, &input) {
 		return
 	}
 
-	fmt.Printf("Successfully bound request: %+v\n", input)
-
 	// Validate that application_id is not zero
 	if input.ApplicationID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application_id: cannot be zero"})
+		apierror.Abort(c, apierror.BadRequest("Invalid application_id: cannot be zero"))
 		return
 	}
 
 	// Validate GitHub URL format
 	if !strings.Contains(input.GithubRepoURL, "github.com") {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid GitHub repository URL"})
+		apierror.Abort(c, apierror.BadRequest("Invalid GitHub repository URL"))
 		return
 	}
 
 	// Find the application and verify ownership
 	var application models.Application
 	if err := DB.Where("id = ? AND student_id = ?", input.ApplicationID, studentID).First(&application).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found or not owned by you"})
+		apierror.Abort(c, apierror.NotFound("Application not found or not owned by you"))
 		return
 	}
 
 	// Update the application with GitHub repository URL
 	if err := DB.Model(&application).Update("github_repo_url", input.GithubRepoURL).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit GitHub repository"})
+		apierror.Abort(c, apierror.Internal("Failed to submit GitHub repository"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":         "GitHub repository submitted successfully",
-		"application_id":  application.ID,
-		"github_repo_url": input.GithubRepoURL,
+	c.JSON(http.StatusOK, submitRepoResponse{
+		Message:       "GitHub repository submitted successfully",
+		ApplicationID: application.ID,
+		GithubRepoURL: input.GithubRepoURL,
 	})
 }
 
 func GetAllProjects(c *gin.Context) {
-	// Get current user ID from context or token
-	var userID uint
+	// Public route: personalise the ordering when a valid token is present
+	userID, _, _ := middleware.OptionalUser(c)
 	var userSkills []string
 
-	// Check if userID is already in context (unlikely for public route but good practice)
-	if id, exists := c.Get("userID"); exists {
-		userID = id.(uint)
-	} else {
-		// Check for Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-				}
-				return middleware.JWT_SECRET, nil
-			})
-
-			if err == nil && token.Valid {
-				if claims, ok := token.Claims.(jwt.MapClaims); ok {
-					if idFloat, ok := claims["user_id"].(float64); ok {
-						userID = uint(idFloat)
-					}
-				}
-			}
-		}
-	}
-
 	if userID != 0 {
 		var user models.User
 		if err := DB.First(&user, userID).Error; err == nil && user.Skills != "" {
@@ -171,13 +152,13 @@ func GetAllProjects(c *gin.Context) {
 
 	var projects []models.Project
 	if err := DB.Where("deadline > ?", time.Now()).Find(&projects).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "project not found"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch projects"))
 		return
 	}
 
 	// If user has skills, sort projects based on matching skills
 	if len(userSkills) > 0 {
-		log.Printf("User Skills: %v", userSkills)
+		middleware.Log(c).Debug("ranking projects by skills", "skills", userSkills)
 
 		// Create a map to cache project scores
 		projectScores := make(map[uint]int)
@@ -197,7 +178,7 @@ func GetAllProjects(c *gin.Context) {
 			}
 			projectScores[project.ID] = score
 			if score > 0 {
-				log.Printf("Project %d (%s) Score: %d", project.ID, project.Title, score)
+				middleware.Log(c).Debug("project skill score", "project_id", project.ID, "score", score)
 			}
 		}
 
@@ -207,7 +188,7 @@ func GetAllProjects(c *gin.Context) {
 		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{"projects": projects})
+	c.JSON(http.StatusOK, projectsResponse{Projects: projects})
 }
 
 func GetProjectById(c *gin.Context) {
@@ -216,44 +197,66 @@ func GetProjectById(c *gin.Context) {
 	var project models.Project
 	if err := DB.First(&project, projectID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			apierror.Abort(c, apierror.NotFound("Project not found"))
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project"})
+			apierror.Abort(c, apierror.Internal("Failed to fetch project"))
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": project})
+	c.JSON(http.StatusOK, projectDetailResponse{Data: project})
+}
+
+type projectDetailResponse struct {
+	Data models.Project `json:"data"`
+}
+
+type applicationResponse struct {
+	Message     string             `json:"message"`
+	Application models.Application `json:"application"`
+}
+
+type applicationsResponse struct {
+	Applications []models.Application `json:"applications"`
+}
+
+type applicantsResponse struct {
+	Applicants []models.Application `json:"applicants"`
+}
+
+type applyToProjectInput struct {
+	ProjectID uint `json:"project_id"`
 }
 
 func ApplyToProject(c *gin.Context) {
 	studentID := c.GetUint("userID")
 	role := c.GetString("role")
 	if role != "student" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Forbidden("Only students can apply to projects"))
 		return
 	}
 
-	var input struct {
-		ProjectID uint `json:"project_id"`
-	}
+	var input applyToProjectInput
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &input) {
 		return
 	}
 
 	// Check if project exists
 	var project models.Project
 	if err := DB.First(&project, input.ProjectID).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid project ID"))
+		return
+	}
+	if project.ClosedAt != nil || project.Deadline.Before(time.Now()) {
+		apierror.Abort(c, apierror.BadRequest("The deadline for this project has passed"))
 		return
 	}
 
 	// Check if already applied
 	var existingApplication models.Application
 	if err := DB.Where("project_id = ? AND student_id = ?", input.ProjectID, studentID).First(&existingApplication).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Already applied to this project"})
+		apierror.Abort(c, apierror.BadRequest("Already applied to this project"))
 		return
 	}
 
@@ -266,13 +269,14 @@ func ApplyToProject(c *gin.Context) {
 	}
 
 	if err := DB.Create(&application).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply to project"})
+		apierror.Abort(c, apierror.Internal("Failed to apply to project"))
 		return
 	}
+	applicationsTotal.Inc("project")
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":     "Project applied successfully",
-		"application": application,
+	c.JSON(http.StatusOK, applicationResponse{
+		Message:     "Project applied successfully",
+		Application: application,
 	})
 }
 
@@ -305,19 +309,35 @@ func GetProjectApplicants(c *gin.Context) {
 	companyID := c.GetUint("userID")
 
 	var project models.Project
-	log.Printf("id = ? AND company_id = ?", projectID, companyID)
-	if err := DB.Where("id = ? AND company_id = ?", projectID, companyID).First(&project).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or not owned by you"})
+	if err := DB.Scopes(ownedByCompany("projects", companyID)).Where("id = ?", projectID).First(&project).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Project not found or not owned by you"))
 		return
 	}
 
 	var applications []models.Application
 	if err := DB.Preload("Student").Where("project_id = ?", projectID).Find(&applications).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applicants"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch applicants"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"applicants": applications})
+	c.JSON(http.StatusOK, applicantsResponse{Applicants: applications})
+}
+
+type submissionResponse struct {
+	Message    string            `json:"message,omitempty"`
+	Submission models.Submission `json:"submission"`
+}
+
+type submissionsResponse struct {
+	Submissions []models.Submission `json:"submissions"`
+}
+
+type submitProjectInput struct {
+	GitHubLink    string `json:"github_link"`
+	GithubRepoURL string `json:"github_repo_url"`
+	Notes         string `json:"notes"`
+	Description   string `json:"description"`
+	DemoURL       string `json:"demo_url"`
 }
 
 func SubmitProject(c *gin.Context) {
@@ -325,34 +345,27 @@ func SubmitProject(c *gin.Context) {
 	role := c.GetString("role")
 
 	if role != "student" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Forbidden("Only students can submit projects"))
 		return
 	}
 
 	projectIDParam := c.Param("id")
 	projectID, err := strconv.ParseUint(projectIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid project ID"))
 		return
 	}
 
-	var input struct {
-		GitHubLink    string `json:"github_link"`
-		GithubRepoURL string `json:"github_repo_url"`
-		Notes         string `json:"notes"`
-		Description   string `json:"description"`
-		DemoURL       string `json:"demo_url"`
-	}
+	var input submitProjectInput
 
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if !bindJSON(c, &input) {
 		return
 	}
 
 	// Optional: Check if the student already submitted
 	var existing models.Submission
 	if err := DB.Where("student_id = ? AND project_id = ?", studentID, projectID).First(&existing).Error; err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Already submitted"})
+		apierror.Abort(c, apierror.BadRequest("Already submitted"))
 		return
 	}
 
@@ -381,9 +394,10 @@ func SubmitProject(c *gin.Context) {
 	}
 
 	if err := DB.Create(&submission).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Submission failed"})
+		apierror.Abort(c, apierror.Internal("Submission failed"))
 		return
 	}
+	submissionsTotal.Inc()
 
 	// Update the application status to "submitted" when final submission is made
 	var application models.Application
@@ -392,7 +406,11 @@ func SubmitProject(c *gin.Context) {
 		DB.Save(&application)
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Project submitted successfully", "submission": submission})
+	if err := DB.First(&submission.Project, submission.ProjectID).Error; err == nil {
+		emitProjectWebhook(DB, submission.Project, webhookSubmissionCreated, submissionWebhookData(submission))
+	}
+
+	c.JSON(http.StatusOK, submissionResponse{Message: "Project submitted successfully", Submission: submission})
 }
 
 func GetProjectSubmissions(c *gin.Context) {
@@ -400,25 +418,45 @@ func GetProjectSubmissions(c *gin.Context) {
 	companyID := c.GetUint("userID")
 
 	var project models.Project
-	if err := DB.Where("id = ? AND company_id = ?", projectIDParam, companyID).First(&project).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or unauthorized"})
+	if err := DB.Scopes(ownedByCompany("projects", companyID)).Where("id = ?", projectIDParam).First(&project).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Project not found or unauthorized"))
 		return
 	}
 
 	var submissions []models.Submission
 	if err := DB.Preload("Student").Where("project_id = ?", projectIDParam).Find(&submissions).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submissions"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch submissions"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"submissions": submissions})
+	c.JSON(http.StatusOK, submissionsResponse{Submissions: submissions})
+}
+
+// reviewSubmissionResponse carries the certificate issued when a company accepts the work
+type reviewSubmissionResponse struct {
+	Message       string                   `json:"message"`
+	Submission    models.Submission        `json:"submission"`
+	Review        *models.SubmissionReview `json:"review"`
+	CertificateID string                   `json:"certificate_id,omitempty"`
+}
+
+type companyReviewInput struct {
+	Status   string             `json:"status"`   // accepted, rejected, changes_requested
+	Feedback string             `json:"feedback"` // optional
+	Scores   []RubricScoreInput `json:"scores"`   // optional, per rubric criterion
+}
+
+type guideReviewInput struct {
+	ReviewStatus  string             `json:"review_status"`  // approved, rejected, pending
+	ReviewComment string             `json:"review_comment"` // optional
+	Scores        []RubricScoreInput `json:"scores"`         // optional, per rubric criterion
 }
 
 func ReviewSubmission(c *gin.Context) {
 	submissionIDParam := c.Param("id")
 	submissionID, err := strconv.ParseUint(submissionIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid submission ID"))
 		return
 	}
 
@@ -427,36 +465,37 @@ func ReviewSubmission(c *gin.Context) {
 
 	// Allow both company and guide to review submissions
 	if role != "company" && role != "guide" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Forbidden("Only companies and guides can review submissions"))
 		return
 	}
 
 	var submission models.Submission
 	if err := DB.Preload("Project").First(&submission, submissionID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		apierror.Abort(c, apierror.NotFound("Submission not found"))
 		return
 	}
 
 	// Company can only review their own projects
-	if role == "company" && submission.Project.CompanyID != userID {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Not your project"})
+	if role == "company" && !companyOwns(userID, submission.Project.CompanyID, submission.Project.OrganisationID) {
+		apierror.Abort(c, apierror.Forbidden("Not your project"))
 		return
 	}
 
 	// Guide can only review projects assigned to them
 	if role == "guide" && (submission.Project.GuideID == nil || *submission.Project.GuideID != userID) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: Not assigned to this project"})
+		apierror.Abort(c, apierror.Forbidden("Not assigned to this project"))
 		return
 	}
 
 	if role == "company" {
 		// Company review (existing functionality)
-		var input struct {
-			Status   string `json:"status"`   // accepted, rejected, changes_requested
-			Feedback string `json:"feedback"` // optional
+		var input companyReviewInput
+		if !bindJSON(c, &input) {
+			return
 		}
-		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+
+		rubric, ok := reviewRubric(c, submission, input.Scores)
+		if !ok {
 			return
 		}
 
@@ -464,30 +503,58 @@ func ReviewSubmission(c *gin.Context) {
 		submission.Status = input.Status
 		submission.Feedback = input.Feedback
 
-		if err := DB.Save(&submission).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
+		review, err := saveSubmissionReview(&submission, rubric, userID, role, input.Feedback, input.Scores)
+		if errors.Is(err, errRubricChanged) {
+			apierror.Abort(c, apierror.Conflict(err.Error()))
+			return
+		}
+		if err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to update submission"))
 			return
 		}
 
 		// Send notification to the student
-		err = utils.CreateNotification(DB, submission.StudentID, "Your submission was reviewed by company: "+input.Status)
+		err = notify.Send(DB, submission.StudentID, notify.Message{
+			Event: notify.EventSubmissionReviewed,
+			Text:  "Your submission was reviewed by company: " + input.Status,
+			Data:  map[string]string{"project": submission.Project.Title, "reviewer": "The company", "status": input.Status, "feedback": input.Feedback},
+			Link:  "/submissions",
+		})
 		if err != nil {
-			log.Printf("Failed to send notification: %v", err)
+			middleware.Log(c).Error("failed to send notification", "error", err)
+		}
+		emitProjectWebhook(DB, submission.Project, webhookSubmissionReviewed, submissionWebhookData(submission))
+
+		// Completed projects count towards the student's job fit scores
+		invalidateStudentFits(submission.StudentID)
+
+		// Accepted work earns the student a certificate of completion
+		var certificateID string
+		if input.Status == "accepted" {
+			certificate, err := issueCertificate(submission)
+			if err != nil {
+				middleware.Log(c).Error("failed to issue certificate", "submission_id", submission.ID, "error", err)
+			} else {
+				certificateID = certificate.CertificateID
+			}
 		}
 
-		c.JSON(http.StatusOK, gin.H{
-			"message":    "Submission reviewed by company",
-			"submission": submission,
+		c.JSON(http.StatusOK, reviewSubmissionResponse{
+			Message:       "Submission reviewed by company",
+			Submission:    submission,
+			Review:        review,
+			CertificateID: certificateID,
 		})
 
 	} else if role == "guide" {
 		// Guide review (new functionality)
-		var input struct {
-			ReviewStatus  string `json:"review_status"`  // approved, rejected, pending
-			ReviewComment string `json:"review_comment"` // optional
+		var input guideReviewInput
+		if !bindJSON(c, &input) {
+			return
 		}
-		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+
+		rubric, ok := reviewRubric(c, submission, input.Scores)
+		if !ok {
 			return
 		}
 
@@ -495,20 +562,32 @@ func ReviewSubmission(c *gin.Context) {
 		submission.ReviewStatus = input.ReviewStatus
 		submission.ReviewComment = input.ReviewComment
 
-		if err := DB.Save(&submission).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update submission"})
+		review, err := saveSubmissionReview(&submission, rubric, userID, role, input.ReviewComment, input.Scores)
+		if errors.Is(err, errRubricChanged) {
+			apierror.Abort(c, apierror.Conflict(err.Error()))
+			return
+		}
+		if err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to update submission"))
 			return
 		}
 
 		// Send notification to the student
-		err = utils.CreateNotification(DB, submission.StudentID, "Your submission was reviewed by guide: "+input.ReviewStatus)
+		err = notify.Send(DB, submission.StudentID, notify.Message{
+			Event: notify.EventSubmissionReviewed,
+			Text:  "Your submission was reviewed by guide: " + input.ReviewStatus,
+			Data:  map[string]string{"project": submission.Project.Title, "reviewer": "Your guide", "status": input.ReviewStatus, "feedback": input.ReviewComment},
+			Link:  "/submissions",
+		})
 		if err != nil {
-			log.Printf("Failed to send notification: %v", err)
+			middleware.Log(c).Error("failed to send notification", "error", err)
 		}
+		emitProjectWebhook(DB, submission.Project, webhookSubmissionReviewed, submissionWebhookData(submission))
 
-		c.JSON(http.StatusOK, gin.H{
-			"message":    "Submission reviewed by guide",
-			"submission": submission,
+		c.JSON(http.StatusOK, reviewSubmissionResponse{
+			Message:    "Submission reviewed by guide",
+			Submission: submission,
+			Review:     review,
 		})
 	}
 }
@@ -519,12 +598,14 @@ func GetMySubmissions(c *gin.Context) {
 	var submissions []models.Submission
 	if err := DB.Preload("Project", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "title")
+	}).Preload("Reviews.Scores.Criterion").Preload("Reviews.Reviewer", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name", "role")
 	}).Where("student_id = ?", studentID).Find(&submissions).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submissions"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch submissions"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"submissions": submissions})
+	c.JSON(http.StatusOK, submissionsResponse{Submissions: submissions})
 }
 
 func GetCompanyApplications(c *gin.Context) {
@@ -535,27 +616,27 @@ func GetCompanyApplications(c *gin.Context) {
 	err := DB.Preload("Student").
 		Preload("Project").
 		Joins("JOIN projects ON projects.id = applications.project_id").
-		Where("projects.company_id = ?", companyID).
+		Scopes(ownedByCompany("projects", companyID)).
 		Find(&applications).Error
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch applications"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"applications": applications})
+	c.JSON(http.StatusOK, applicationsResponse{Applications: applications})
 }
 
 func GetCompanyProjects(c *gin.Context) {
 	companyID := c.GetUint("userID")
 
 	var projects []models.Project
-	if err := DB.Where("company_id = ?", companyID).Find(&projects).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch company projects"})
+	if err := DB.Scopes(ownedByCompany("projects", companyID)).Find(&projects).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch company projects"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"projects": projects})
+	c.JSON(http.StatusOK, projectsResponse{Projects: projects})
 }
 
 func GetMyApplications(c *gin.Context) {
@@ -563,11 +644,11 @@ func GetMyApplications(c *gin.Context) {
 
 	var applications []models.Application
 	if err := DB.Preload("Project").Where("student_id = ?", studentID).Find(&applications).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch applications"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"applications": applications})
+	c.JSON(http.StatusOK, applicationsResponse{Applications: applications})
 }
 
 func GetGuideSubmissions(c *gin.Context) {
@@ -581,11 +662,11 @@ func GetGuideSubmissions(c *gin.Context) {
 		Find(&submissions).Error
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submissions for guide"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch submissions for guide"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"submissions": submissions})
+	c.JSON(http.StatusOK, submissionsResponse{Submissions: submissions})
 }
 
 // controller/project_controller.go
@@ -595,13 +676,13 @@ func DeleteProject(c *gin.Context) {
 	role := c.GetString("role")
 
 	if role != "company" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Forbidden("Only companies can delete projects"))
 		return
 	}
 
 	var project models.Project
-	if err := DB.Where("id = ? AND company_id = ?", projectID, companyID).First(&project).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or unauthorized"})
+	if err := DB.Scopes(ownedByCompany("projects", companyID)).Where("id = ?", projectID).First(&project).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Project not found or unauthorized"))
 		return
 	}
 
@@ -609,26 +690,75 @@ func DeleteProject(c *gin.Context) {
 	// This bypasses GORM's soft-delete / model hooks entirely so the
 	// FK constraints on `submissions` and `applications` are cleared
 	// before we attempt to delete the parent project row.
+	if err := DB.Exec("DELETE FROM criterion_scores WHERE review_id IN (SELECT id FROM submission_reviews WHERE submission_id IN (SELECT id FROM submissions WHERE project_id = ?))", project.ID).Error; err != nil {
+		middleware.Log(c).Error("failed to delete project rubric scores", "project_id", project.ID, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to delete project reviews"))
+		return
+	}
+
+	if err := DB.Exec("DELETE FROM submission_reviews WHERE submission_id IN (SELECT id FROM submissions WHERE project_id = ?)", project.ID).Error; err != nil {
+		middleware.Log(c).Error("failed to delete project reviews", "project_id", project.ID, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to delete project reviews"))
+		return
+	}
+
+	if err := DB.Exec("DELETE FROM review_comment_mentions WHERE comment_id IN (SELECT id FROM review_comments WHERE submission_id IN (SELECT id FROM submissions WHERE project_id = ?))", project.ID).Error; err != nil {
+		middleware.Log(c).Error("failed to delete project comment mentions", "project_id", project.ID, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to delete project review comments"))
+		return
+	}
+
+	// Replies reference their thread, so they go first
+	if err := DB.Exec("DELETE FROM review_comments WHERE parent_id IS NOT NULL AND submission_id IN (SELECT id FROM submissions WHERE project_id = ?)", project.ID).Error; err != nil {
+		middleware.Log(c).Error("failed to delete project comment replies", "project_id", project.ID, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to delete project review comments"))
+		return
+	}
+
+	if err := DB.Exec("DELETE FROM review_comments WHERE submission_id IN (SELECT id FROM submissions WHERE project_id = ?)", project.ID).Error; err != nil {
+		middleware.Log(c).Error("failed to delete project comments", "project_id", project.ID, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to delete project review comments"))
+		return
+	}
+
 	if err := DB.Exec("DELETE FROM submissions WHERE project_id = ?", project.ID).Error; err != nil {
-		log.Printf("DeleteProject - Failed to delete submissions: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project submissions"})
+		middleware.Log(c).Error("failed to delete project submissions", "project_id", project.ID, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to delete project submissions"))
 		return
 	}
 
 	if err := DB.Exec("DELETE FROM applications WHERE project_id = ?", project.ID).Error; err != nil {
-		log.Printf("DeleteProject - Failed to delete applications: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project applications"})
+		middleware.Log(c).Error("failed to delete project applications", "project_id", project.ID, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to delete project applications"))
+		return
+	}
+
+	if err := DB.Exec("DELETE FROM project_guide_invitations WHERE project_id = ?", project.ID).Error; err != nil {
+		middleware.Log(c).Error("failed to delete project guide invitations", "project_id", project.ID, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to delete project guide invitations"))
+		return
+	}
+
+	if err := DB.Exec("DELETE FROM rubric_criterions WHERE rubric_id IN (SELECT id FROM rubrics WHERE project_id = ?)", project.ID).Error; err != nil {
+		middleware.Log(c).Error("failed to delete project rubric criteria", "project_id", project.ID, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to delete project rubric"))
+		return
+	}
+
+	if err := DB.Exec("DELETE FROM rubrics WHERE project_id = ?", project.ID).Error; err != nil {
+		middleware.Log(c).Error("failed to delete project rubric", "project_id", project.ID, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to delete project rubric"))
 		return
 	}
 
 	// Now safe to delete the project itself
 	if err := DB.Exec("DELETE FROM projects WHERE id = ?", project.ID).Error; err != nil {
-		log.Printf("DeleteProject - Failed to delete project: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
+		middleware.Log(c).Error("failed to delete project", "project_id", project.ID, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to delete project"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Project deleted successfully"})
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Project deleted successfully"})
 }
 
 func WithdrawApplication(c *gin.Context) {
@@ -636,29 +766,29 @@ func WithdrawApplication(c *gin.Context) {
 	role := c.GetString("role")
 
 	if role != "student" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		apierror.Abort(c, apierror.Forbidden("Only students can withdraw applications"))
 		return
 	}
 
 	projectIDParam := c.Param("id")
 	projectID, err := strconv.ParseUint(projectIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		apierror.Abort(c, apierror.BadRequest("Invalid project ID"))
 		return
 	}
 
 	// Find the application
 	var application models.Application
 	if err := DB.Where("student_id = ? AND project_id = ?", studentID, projectID).First(&application).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		apierror.Abort(c, apierror.NotFound("Application not found"))
 		return
 	}
 
 	// Delete the application
 	if err := DB.Delete(&application).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw application"})
+		apierror.Abort(c, apierror.Internal("Failed to withdraw application"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Application withdrawn successfully"})
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Application withdrawn successfully"})
 }
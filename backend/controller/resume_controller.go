@@ -1,19 +1,22 @@
 package controller
 
 import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
 	"SkillBridge/models"
-	"bytes"
-	"encoding/json"
+	"SkillBridge/resume"
+	"SkillBridge/utils"
+	"errors"
 	"fmt"
-	"io"
-	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-const resumeAPIKey = "ur_live_EK-wQZBkYKTxuTATom7PljhXTv8a127j"
+// generatedResumeURLTTL is how long the link to a freshly generated resume stays valid
+const generatedResumeURLTTL = time.Hour
 
 type ResumeRequestBody struct {
 	Location       string                `json:"location"`
@@ -21,6 +24,15 @@ type ResumeRequestBody struct {
 	Education      []ResumeEducation     `json:"education"`
 	Projects       []ResumeProject       `json:"projects"`
 	Certifications []ResumeCertification `json:"certifications"`
+
+	// Rendering options
+	Template string `json:"template"` // classic (default), modern, compact
+	Format   string `json:"format"`   // pdf or html streams the file; empty saves a PDF and returns a link
+	Provider string `json:"provider"` // local (default) or useresume when configured
+
+	// Accepted SkillBridge projects and certificates are added unless turned off
+	IncludePlatformProjects *bool `json:"include_platform_projects"`
+	IncludeCertificates     *bool `json:"include_certificates"`
 }
 
 type ResumeExperience struct {
@@ -52,44 +64,9 @@ type ResumeProject struct {
 	Highlights   []string `json:"highlights"`
 }
 
-// safeStrings ensures a nil slice becomes an empty slice (never marshalled as null)
-func safeStrings(s []string) []string {
-	if s == nil {
-		return []string{}
-	}
-	return s
-}
-
-func GenerateResume(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
-
-	var user models.User
-	if err := DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "User not found"})
-		return
-	}
-
-	var body ResumeRequestBody
-	if err := c.ShouldBindJSON(&body); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
-		return
-	}
-
-	// Build skills — never leave as null
-	skillsList := []map[string]string{}
-	if user.Skills != "" {
-		for _, s := range strings.Split(user.Skills, ",") {
-			trimmed := strings.TrimSpace(s)
-			if trimmed != "" {
-				skillsList = append(skillsList, map[string]string{"name": trimmed})
-			}
-		}
-	}
-
+// buildResumeDocument combines the student's profile, the request and, unless
+// turned off, their accepted SkillBridge projects and certificates
+func buildResumeDocument(user models.User, body ResumeRequestBody) resume.Document {
 	// Title fallback chain
 	title := strings.TrimSpace(user.Position)
 	if title == "" {
@@ -111,121 +88,204 @@ func GenerateResume(c *gin.Context) {
 		location = strings.TrimSpace(user.University)
 	}
 
-	// Build experience — ensure highlights is never null
-	experience := []map[string]interface{}{}
+	doc := resume.Document{
+		Name:     user.Name,
+		Email:    user.Email,
+		Phone:    user.Phone,
+		Location: location,
+		Title:    title,
+		Bio:      bio,
+		Skills:   utils.ParseSkills(user.Skills),
+	}
+	for _, link := range []string{user.GithubURL, user.LinkedIn, user.PortfolioURL} {
+		if strings.TrimSpace(link) != "" {
+			doc.Links = append(doc.Links, strings.TrimSpace(link))
+		}
+	}
+
 	for _, e := range body.Experience {
 		if e.Company == "" {
 			continue
 		}
-		experience = append(experience, map[string]interface{}{
-			"company":    e.Company,
-			"position":   e.Position,
-			"location":   e.Location,
-			"startDate":  e.Start,
-			"endDate":    e.End,
-			"summary":    e.Summary,
-			"highlights": safeStrings(e.Points),
+		doc.Experience = append(doc.Experience, resume.Experience{
+			Company:    e.Company,
+			Position:   e.Position,
+			Location:   e.Location,
+			Start:      e.Start,
+			End:        e.End,
+			Summary:    e.Summary,
+			Highlights: e.Points,
 		})
 	}
-	if experience == nil {
-		experience = []map[string]interface{}{}
-	}
 
-	// Build education
-	education := []map[string]interface{}{}
 	for _, e := range body.Education {
 		if e.Institution == "" {
 			continue
 		}
-		education = append(education, map[string]interface{}{
-			"institution": e.Institution,
-			"degree":      e.Degree,
-			"field":       e.Field,
-			"startDate":   e.Start,
-			"endDate":     e.End,
+		doc.Education = append(doc.Education, resume.Education{
+			Institution: e.Institution,
+			Degree:      e.Degree,
+			Field:       e.Field,
+			Start:       e.Start,
+			End:         e.End,
 		})
 	}
 
-	// Build projects — ensure technologies and highlights are never null
-	projects := []map[string]interface{}{}
+	listed := map[string]bool{}
 	for _, p := range body.Projects {
 		if p.Name == "" {
 			continue
 		}
-		projects = append(projects, map[string]interface{}{
-			"name":         p.Name,
-			"description":  p.Description,
-			"technologies": safeStrings(p.Technologies),
-			"highlights":   safeStrings(p.Highlights),
+		listed[strings.ToLower(p.Name)] = true
+		doc.Projects = append(doc.Projects, resume.Project{
+			Name:         p.Name,
+			Description:  p.Description,
+			Technologies: p.Technologies,
+			Highlights:   p.Highlights,
 		})
 	}
+	if body.IncludePlatformProjects == nil || *body.IncludePlatformProjects {
+		var submissions []models.Submission
+		DB.Preload("Project").
+			Where("student_id = ? AND status = ?", user.ID, "accepted").
+			Order("submitted_at DESC").
+			Find(&submissions)
+		for _, submission := range submissions {
+			if listed[strings.ToLower(submission.Project.Title)] {
+				continue
+			}
+			listed[strings.ToLower(submission.Project.Title)] = true
+			url := submission.GithubURL
+			if url == "" {
+				url = submission.GithubLink
+			}
+			doc.Projects = append(doc.Projects, resume.Project{
+				Name:         submission.Project.Title,
+				Description:  submission.Project.Description,
+				Technologies: utils.ParseSkills(submission.Project.Skills),
+				URL:          url,
+			})
+		}
+	}
 
-	// Build certifications
-	certifications := []map[string]string{}
 	for _, cert := range body.Certifications {
 		if cert.Name != "" {
-			certifications = append(certifications, map[string]string{"name": cert.Name})
+			doc.Certifications = append(doc.Certifications, resume.Certification{Name: cert.Name})
+		}
+	}
+	if body.IncludeCertificates == nil || *body.IncludeCertificates {
+		var certificates []models.Certificate
+		DB.Where("student_id = ? AND revoked = ?", user.ID, false).Order("issued_at DESC").Find(&certificates)
+		for _, certificate := range certificates {
+			doc.Certifications = append(doc.Certifications, resume.Certification{
+				Name:      certificate.ProjectTitle,
+				Issuer:    certificate.CompanyName + " via SkillBridge",
+				Date:      certificate.CompletedAt.Format("Jan 2006"),
+				VerifyURL: "/api/certificates/" + certificate.CertificateID + "/verify",
+			})
 		}
 	}
 
-	// Build the final payload — exact format as required by useresume.ai
-	payload := map[string]interface{}{
-		"content": map[string]interface{}{
-			"name":           user.Name,
-			"email":          user.Email,
-			"phone":          user.Phone,
-			"location":       location,
-			"title":          title,
-			"bio":            bio,
-			"skills":         skillsList,
-			"experience":     experience,
-			"projects":       projects,
-			"education":      education,
-			"certifications": certifications,
-		},
-		"style": map[string]string{
-			"template": "modern-pro",
-		},
+	return doc
+}
+
+// GetResumeTemplates - Lists the resume templates and rendering backends
+func GetResumeTemplates(c *gin.Context) {
+	c.JSON(http.StatusOK, resumeTemplatesResponse{
+		Templates:       resume.Templates(),
+		DefaultTemplate: resume.DefaultTemplate,
+		Formats:         resume.Local{}.Formats(),
+	})
+}
+
+type resumeTemplatesResponse struct {
+	Templates       map[string]string `json:"templates"`
+	DefaultTemplate string            `json:"default_template"`
+	Formats         []string          `json:"formats"`
+}
+
+type generatedResumeResponse struct {
+	Message string          `json:"message"`
+	Data    generatedResume `json:"data"`
+}
+
+type generatedResume struct {
+	FileURL      string    `json:"file_url"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	ResumeFileID uint      `json:"resume_file_id"`
+}
+
+// GenerateResume - Render the student's resume. With format pdf or html the file is
+// returned directly; otherwise the PDF is saved to their resumes and a link returned.
+func GenerateResume(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		apierror.Abort(c, apierror.Unauthorized("Unauthorized"))
+		return
 	}
 
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build resume payload"})
+	var user models.User
+	if err := DB.First(&user, userID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("User not found"))
 		return
 	}
 
-	// Log the exact payload sent to API for debugging
-	log.Printf("[ResumeAPI] Sending payload: %s", string(payloadBytes))
+	var body ResumeRequestBody
+	if !bindJSON(c, &body) {
+		return
+	}
 
-	req, err := http.NewRequest("POST", "https://useresume.ai/api/v3/resume/create", bytes.NewBuffer(payloadBytes))
+	backend, err := resume.Get(body.Provider)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create request"})
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
 		return
 	}
-	req.Header.Set("Authorization", "Bearer "+resumeAPIKey)
-	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	doc := buildResumeDocument(user, body)
+	result, err := backend.Render(c.Request.Context(), doc, resume.Options{Template: body.Template, Format: body.Format})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reach resume API: " + err.Error()})
+		if errors.Is(err, resume.ErrUnsupported) {
+			apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("Unsupported template or format for the %s backend", backend.Name())))
+			return
+		}
+		middleware.Log(c).Error("resume rendering failed", "backend", backend.Name(), "error", err)
+		apierror.Abort(c, apierror.BadGateway("Failed to generate resume"))
 		return
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read API response"})
+	// Hosted providers return their own link
+	if result.Data == nil {
+		c.Data(http.StatusOK, "application/json", result.Raw)
 		return
 	}
 
-	log.Printf("[ResumeAPI] Response status: %d, body: %s", resp.StatusCode, string(respBody))
+	if body.Format != "" {
+		ext := "pdf"
+		if body.Format == resume.FormatHTML {
+			ext = "html"
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", "resume."+ext))
+		c.Data(http.StatusOK, result.ContentType, result.Data)
+		return
+	}
 
-	var apiResponse map[string]interface{}
-	if err := json.Unmarshal(respBody, &apiResponse); err != nil {
-		c.Data(resp.StatusCode, "application/json", respBody)
+	file, err := saveGeneratedResume(c, user.ID, "resume-"+time.Now().Format("2006-01-02")+".pdf", result.Data)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to save resume"))
 		return
 	}
 
-	c.JSON(resp.StatusCode, apiResponse)
+	url, expires, err := utils.SignedFileURL(fmt.Sprintf("/api/files/resumes/%d", file.ID), generatedResumeURLTTL)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to sign the download link"))
+		return
+	}
+	c.JSON(http.StatusOK, generatedResumeResponse{
+		Message: "Resume generated successfully",
+		Data: generatedResume{
+			FileURL:      url,
+			ExpiresAt:    expires,
+			ResumeFileID: file.ID,
+		},
+	})
 }
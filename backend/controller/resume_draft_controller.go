@@ -0,0 +1,576 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/models"
+	"SkillBridge/resume"
+	"SkillBridge/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// resumeContent is what a draft stores: the GenerateResume body without the
+// per-request rendering options
+type resumeContent struct {
+	Location                string                `json:"location"`
+	Experience              []ResumeExperience    `json:"experience"`
+	Education               []ResumeEducation     `json:"education"`
+	Projects                []ResumeProject       `json:"projects"`
+	Certifications          []ResumeCertification `json:"certifications"`
+	IncludePlatformProjects *bool                 `json:"include_platform_projects,omitempty"`
+	IncludeCertificates     *bool                 `json:"include_certificates,omitempty"`
+}
+
+// normaliseResumeContent validates raw content and returns it in canonical form
+func normaliseResumeContent(raw json.RawMessage) (json.RawMessage, resumeContent, error) {
+	var content resumeContent
+	if len(raw) == 0 {
+		raw = json.RawMessage("{}")
+	}
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return nil, content, errors.New("invalid resume content: " + err.Error())
+	}
+	normalised, err := json.Marshal(content)
+	return normalised, content, err
+}
+
+// mergeResumeContent applies a JSON merge patch (RFC 7386) to stored content:
+// keys in the patch replace existing ones and null removes them
+func mergeResumeContent(stored, patch json.RawMessage) (json.RawMessage, error) {
+	var target, changes map[string]interface{}
+	if len(stored) > 0 {
+		if err := json.Unmarshal(stored, &target); err != nil {
+			return nil, err
+		}
+	}
+	if target == nil {
+		target = map[string]interface{}{}
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return nil, errors.New("content must be a JSON object")
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(target, key)
+			continue
+		}
+		target[key] = value
+	}
+	return json.Marshal(target)
+}
+
+// toRequest turns stored content into a GenerateResume body
+func (content resumeContent) toRequest(template string) ResumeRequestBody {
+	return ResumeRequestBody{
+		Location:                content.Location,
+		Experience:              content.Experience,
+		Education:               content.Education,
+		Projects:                content.Projects,
+		Certifications:          content.Certifications,
+		Template:                template,
+		IncludePlatformProjects: content.IncludePlatformProjects,
+		IncludeCertificates:     content.IncludeCertificates,
+	}
+}
+
+// documentContent maps a resume document back to editable draft content
+func documentContent(doc resume.Document) resumeContent {
+	content := resumeContent{Location: doc.Location}
+	for _, e := range doc.Experience {
+		content.Experience = append(content.Experience, ResumeExperience{
+			Company: e.Company, Position: e.Position, Location: e.Location,
+			Start: e.Start, End: e.End, Summary: e.Summary, Points: e.Highlights,
+		})
+	}
+	for _, e := range doc.Education {
+		content.Education = append(content.Education, ResumeEducation{
+			Institution: e.Institution, Degree: e.Degree, Field: e.Field, Start: e.Start, End: e.End,
+		})
+	}
+	for _, p := range doc.Projects {
+		content.Projects = append(content.Projects, ResumeProject{
+			Name: p.Name, Description: p.Description, Technologies: p.Technologies, Highlights: p.Highlights,
+		})
+	}
+	for _, cert := range doc.Certifications {
+		content.Certifications = append(content.Certifications, ResumeCertification{Name: cert.Name})
+	}
+	return content
+}
+
+// validResumeTemplate reports whether the local renderer has the template
+func validResumeTemplate(template string) bool {
+	_, ok := resume.Templates()[template]
+	return template == "" || ok
+}
+
+// loadOwnDraft fetches one of the caller's drafts, writing a 404 when it is not theirs
+func loadOwnDraft(c *gin.Context) (models.ResumeDraft, bool) {
+	var draft models.ResumeDraft
+	if err := DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("userID")).First(&draft).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Resume draft not found"))
+		return draft, false
+	}
+	return draft, true
+}
+
+// targetJobExists checks a draft's target job, responding 404 when it is missing
+func targetJobExists(c *gin.Context, jobID uint) bool {
+	var job models.JobListing
+	if err := DB.Select("id").First(&job, jobID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Job listing not found"))
+		return false
+	}
+	return true
+}
+
+type createResumeDraftInput struct {
+	Name        string          `json:"name" binding:"required"`
+	Template    string          `json:"template"`
+	TargetJobID *uint           `json:"target_job_id"`
+	Content     json.RawMessage `json:"content"`
+}
+
+// CreateResumeDraft - Student saves a new resume draft
+func CreateResumeDraft(c *gin.Context) {
+	var input createResumeDraftInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if !validResumeTemplate(input.Template) {
+		apierror.Abort(c, apierror.BadRequest("Unknown template: "+input.Template))
+		return
+	}
+	content, _, err := normaliseResumeContent(input.Content)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
+		return
+	}
+	if input.TargetJobID != nil && !targetJobExists(c, *input.TargetJobID) {
+		return
+	}
+
+	draft := models.ResumeDraft{
+		UserID:      c.GetUint("userID"),
+		Name:        strings.TrimSpace(input.Name),
+		Template:    input.Template,
+		TargetJobID: input.TargetJobID,
+		Content:     content,
+	}
+	if err := DB.Create(&draft).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to save resume draft"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, resumeDraftResponse{
+		Message: "Resume draft created successfully",
+		Draft:   draft,
+	})
+}
+
+type resumeDraftResponse struct {
+	Message string             `json:"message,omitempty"`
+	Draft   models.ResumeDraft `json:"draft"`
+}
+
+type resumeDraftsResponse struct {
+	Drafts []models.ResumeDraft `json:"drafts"`
+	Count  int                  `json:"count"`
+}
+
+// GetResumeDrafts - Student lists their drafts, most recently edited first
+func GetResumeDrafts(c *gin.Context) {
+	var drafts []models.ResumeDraft
+	if err := DB.Where("user_id = ?", c.GetUint("userID")).Order("updated_at DESC").Find(&drafts).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch resume drafts"))
+		return
+	}
+
+	c.JSON(http.StatusOK, resumeDraftsResponse{
+		Drafts: drafts,
+		Count:  len(drafts),
+	})
+}
+
+// GetResumeDraft - Student views a draft and its version history
+func GetResumeDraft(c *gin.Context) {
+	draft, ok := loadOwnDraft(c)
+	if !ok {
+		return
+	}
+	DB.Where("draft_id = ?", draft.ID).Order("version DESC").Find(&draft.Versions)
+
+	c.JSON(http.StatusOK, resumeDraftResponse{Draft: draft})
+}
+
+type updateResumeDraftInput struct {
+	Name        *string         `json:"name"`
+	Template    *string         `json:"template"`
+	TargetJobID *uint           `json:"target_job_id"`
+	Content     json.RawMessage `json:"content"`
+}
+
+// UpdateResumeDraft - Student edits a draft. Omitted fields are unchanged and
+// content is merged key by key, so only the changed sections need to be sent.
+func UpdateResumeDraft(c *gin.Context) {
+	draft, ok := loadOwnDraft(c)
+	if !ok {
+		return
+	}
+
+	var input updateResumeDraftInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			apierror.Abort(c, apierror.BadRequest("Name cannot be empty"))
+			return
+		}
+		updates["name"] = name
+	}
+	if input.Template != nil {
+		if !validResumeTemplate(*input.Template) {
+			apierror.Abort(c, apierror.BadRequest("Unknown template: "+*input.Template))
+			return
+		}
+		updates["template"] = *input.Template
+	}
+	if input.TargetJobID != nil {
+		if !targetJobExists(c, *input.TargetJobID) {
+			return
+		}
+		updates["target_job_id"] = *input.TargetJobID
+	}
+	if len(input.Content) > 0 && string(input.Content) != "null" {
+		merged, err := mergeResumeContent(draft.Content, input.Content)
+		if err != nil {
+			apierror.Abort(c, apierror.BadRequest(err.Error()))
+			return
+		}
+		content, _, err := normaliseResumeContent(merged)
+		if err != nil {
+			apierror.Abort(c, apierror.BadRequest(err.Error()))
+			return
+		}
+		updates["content"] = content
+	}
+	if len(updates) == 0 {
+		apierror.Abort(c, apierror.BadRequest("Nothing to update"))
+		return
+	}
+
+	if err := DB.Model(&draft).Updates(updates).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to update resume draft"))
+		return
+	}
+	DB.First(&draft, draft.ID)
+
+	c.JSON(http.StatusOK, resumeDraftResponse{
+		Message: "Resume draft updated successfully",
+		Draft:   draft,
+	})
+}
+
+// DeleteResumeDraft - Student deletes a draft. Versions already attached to
+// applications stay readable by the companies that received them.
+func DeleteResumeDraft(c *gin.Context) {
+	draft, ok := loadOwnDraft(c)
+	if !ok {
+		return
+	}
+	if err := DB.Delete(&draft).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to delete resume draft"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Resume draft deleted successfully"})
+}
+
+type duplicateResumeDraftInput struct {
+	Name        string `json:"name"`
+	TargetJobID *uint  `json:"target_job_id"`
+}
+
+// DuplicateResumeDraft - Student copies a draft, typically to tailor it for a job
+func DuplicateResumeDraft(c *gin.Context) {
+	source, ok := loadOwnDraft(c)
+	if !ok {
+		return
+	}
+
+	var input duplicateResumeDraftInput
+	c.ShouldBindJSON(&input) // optional body
+
+	duplicate := models.ResumeDraft{
+		UserID:         source.UserID,
+		Name:           strings.TrimSpace(input.Name),
+		Template:       source.Template,
+		TargetJobID:    source.TargetJobID,
+		DuplicatedFrom: &source.ID,
+		Content:        source.Content,
+	}
+	if input.TargetJobID != nil {
+		var job models.JobListing
+		if err := DB.First(&job, *input.TargetJobID).Error; err != nil {
+			apierror.Abort(c, apierror.NotFound("Job listing not found"))
+			return
+		}
+		duplicate.TargetJobID = &job.ID
+		if duplicate.Name == "" {
+			duplicate.Name = source.Name + " - " + job.Title
+		}
+	}
+	if duplicate.Name == "" {
+		duplicate.Name = source.Name + " (copy)"
+	}
+
+	if err := DB.Create(&duplicate).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to duplicate resume draft"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, resumeDraftResponse{
+		Message: "Resume draft duplicated successfully",
+		Draft:   duplicate,
+	})
+}
+
+// RenderResumeDraft - Student previews the current draft as PDF or HTML (?format=html)
+func RenderResumeDraft(c *gin.Context) {
+	draft, ok := loadOwnDraft(c)
+	if !ok {
+		return
+	}
+	_, content, err := normaliseResumeContent(draft.Content)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err.Error()))
+		return
+	}
+
+	var user models.User
+	if err := DB.First(&user, draft.UserID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("User not found"))
+		return
+	}
+
+	format := c.DefaultQuery("format", resume.FormatPDF)
+	result, err := resume.Local{}.Render(c.Request.Context(), buildResumeDocument(user, content.toRequest(draft.Template)), resume.Options{
+		Template: draft.Template,
+		Format:   format,
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Unsupported format: "+format))
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", "resume."+format))
+	c.Data(http.StatusOK, result.ContentType, result.Data)
+}
+
+type resumeVersionInput struct {
+	Label string `json:"label"`
+}
+
+// CreateResumeVersion - Student freezes the current draft as a named version.
+// The PDF is rendered now and kept, so it never changes after submission.
+func CreateResumeVersion(c *gin.Context) {
+	draft, ok := loadOwnDraft(c)
+	if !ok {
+		return
+	}
+
+	var input resumeVersionInput
+	c.ShouldBindJSON(&input) // optional body
+
+	_, content, err := normaliseResumeContent(draft.Content)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal(err.Error()))
+		return
+	}
+	var user models.User
+	if err := DB.First(&user, draft.UserID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("User not found"))
+		return
+	}
+
+	// Platform projects and certificates are resolved now and frozen into the snapshot
+	request := content.toRequest(draft.Template)
+	doc := buildResumeDocument(user, request)
+	result, err := resume.Local{}.Render(c.Request.Context(), doc, resume.Options{Template: draft.Template, Format: resume.FormatPDF})
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to render resume"))
+		return
+	}
+	snapshot, err := json.Marshal(doc)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to save resume version"))
+		return
+	}
+
+	file, err := uploadGeneratedResume(c, draft.UserID, fmt.Sprintf("resume-%d.pdf", draft.ID), result.Data)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to save resume version"))
+		return
+	}
+
+	var version models.ResumeVersion
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		var locked models.ResumeDraft
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, draft.ID).Error; err != nil {
+			return err
+		}
+		number := locked.LatestVersion + 1
+
+		label := strings.TrimSpace(input.Label)
+		if label == "" {
+			label = fmt.Sprintf("Version %d", number)
+		}
+		file.FileName = fmt.Sprintf("resume-%d-v%d.pdf", draft.ID, number)
+		if err := tx.Create(&file).Error; err != nil {
+			return err
+		}
+
+		version = models.ResumeVersion{
+			DraftID:      draft.ID,
+			Version:      number,
+			Label:        label,
+			UserID:       draft.UserID,
+			Template:     draft.Template,
+			Content:      snapshot,
+			ResumeFileID: &file.ID,
+		}
+		if err := tx.Create(&version).Error; err != nil {
+			return err
+		}
+		return tx.Model(&locked).UpdateColumn("latest_version", number).Error
+	})
+	if err != nil {
+		ResumeStore.Delete(c.Request.Context(), file.StorageKey)
+		apierror.Abort(c, apierror.Internal("Failed to save resume version"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, resumeVersionResponse{
+		Message: "Resume version saved successfully",
+		Version: version,
+	})
+}
+
+type resumeVersionResponse struct {
+	Message string               `json:"message,omitempty"`
+	Version models.ResumeVersion `json:"version"`
+}
+
+// GetResumeVersion - Student views one version of a draft
+func GetResumeVersion(c *gin.Context) {
+	draft, ok := loadOwnDraft(c)
+	if !ok {
+		return
+	}
+
+	var version models.ResumeVersion
+	if err := DB.Where("draft_id = ? AND version = ?", draft.ID, c.Param("version")).First(&version).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Resume version not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, resumeVersionResponse{Version: version})
+}
+
+// RestoreResumeVersion - Student copies a version's content back into the draft
+func RestoreResumeVersion(c *gin.Context) {
+	draft, ok := loadOwnDraft(c)
+	if !ok {
+		return
+	}
+
+	var version models.ResumeVersion
+	if err := DB.Where("draft_id = ? AND version = ?", draft.ID, c.Param("version")).First(&version).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Resume version not found"))
+		return
+	}
+
+	// Versions store the resolved document; map it back to editable content
+	var doc resume.Document
+	if err := json.Unmarshal(version.Content, &doc); err != nil {
+		apierror.Abort(c, apierror.Internal("Resume version is unreadable"))
+		return
+	}
+	content := documentContent(doc)
+	// Platform entries are now part of the content, so don't add them a second time
+	off := false
+	content.IncludePlatformProjects = &off
+	content.IncludeCertificates = &off
+
+	raw, err := json.Marshal(content)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to restore resume version"))
+		return
+	}
+	if err := DB.Model(&draft).Updates(map[string]interface{}{"content": raw, "template": version.Template}).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to restore resume version"))
+		return
+	}
+	DB.First(&draft, draft.ID)
+
+	c.JSON(http.StatusOK, resumeDraftResponse{
+		Message: fmt.Sprintf("Draft restored to version %d", version.Version),
+		Draft:   draft,
+	})
+}
+
+// applicationResumeVersionResponse links the version's PDF when one was saved
+type applicationResumeVersionResponse struct {
+	Version   models.ResumeVersion `json:"version"`
+	FileURL   string               `json:"file_url,omitempty"`
+	ExpiresAt *time.Time           `json:"expires_at,omitempty"`
+}
+
+// GetApplicationResumeVersion - Company views the exact resume version an applicant submitted
+func GetApplicationResumeVersion(c *gin.Context) {
+	companyID := c.GetUint("userID")
+
+	var application models.JobApplication
+	if err := DB.Preload("JobListing").First(&application, c.Param("id")).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Application not found"))
+		return
+	}
+	if !companyOwns(companyID, application.JobListing.CompanyID, application.JobListing.OrganisationID) {
+		apierror.Abort(c, apierror.Forbidden("Unauthorized"))
+		return
+	}
+	if application.ResumeVersionID == nil {
+		apierror.Abort(c, apierror.NotFound("This application has no resume version attached"))
+		return
+	}
+
+	var version models.ResumeVersion
+	if err := DB.First(&version, *application.ResumeVersionID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Resume version not found"))
+		return
+	}
+
+	response := applicationResumeVersionResponse{Version: version}
+	if version.ResumeFileID != nil {
+		url, expires, err := utils.SignedFileURL(fmt.Sprintf("/api/files/resumes/%d", *version.ResumeFileID), resumeURLTTL)
+		if err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to sign the download link"))
+			return
+		}
+		response.FileURL = url
+		response.ExpiresAt = &expires
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,309 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/config"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"SkillBridge/storage"
+	"SkillBridge/utils"
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResumeStore holds uploaded resume files
+var ResumeStore storage.Store
+
+// resumeURLTTL is how long a company's resume download link stays valid
+const resumeURLTTL = 15 * time.Minute
+
+const (
+	contentTypePDF  = "application/pdf"
+	contentTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// InitStorage sets the backend used for uploaded files
+func InitStorage(store storage.Store) {
+	ResumeStore = store
+}
+
+// maxResumeBytes is the upload limit, 5 MB unless RESUME_MAX_BYTES is set
+func maxResumeBytes() int64 {
+	limit, err := strconv.ParseInt(config.GetEnv("RESUME_MAX_BYTES", "5242880"), 10, 64)
+	if err != nil || limit <= 0 {
+		return 5 << 20
+	}
+	return limit
+}
+
+// detectResumeType checks the file's contents match its extension and returns its MIME type.
+// Browsers send unreliable Content-Type headers, so only the bytes are trusted.
+func detectResumeType(ext string, data []byte) (string, error) {
+	switch ext {
+	case ".pdf":
+		if http.DetectContentType(data) != contentTypePDF {
+			return "", errors.New("file is not a valid PDF")
+		}
+		return contentTypePDF, nil
+	case ".docx":
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return "", errors.New("file is not a valid DOCX document")
+		}
+		for _, f := range archive.File {
+			if f.Name == "word/document.xml" {
+				return contentTypeDOCX, nil
+			}
+		}
+		return "", errors.New("file is not a valid DOCX document")
+	default:
+		return "", errors.New("only PDF and DOCX resumes are accepted")
+	}
+}
+
+// UploadResume - Student uploads a PDF or DOCX resume (multipart field "file")
+func UploadResume(c *gin.Context) {
+	studentID := c.GetUint("userID")
+	limit := maxResumeBytes()
+
+	// Leave room for the multipart envelope around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+64<<10)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apierror.Abort(c, apierror.PayloadTooLarge(fmt.Sprintf("Resume must be at most %d MB", limit>>20)))
+			return
+		}
+		apierror.Abort(c, apierror.BadRequest("A resume file is required in the \"file\" field"))
+		return
+	}
+	if header.Size > limit {
+		apierror.Abort(c, apierror.PayloadTooLarge(fmt.Sprintf("Resume must be at most %d MB", limit>>20)))
+		return
+	}
+	if header.Size == 0 {
+		apierror.Abort(c, apierror.BadRequest("Resume file is empty"))
+		return
+	}
+
+	f, err := header.Open()
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest("Could not read uploaded file"))
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil || int64(len(data)) > limit {
+		apierror.Abort(c, apierror.BadRequest("Could not read uploaded file"))
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	contentType, err := detectResumeType(ext, data)
+	if err != nil {
+		apierror.Abort(c, apierror.UnsupportedMediaType(err.Error()))
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	resume := models.ResumeFile{
+		UserID:      studentID,
+		FileName:    filepath.Base(header.Filename),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(sum[:]),
+		StorageKey:  fmt.Sprintf("resumes/%d/%s%s", studentID, utils.RandomToken(16), ext),
+	}
+
+	if err := ResumeStore.Put(c.Request.Context(), resume.StorageKey, bytes.NewReader(data), resume.Size, contentType); err != nil {
+		middleware.Log(c).Error("failed to store resume", "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to store resume"))
+		return
+	}
+	if err := DB.Create(&resume).Error; err != nil {
+		ResumeStore.Delete(c.Request.Context(), resume.StorageKey)
+		apierror.Abort(c, apierror.Internal("Failed to save resume"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, resumeFileResponse{
+		Message: "Resume uploaded successfully",
+		Resume:  resume,
+	})
+}
+
+type resumeFileResponse struct {
+	Message string            `json:"message"`
+	Resume  models.ResumeFile `json:"resume"`
+}
+
+type resumeFilesResponse struct {
+	Resumes []models.ResumeFile `json:"resumes"`
+	Count   int                 `json:"count"`
+}
+
+// signedURLResponse is a download link and when it stops working
+type signedURLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// saveGeneratedResume stores a rendered PDF as one of the student's resumes
+func saveGeneratedResume(c *gin.Context, userID uint, fileName string, data []byte) (models.ResumeFile, error) {
+	file, err := uploadGeneratedResume(c, userID, fileName, data)
+	if err != nil {
+		return file, err
+	}
+	if err := DB.Create(&file).Error; err != nil {
+		ResumeStore.Delete(c.Request.Context(), file.StorageKey)
+		return file, err
+	}
+	return file, nil
+}
+
+// uploadGeneratedResume puts a rendered PDF in storage and returns its
+// ResumeFile for the caller to save, so uploads stay out of transactions
+func uploadGeneratedResume(c *gin.Context, userID uint, fileName string, data []byte) (models.ResumeFile, error) {
+	sum := sha256.Sum256(data)
+	file := models.ResumeFile{
+		UserID:      userID,
+		FileName:    fileName,
+		ContentType: contentTypePDF,
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(sum[:]),
+		StorageKey:  fmt.Sprintf("resumes/%d/%s.pdf", userID, utils.RandomToken(16)),
+	}
+	if err := ResumeStore.Put(c.Request.Context(), file.StorageKey, bytes.NewReader(data), file.Size, file.ContentType); err != nil {
+		middleware.Log(c).Error("failed to store generated resume", "error", err)
+		return file, err
+	}
+	return file, nil
+}
+
+// GetMyResumes - Student lists their uploaded resumes
+func GetMyResumes(c *gin.Context) {
+	var resumes []models.ResumeFile
+	if err := DB.Where("user_id = ?", c.GetUint("userID")).Order("created_at DESC").Find(&resumes).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch resumes"))
+		return
+	}
+
+	c.JSON(http.StatusOK, resumeFilesResponse{
+		Resumes: resumes,
+		Count:   len(resumes),
+	})
+}
+
+// DeleteResume - Student removes an uploaded resume. Files attached to applications
+// or resume versions are kept so companies can still read them.
+func DeleteResume(c *gin.Context) {
+	var resume models.ResumeFile
+	if err := DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("userID")).First(&resume).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Resume not found"))
+		return
+	}
+
+	var attached, versioned int64
+	DB.Model(&models.JobApplication{}).Where("resume_file_id = ?", resume.ID).Count(&attached)
+	DB.Model(&models.ResumeVersion{}).Where("resume_file_id = ?", resume.ID).Count(&versioned)
+
+	if err := DB.Delete(&resume).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to delete resume"))
+		return
+	}
+	if attached == 0 && versioned == 0 {
+		if err := ResumeStore.Delete(c.Request.Context(), resume.StorageKey); err != nil {
+			middleware.Log(c).Warn("failed to delete stored resume", "key", resume.StorageKey, "error", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Resume deleted successfully"})
+}
+
+// DownloadMyResume - Student downloads one of their own resumes
+func DownloadMyResume(c *gin.Context) {
+	var resume models.ResumeFile
+	if err := DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("userID")).First(&resume).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Resume not found"))
+		return
+	}
+	serveResume(c, resume)
+}
+
+// GetApplicationResumeURL - Company gets a short-lived download link for an applicant's resume
+func GetApplicationResumeURL(c *gin.Context) {
+	companyID := c.GetUint("userID")
+
+	var application models.JobApplication
+	if err := DB.Preload("JobListing").First(&application, c.Param("id")).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Application not found"))
+		return
+	}
+	if !companyOwns(companyID, application.JobListing.CompanyID, application.JobListing.OrganisationID) {
+		apierror.Abort(c, apierror.Forbidden("Unauthorized"))
+		return
+	}
+	if application.ResumeFileID == nil {
+		apierror.Abort(c, apierror.NotFound("This application has no uploaded resume"))
+		return
+	}
+
+	url, expires, err := utils.SignedFileURL(fmt.Sprintf("/api/files/resumes/%d", *application.ResumeFileID), resumeURLTTL)
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to sign the download link"))
+		return
+	}
+	c.JSON(http.StatusOK, signedURLResponse{
+		URL:       url,
+		ExpiresAt: expires,
+	})
+}
+
+// DownloadSignedResume - Serves a resume through a signed, time-limited link
+func DownloadSignedResume(c *gin.Context) {
+	if !utils.VerifyFileURL(c.Request.URL.Path, c.Query("expires"), c.Query("signature")) {
+		apierror.Abort(c, apierror.Forbidden("This link is invalid or has expired"))
+		return
+	}
+
+	// Resumes attached to applications stay readable after the student deletes them
+	var resume models.ResumeFile
+	if err := DB.Unscoped().First(&resume, c.Param("id")).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Resume not found"))
+		return
+	}
+	serveResume(c, resume)
+}
+
+// serveResume streams a stored resume to the client
+func serveResume(c *gin.Context, resume models.ResumeFile) {
+	reader, err := ResumeStore.Get(c.Request.Context(), resume.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			apierror.Abort(c, apierror.NotFound("Resume file is missing"))
+			return
+		}
+		middleware.Log(c).Error("failed to read resume", "key", resume.StorageKey, "error", err)
+		apierror.Abort(c, apierror.Internal("Failed to read resume"))
+		return
+	}
+	defer reader.Close()
+
+	c.Header("Cache-Control", "private, no-store")
+	c.DataFromReader(http.StatusOK, resume.Size, resume.ContentType, reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", resume.FileName),
+	})
+}
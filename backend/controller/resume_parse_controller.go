@@ -0,0 +1,329 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"SkillBridge/resume"
+	"SkillBridge/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileChange is one profile field the parser would fill in or change
+type ProfileChange struct {
+	Field    string `json:"field"`
+	Current  string `json:"current"`
+	Proposed string `json:"proposed"`
+}
+
+// parsedProfileFields are the profile fields a parsed resume may update, by
+// their API name, mapped to the users column they are stored in
+var parsedProfileFields = map[string]string{
+	"name": "name", "phone": "phone", "bio": "bio", "linkedin": "linked_in", "github_url": "github_url",
+	"portfolio_url": "portfolio_url", "university": "university", "major": "major", "skills": "skills",
+}
+
+// readResumeForParsing loads the resume to parse, either from a multipart
+// "file" upload or from one of the student's stored resumes (resume_file_id)
+func readResumeForParsing(c *gin.Context, studentID uint) (string, []byte, error) {
+	limit := maxResumeBytes()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+64<<10)
+
+	id := c.Query("resume_file_id")
+	if id == "" {
+		id = c.PostForm("resume_file_id")
+	}
+	if id != "" {
+		var file models.ResumeFile
+		if err := DB.Where("id = ? AND user_id = ?", id, studentID).First(&file).Error; err != nil {
+			return "", nil, errors.New("Resume not found")
+		}
+		reader, err := ResumeStore.Get(c.Request.Context(), file.StorageKey)
+		if err != nil {
+			middleware.Log(c).Error("failed to read resume", "key", file.StorageKey, "error", err)
+			return "", nil, errors.New("Failed to read resume")
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(io.LimitReader(reader, limit+1))
+		if err != nil {
+			return "", nil, errors.New("Failed to read resume")
+		}
+		return file.ContentType, data, nil
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return "", nil, errors.New("Send a resume in the \"file\" field or a resume_file_id")
+	}
+	if header.Size > limit {
+		return "", nil, fmt.Errorf("Resume must be at most %d MB", limit>>20)
+	}
+	f, err := header.Open()
+	if err != nil {
+		return "", nil, errors.New("Could not read uploaded file")
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil || int64(len(data)) > limit {
+		return "", nil, errors.New("Could not read uploaded file")
+	}
+
+	ext := strings.ToLower(filepath.Ext(header.Filename))
+	if ext == ".txt" {
+		if !utf8.Valid(data) {
+			return "", nil, errors.New("Text resumes must be UTF-8")
+		}
+		return resume.ContentTypeText, data, nil
+	}
+	contentType, err := detectResumeType(ext, data)
+	if err != nil {
+		return "", nil, errors.New("Only PDF, DOCX and plain text resumes can be parsed")
+	}
+	return contentType, data, nil
+}
+
+// proposedProfile maps a parsed resume onto the profile columns it can fill
+func proposedProfile(doc resume.Document, user models.User) map[string]string {
+	proposed := map[string]string{
+		"name":  doc.Name,
+		"phone": doc.Phone,
+		"bio":   doc.Bio,
+	}
+	for _, link := range doc.Links {
+		lower := strings.ToLower(link)
+		switch {
+		case strings.Contains(lower, "linkedin.com"):
+			proposed["linkedin"] = absoluteLink(link)
+		case strings.Contains(lower, "github.com"):
+			proposed["github_url"] = absoluteLink(link)
+		case proposed["portfolio_url"] == "":
+			proposed["portfolio_url"] = absoluteLink(link)
+		}
+	}
+	if len(doc.Education) > 0 {
+		proposed["university"] = doc.Education[0].Institution
+		proposed["major"] = doc.Education[0].Field
+	}
+
+	// Skills are added to the existing list rather than replacing it
+	skills := utils.ParseSkills(user.Skills)
+	seen := map[string]bool{}
+	for _, s := range skills {
+		seen[strings.ToLower(s)] = true
+	}
+	for _, s := range doc.Skills {
+		if !seen[strings.ToLower(s)] {
+			seen[strings.ToLower(s)] = true
+			skills = append(skills, s)
+		}
+	}
+	proposed["skills"] = strings.Join(skills, ", ")
+	return proposed
+}
+
+// absoluteLink adds a scheme to bare links such as "github.com/jane"
+func absoluteLink(link string) string {
+	if strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") {
+		return link
+	}
+	return "https://" + link
+}
+
+// currentProfileValue reads one of parsedProfileFields from the user
+func currentProfileValue(user models.User, field string) string {
+	switch field {
+	case "name":
+		return user.Name
+	case "phone":
+		return user.Phone
+	case "bio":
+		return user.Bio
+	case "linkedin":
+		return user.LinkedIn
+	case "github_url":
+		return user.GithubURL
+	case "portfolio_url":
+		return user.PortfolioURL
+	case "university":
+		return user.University
+	case "major":
+		return user.Major
+	case "skills":
+		return user.Skills
+	}
+	return ""
+}
+
+// ParseResume - Student uploads a resume (PDF, DOCX or text) and gets back the
+// profile fields and resume sections it contains. Nothing is saved; the student
+// picks what to keep and sends it to ApplyParsedResume.
+func ParseResume(c *gin.Context) {
+	studentID := c.GetUint("userID")
+
+	contentType, data, err := readResumeForParsing(c, studentID)
+	if err != nil {
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
+		return
+	}
+	text, err := resume.ExtractText(contentType, data)
+	if err != nil {
+		if errors.Is(err, resume.ErrNoText) {
+			apierror.Abort(c, apierror.Unprocessable("No readable text was found in this resume. Scanned PDFs are not supported; try a DOCX or text version."))
+			return
+		}
+		apierror.Abort(c, apierror.Unprocessable("Could not read this resume: "+err.Error()))
+		return
+	}
+
+	var user models.User
+	if err := DB.First(&user, studentID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("User not found"))
+		return
+	}
+
+	doc := resume.Parse(text)
+	proposed := proposedProfile(doc, user)
+	changes := []ProfileChange{}
+	for _, field := range []string{"name", "phone", "bio", "linkedin", "github_url", "portfolio_url", "university", "major", "skills"} {
+		current := currentProfileValue(user, field)
+		if proposed[field] != "" && proposed[field] != current {
+			changes = append(changes, ProfileChange{Field: field, Current: current, Proposed: proposed[field]})
+		}
+	}
+
+	c.JSON(http.StatusOK, parsedResumeResponse{
+		Parsed:         doc,
+		ProfileChanges: changes,
+		ResumeContent:  documentContent(doc),
+	})
+}
+
+type parsedResumeResponse struct {
+	Parsed         resume.Document `json:"parsed"`
+	ProfileChanges []ProfileChange `json:"profile_changes"`
+	ResumeContent  resumeContent   `json:"resume_content"`
+}
+
+// applyParsedResumeResponse has the draft and profile fields that were written
+type applyParsedResumeResponse struct {
+	Message string                 `json:"message"`
+	Draft   *models.ResumeDraft    `json:"draft,omitempty"`
+	Profile map[string]interface{} `json:"profile,omitempty"`
+}
+
+type applyParsedResumeInput struct {
+	Profile       map[string]string `json:"profile"`
+	ResumeContent json.RawMessage   `json:"resume_content"`
+	DraftID       *uint             `json:"draft_id"`
+	DraftName     string            `json:"draft_name"`
+}
+
+// ApplyParsedResume - Student accepts the parsed fields they want. "profile"
+// holds the chosen profile fields; "resume_content" is saved into a new draft,
+// or merged into draft_id when given.
+func ApplyParsedResume(c *gin.Context) {
+	studentID := c.GetUint("userID")
+
+	var input applyParsedResumeInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	hasContent := len(input.ResumeContent) > 0 && string(input.ResumeContent) != "null"
+	if len(input.Profile) == 0 && !hasContent {
+		apierror.Abort(c, apierror.BadRequest("Nothing to apply"))
+		return
+	}
+
+	updates := map[string]interface{}{}
+	for field, value := range input.Profile {
+		if _, ok := parsedProfileFields[field]; !ok {
+			apierror.Abort(c, apierror.BadRequest("Field cannot be set from a resume: "+field))
+			return
+		}
+		updates[field] = strings.TrimSpace(value)
+	}
+	if name, ok := updates["name"]; ok && name == "" {
+		apierror.Abort(c, apierror.BadRequest("Name cannot be empty"))
+		return
+	}
+	for _, field := range profileURLFields {
+		if value, ok := updates[field].(string); ok && value != "" && !isHTTPURL(value) {
+			apierror.Abort(c, apierror.Validation("Invalid request", []models.FieldError{{
+				Field:   "profile." + field,
+				Rule:    "httpurl",
+				Message: "must be an http(s) URL",
+			}}))
+			return
+		}
+	}
+
+	var draft models.ResumeDraft
+	var content json.RawMessage
+	if hasContent {
+		var err error
+		if input.DraftID != nil {
+			if err := DB.Where("id = ? AND user_id = ?", *input.DraftID, studentID).First(&draft).Error; err != nil {
+				apierror.Abort(c, apierror.NotFound("Resume draft not found"))
+				return
+			}
+			if content, err = mergeResumeContent(draft.Content, input.ResumeContent); err != nil {
+				apierror.Abort(c, apierror.BadRequest(err.Error()))
+				return
+			}
+		} else {
+			content = input.ResumeContent
+		}
+		if content, _, err = normaliseResumeContent(content); err != nil {
+			apierror.Abort(c, apierror.BadRequest(err.Error()))
+			return
+		}
+	}
+
+	if len(updates) > 0 {
+		columns := make(map[string]interface{}, len(updates))
+		for field, value := range updates {
+			columns[parsedProfileFields[field]] = value
+		}
+		if err := DB.Model(&models.User{}).Where("id = ?", studentID).Updates(columns).Error; err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to update profile"))
+			return
+		}
+		invalidateStudentFits(studentID)
+	}
+
+	response := applyParsedResumeResponse{Message: "Resume details applied successfully"}
+	if hasContent {
+		if draft.ID != 0 {
+			if err := DB.Model(&draft).Update("content", content).Error; err != nil {
+				apierror.Abort(c, apierror.Internal("Failed to update resume draft"))
+				return
+			}
+			DB.First(&draft, draft.ID)
+		} else {
+			name := strings.TrimSpace(input.DraftName)
+			if name == "" {
+				name = "Imported resume"
+			}
+			draft = models.ResumeDraft{UserID: studentID, Name: name, Content: content}
+			if err := DB.Create(&draft).Error; err != nil {
+				apierror.Abort(c, apierror.Internal("Failed to save resume draft"))
+				return
+			}
+		}
+		response.Draft = &draft
+	}
+
+	if len(updates) > 0 {
+		response.Profile = updates
+	}
+	c.JSON(http.StatusOK, response)
+}
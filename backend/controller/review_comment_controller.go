@@ -0,0 +1,330 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"SkillBridge/utils"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// loadAccessibleSubmission fetches a submission the current user may view and comment on:
+// the submitting student, the owning company, the assigned guide or an admin.
+// It writes the error response itself and returns ok=false when access is denied.
+func loadAccessibleSubmission(c *gin.Context, submissionID string) (models.Submission, bool) {
+	userID := c.GetUint("userID")
+	role := c.GetString("role")
+
+	var submission models.Submission
+	if err := DB.Preload("Project").First(&submission, submissionID).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Submission not found"))
+		return submission, false
+	}
+
+	allowed := false
+	switch role {
+	case "student":
+		allowed = submission.StudentID == userID
+	case "company":
+		allowed = companyOwns(userID, submission.Project.CompanyID, submission.Project.OrganisationID)
+	case "guide":
+		allowed = submission.Project.GuideID != nil && *submission.Project.GuideID == userID
+	case "admin":
+		allowed = true
+	}
+	if !allowed {
+		apierror.Abort(c, apierror.Forbidden("You do not have access to this submission"))
+		return submission, false
+	}
+	return submission, true
+}
+
+// submissionParticipants returns the IDs of users who take part in a submission's review
+func submissionParticipants(submission models.Submission) map[uint]bool {
+	participants := map[uint]bool{
+		submission.StudentID:         true,
+		submission.Project.CompanyID: true,
+	}
+	if submission.Project.GuideID != nil {
+		participants[*submission.Project.GuideID] = true
+	}
+	return participants
+}
+
+// threadsQuery preloads everything needed to render comment threads
+func threadsQuery(db *gorm.DB) *gorm.DB {
+	return db.Preload("Author", selectPublicUser).
+		Preload("Mentions.User", selectPublicUser).
+		Preload("Replies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Replies.Author", selectPublicUser).
+		Preload("Replies.Mentions.User", selectPublicUser)
+}
+
+// GetSubmissionDetail - Get a submission with its rubric reviews and comment threads
+func GetSubmissionDetail(c *gin.Context) {
+	submission, ok := loadAccessibleSubmission(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	if err := DB.Preload("Student", selectPublicUser).
+		Preload("Project").
+		Preload("Reviews.Scores.Criterion").
+		Preload("Reviews.Reviewer", selectPublicUser).
+		Preload("Comments", func(db *gorm.DB) *gorm.DB {
+			return threadsQuery(db).Where("parent_id IS NULL").Order("created_at ASC")
+		}).
+		First(&submission, submission.ID).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch submission"))
+		return
+	}
+
+	c.JSON(http.StatusOK, submissionResponse{Submission: submission})
+}
+
+// GetSubmissionComments - List comment threads on a submission, optionally filtered by file or resolution state
+func GetSubmissionComments(c *gin.Context) {
+	submission, ok := loadAccessibleSubmission(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	query := threadsQuery(DB).Where("submission_id = ? AND parent_id IS NULL", submission.ID)
+	if filePath := c.Query("file_path"); filePath != "" {
+		query = query.Where("file_path = ?", filePath)
+	}
+	if resolved := c.Query("resolved"); resolved != "" {
+		query = query.Where("resolved = ?", resolved == "true")
+	}
+
+	var threads []models.ReviewComment
+	if err := query.Order("file_path ASC, start_line ASC, created_at ASC").Find(&threads).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch comments"))
+		return
+	}
+
+	c.JSON(http.StatusOK, commentsResponse{Comments: threads, Count: len(threads)})
+}
+
+type commentsResponse struct {
+	Comments []models.ReviewComment `json:"comments"`
+	Count    int                    `json:"count"`
+}
+
+type commentResponse struct {
+	Message string               `json:"message"`
+	Comment models.ReviewComment `json:"comment"`
+}
+
+type submissionCommentInput struct {
+	Body      string `json:"body" binding:"required"`
+	ParentID  *uint  `json:"parent_id"`
+	FilePath  string `json:"file_path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	Section   string `json:"section"`
+	Mentions  []uint `json:"mentions"` // user IDs of submission participants
+}
+
+// AddSubmissionComment - Start a comment thread or reply to one
+func AddSubmissionComment(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	submission, ok := loadAccessibleSubmission(c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	var input submissionCommentInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	comment := models.ReviewComment{
+		SubmissionID: submission.ID,
+		AuthorID:     userID,
+		Body:         strings.TrimSpace(input.Body),
+	}
+	if comment.Body == "" {
+		apierror.Abort(c, apierror.BadRequest("Comment body cannot be empty"))
+		return
+	}
+
+	var parent models.ReviewComment
+	if input.ParentID != nil {
+		if err := DB.Where("id = ? AND submission_id = ?", *input.ParentID, submission.ID).First(&parent).Error; err != nil {
+			apierror.Abort(c, apierror.NotFound("Parent comment not found"))
+			return
+		}
+		if parent.ParentID != nil {
+			apierror.Abort(c, apierror.BadRequest("Replies must be made to the top-level comment of a thread"))
+			return
+		}
+		// Replies share the anchor of their thread
+		comment.ParentID = &parent.ID
+		comment.FilePath = parent.FilePath
+		comment.StartLine = parent.StartLine
+		comment.EndLine = parent.EndLine
+		comment.Section = parent.Section
+	} else if input.FilePath != "" {
+		if input.StartLine < 1 {
+			apierror.Abort(c, apierror.BadRequest("start_line must be at least 1 when file_path is set"))
+			return
+		}
+		if input.EndLine == 0 {
+			input.EndLine = input.StartLine
+		}
+		if input.EndLine < input.StartLine {
+			apierror.Abort(c, apierror.BadRequest("end_line cannot be before start_line"))
+			return
+		}
+		comment.FilePath = strings.TrimPrefix(input.FilePath, "/")
+		comment.StartLine = input.StartLine
+		comment.EndLine = input.EndLine
+	} else {
+		comment.Section = input.Section
+		if comment.Section == "" {
+			comment.Section = "general"
+		}
+	}
+
+	participants := submissionParticipants(submission)
+	mentioned := map[uint]bool{}
+	for _, id := range input.Mentions {
+		if !participants[id] {
+			apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("User %d is not a participant in this submission", id)))
+			return
+		}
+		if id != userID && !mentioned[id] {
+			mentioned[id] = true
+			comment.Mentions = append(comment.Mentions, models.ReviewCommentMention{UserID: id})
+		}
+	}
+
+	if err := DB.Create(&comment).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to add comment"))
+		return
+	}
+
+	// Notify mentioned users, the parent's author and the student, once each
+	notified := map[uint]bool{userID: true}
+	notify := func(recipient uint, message string) {
+		if notified[recipient] {
+			return
+		}
+		notified[recipient] = true
+		if err := utils.CreateNotification(DB, recipient, message); err != nil {
+			middleware.Log(c).Error("failed to send notification", "error", err)
+		}
+	}
+	for id := range mentioned {
+		notify(id, "You were mentioned in a review comment on "+submission.Project.Title)
+	}
+	if comment.ParentID != nil {
+		notify(parent.AuthorID, "Someone replied to your review comment on "+submission.Project.Title)
+	}
+	notify(submission.StudentID, "New review comment on your submission for "+submission.Project.Title)
+
+	if err := DB.Preload("Author", selectPublicUser).Preload("Mentions.User", selectPublicUser).First(&comment, comment.ID).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to load the new comment"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, commentResponse{Message: "Comment added successfully", Comment: comment})
+}
+
+type resolveCommentInput struct {
+	Resolved bool `json:"resolved"`
+}
+
+type resolveCommentResponse struct {
+	Message   string `json:"message"`
+	CommentID uint   `json:"comment_id"`
+	Resolved  bool   `json:"resolved"`
+}
+
+// ResolveSubmissionComment - Mark a comment thread resolved or reopen it
+func ResolveSubmissionComment(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var comment models.ReviewComment
+	if err := DB.First(&comment, c.Param("id")).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Comment not found"))
+		return
+	}
+	if comment.ParentID != nil {
+		apierror.Abort(c, apierror.BadRequest("Only top-level comments can be resolved"))
+		return
+	}
+
+	if _, ok := loadAccessibleSubmission(c, fmt.Sprint(comment.SubmissionID)); !ok {
+		return
+	}
+
+	var input resolveCommentInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	updates := map[string]interface{}{
+		"resolved":       input.Resolved,
+		"resolved_by_id": nil,
+		"resolved_at":    nil,
+	}
+	if input.Resolved {
+		updates["resolved_by_id"] = userID
+		updates["resolved_at"] = time.Now()
+	}
+	if err := DB.Model(&comment).Updates(updates).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to update comment"))
+		return
+	}
+
+	status := "unresolved"
+	if input.Resolved {
+		status = "resolved"
+	}
+	c.JSON(http.StatusOK, resolveCommentResponse{
+		Message:   "Comment " + status,
+		CommentID: comment.ID,
+		Resolved:  input.Resolved,
+	})
+}
+
+// DeleteSubmissionComment - Author removes their own comment (and its replies if it starts a thread)
+func DeleteSubmissionComment(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var comment models.ReviewComment
+	if err := DB.First(&comment, c.Param("id")).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Comment not found"))
+		return
+	}
+	if comment.AuthorID != userID {
+		apierror.Abort(c, apierror.Forbidden("You can only delete your own comments"))
+		return
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if comment.ParentID == nil {
+			if err := tx.Where("parent_id = ?", comment.ID).Delete(&models.ReviewComment{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&comment).Error
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to delete comment"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Comment deleted successfully"})
+}
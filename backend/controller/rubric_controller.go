@@ -0,0 +1,338 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/models"
+	"SkillBridge/utils"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errRubricScored  = errors.New("Rubric cannot be changed after submissions have been scored")
+	errRubricChanged = errors.New("The project's rubric changed while you were scoring; reload it and score again")
+)
+
+// RubricScoreInput is a reviewer's score for a single rubric criterion
+type RubricScoreInput struct {
+	CriterionID uint   `json:"criterion_id" binding:"required"`
+	Score       int    `json:"score"`
+	Comment     string `json:"comment"`
+}
+
+type rubricInput struct {
+	Title    string `json:"title"`
+	Criteria []struct {
+		Name        string  `json:"name" binding:"required"`
+		Description string  `json:"description"`
+		Weight      float64 `json:"weight"`
+		MaxScore    int     `json:"max_score"`
+	} `json:"criteria" binding:"required,min=1,dive"`
+}
+
+// SetProjectRubric - Company creates or replaces the rubric for one of its projects
+func SetProjectRubric(c *gin.Context) {
+	companyID := c.GetUint("userID")
+
+	var project models.Project
+	if err := DB.Scopes(ownedByCompany("projects", companyID)).Where("id = ?", c.Param("id")).First(&project).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Project not found or not owned by you"))
+		return
+	}
+
+	var input rubricInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	criteria := make([]models.RubricCriterion, 0, len(input.Criteria))
+	for i, criterion := range input.Criteria {
+		weight := criterion.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		maxScore := criterion.MaxScore
+		if maxScore == 0 {
+			maxScore = 5
+		}
+		if weight < 0 || maxScore < 0 {
+			apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("Criterion %q must have a positive weight and max_score", criterion.Name)))
+			return
+		}
+		criteria = append(criteria, models.RubricCriterion{
+			Name:        criterion.Name,
+			Description: criterion.Description,
+			Weight:      weight,
+			MaxScore:    maxScore,
+			Position:    i,
+		})
+	}
+
+	var rubric models.Rubric
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		// The lock holds off reviews, which lock the rubric before scoring against it
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("project_id = ?", project.ID).First(&rubric).Error
+		hasRubric := err == nil
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if hasRubric {
+			// Replacing criteria would orphan the scores of existing reviews
+			var reviewCount int64
+			if err := tx.Model(&models.SubmissionReview{}).Where("rubric_id = ?", rubric.ID).Count(&reviewCount).Error; err != nil {
+				return err
+			}
+			if reviewCount > 0 {
+				return errRubricScored
+			}
+			if err := tx.Where("rubric_id = ?", rubric.ID).Delete(&models.RubricCriterion{}).Error; err != nil {
+				return err
+			}
+			rubric.Title = input.Title
+			rubric.Criteria = criteria
+			return tx.Save(&rubric).Error
+		}
+		rubric = models.Rubric{
+			ProjectID:   project.ID,
+			CreatedByID: companyID,
+			Title:       input.Title,
+			Criteria:    criteria,
+		}
+		return tx.Create(&rubric).Error
+	})
+	if errors.Is(err, errRubricScored) {
+		apierror.Abort(c, apierror.Conflict(err.Error()))
+		return
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to save rubric"))
+		return
+	}
+
+	c.JSON(http.StatusOK, rubricResponse{Message: "Rubric saved successfully", Rubric: rubric})
+}
+
+type rubricResponse struct {
+	Message string        `json:"message,omitempty"`
+	Rubric  models.Rubric `json:"rubric"`
+}
+
+// GetProjectRubric - Get the rubric attached to a project
+func GetProjectRubric(c *gin.Context) {
+	var rubric models.Rubric
+	if err := DB.Preload("Criteria", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("project_id = ?", c.Param("id")).First(&rubric).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Rubric not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, rubricResponse{Rubric: rubric})
+}
+
+// validateRubricScores checks that every score belongs to the rubric and is within its scale
+func validateRubricScores(rubric models.Rubric, scores []RubricScoreInput) error {
+	criteria := make(map[uint]models.RubricCriterion, len(rubric.Criteria))
+	for _, criterion := range rubric.Criteria {
+		criteria[criterion.ID] = criterion
+	}
+
+	seen := make(map[uint]bool, len(scores))
+	for _, score := range scores {
+		criterion, ok := criteria[score.CriterionID]
+		if !ok {
+			return fmt.Errorf("criterion %d is not part of this project's rubric", score.CriterionID)
+		}
+		if seen[score.CriterionID] {
+			return fmt.Errorf("criterion %d is scored more than once", score.CriterionID)
+		}
+		seen[score.CriterionID] = true
+		if score.Score < 0 || score.Score > criterion.MaxScore {
+			return fmt.Errorf("score for %q must be between 0 and %d", criterion.Name, criterion.MaxScore)
+		}
+	}
+	if len(seen) != len(criteria) {
+		return fmt.Errorf("all %d rubric criteria must be scored", len(criteria))
+	}
+	return nil
+}
+
+// saveRubricReview stores (or replaces) the reviewer's rubric scores for a
+// submission. Run it inside the transaction that updates the submission.
+func saveRubricReview(tx *gorm.DB, submission models.Submission, rubric models.Rubric, reviewerID uint, role, comment string, scores []RubricScoreInput) (models.SubmissionReview, error) {
+	scoreMap := make(map[uint]int, len(scores))
+	criterionScores := make([]models.CriterionScore, 0, len(scores))
+	for _, score := range scores {
+		scoreMap[score.CriterionID] = score.Score
+		criterionScores = append(criterionScores, models.CriterionScore{
+			CriterionID: score.CriterionID,
+			Score:       score.Score,
+			Comment:     score.Comment,
+		})
+	}
+
+	review := models.SubmissionReview{
+		SubmissionID: submission.ID,
+		ReviewerID:   reviewerID,
+		ReviewerRole: role,
+		RubricID:     rubric.ID,
+		OverallScore: utils.ComputeRubricScore(rubric.Criteria, scoreMap),
+		Comment:      comment,
+	}
+
+	var existing models.SubmissionReview
+	if err := tx.Where("submission_id = ? AND reviewer_id = ?", submission.ID, reviewerID).First(&existing).Error; err == nil {
+		if err := tx.Where("review_id = ?", existing.ID).Delete(&models.CriterionScore{}).Error; err != nil {
+			return review, err
+		}
+		review.ID = existing.ID
+		review.CreatedAt = existing.CreatedAt
+	}
+	if err := tx.Save(&review).Error; err != nil {
+		return review, err
+	}
+	for i := range criterionScores {
+		criterionScores[i].ReviewID = review.ID
+	}
+	if err := tx.Create(&criterionScores).Error; err != nil {
+		return review, err
+	}
+	review.Scores = criterionScores
+	return review, nil
+}
+
+// GetProjectReviewScores - Company compares rubric scores across all submissions of a project
+func GetProjectReviewScores(c *gin.Context) {
+	companyID := c.GetUint("userID")
+
+	var project models.Project
+	if err := DB.Scopes(ownedByCompany("projects", companyID)).Where("id = ?", c.Param("id")).First(&project).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Project not found or unauthorized"))
+		return
+	}
+
+	var rubric models.Rubric
+	if err := DB.Preload("Criteria", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("project_id = ?", project.ID).First(&rubric).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("This project has no rubric"))
+		return
+	}
+
+	var submissions []models.Submission
+	if err := DB.Preload("Student").Preload("Reviews.Scores").Where("project_id = ?", project.ID).Find(&submissions).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch submissions"))
+		return
+	}
+
+	results := make([]submissionScores, 0, len(submissions))
+	for _, submission := range submissions {
+		row := submissionScores{
+			SubmissionID:   submission.ID,
+			StudentID:      submission.StudentID,
+			StudentName:    submission.Student.Name,
+			CriterionScore: map[uint]float64{},
+		}
+		counts := map[uint]int{}
+		var total float64
+		for _, review := range submission.Reviews {
+			if review.RubricID != rubric.ID {
+				continue
+			}
+			row.ReviewCount++
+			total += review.OverallScore
+			for _, score := range review.Scores {
+				row.CriterionScore[score.CriterionID] += float64(score.Score)
+				counts[score.CriterionID]++
+			}
+		}
+		if row.ReviewCount > 0 {
+			row.AverageScore = total / float64(row.ReviewCount)
+		}
+		for id, sum := range row.CriterionScore {
+			row.CriterionScore[id] = sum / float64(counts[id])
+		}
+		results = append(results, row)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].AverageScore > results[j].AverageScore
+	})
+
+	c.JSON(http.StatusOK, reviewScoresResponse{
+		ProjectID:   project.ID,
+		Rubric:      rubric,
+		Submissions: results,
+	})
+}
+
+// reviewScoresResponse compares the rubric scores of a project's submissions, best first
+type reviewScoresResponse struct {
+	ProjectID   uint               `json:"project_id"`
+	Rubric      models.Rubric      `json:"rubric"`
+	Submissions []submissionScores `json:"submissions"`
+}
+
+type submissionScores struct {
+	SubmissionID   uint             `json:"submission_id"`
+	StudentID      uint             `json:"student_id"`
+	StudentName    string           `json:"student_name"`
+	ReviewCount    int              `json:"review_count"`
+	AverageScore   float64          `json:"average_score"`
+	CriterionScore map[uint]float64 `json:"criterion_scores"` // criterion ID -> average raw score
+}
+
+// reviewRubric loads the project's rubric and validates the scores sent with
+// ReviewSubmission. It returns a nil rubric when no scores were sent, and writes
+// the error response itself and returns ok=false when the request must stop.
+func reviewRubric(c *gin.Context, submission models.Submission, scores []RubricScoreInput) (*models.Rubric, bool) {
+	if len(scores) == 0 {
+		return nil, true
+	}
+
+	var rubric models.Rubric
+	if err := DB.Preload("Criteria").Where("project_id = ?", submission.ProjectID).First(&rubric).Error; err != nil {
+		apierror.Abort(c, apierror.BadRequest("This project has no rubric to score against"))
+		return nil, false
+	}
+	if err := validateRubricScores(rubric, scores); err != nil {
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
+		return nil, false
+	}
+	return &rubric, true
+}
+
+// saveSubmissionReview saves the reviewed submission together with the
+// reviewer's rubric scores, if any, so neither is stored without the other.
+// It returns errRubricChanged if the rubric was replaced after the scores
+// were checked against it.
+func saveSubmissionReview(submission *models.Submission, rubric *models.Rubric, reviewerID uint, role, comment string, scores []RubricScoreInput) (*models.SubmissionReview, error) {
+	var review *models.SubmissionReview
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if rubric != nil {
+			var locked models.Rubric
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Criteria").First(&locked, rubric.ID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errRubricChanged
+				}
+				return err
+			}
+			if validateRubricScores(locked, scores) != nil {
+				return errRubricChanged
+			}
+			saved, err := saveRubricReview(tx, *submission, locked, reviewerID, role, comment, scores)
+			if err != nil {
+				return err
+			}
+			review = &saved
+		}
+		return tx.Save(submission).Error
+	})
+	return review, err
+}
@@ -0,0 +1,426 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/models"
+	"SkillBridge/utils"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxSavedSearches caps how many saved searches one student can keep
+const maxSavedSearches = 20
+
+type savedSearchInput struct {
+	Name            string  `json:"name"`
+	Domain          string  `json:"domain"`
+	Category        string  `json:"category"`
+	Location        string  `json:"location"`
+	Skills          string  `json:"skills"`
+	MinStipend      int     `json:"min_stipend"`
+	IncludeJobs     *bool   `json:"include_jobs"`
+	IncludeProjects *bool   `json:"include_projects"`
+	Frequency       *string `json:"frequency"`
+}
+
+// apply copies the input onto a saved search, validating it
+func (input savedSearchInput) apply(search *models.SavedSearch) error {
+	search.Name = strings.TrimSpace(input.Name)
+	search.Domain = strings.TrimSpace(input.Domain)
+	search.Category = strings.TrimSpace(input.Category)
+	search.Location = strings.TrimSpace(input.Location)
+	search.Skills = strings.Join(utils.ParseSkills(input.Skills), ", ")
+	search.MinStipend = input.MinStipend
+	if input.IncludeJobs != nil {
+		search.IncludeJobs = *input.IncludeJobs
+	}
+	if input.IncludeProjects != nil {
+		search.IncludeProjects = *input.IncludeProjects
+	}
+	if input.Frequency != nil {
+		search.Frequency = *input.Frequency
+	}
+
+	if search.MinStipend < 0 {
+		return fmt.Errorf("min_stipend cannot be negative")
+	}
+	if !search.IncludeJobs && !search.IncludeProjects {
+		return fmt.Errorf("a saved search must include jobs, projects or both")
+	}
+	if search.Frequency != models.AlertInstant && search.Frequency != models.AlertDaily {
+		return fmt.Errorf("frequency must be %q or %q", models.AlertInstant, models.AlertDaily)
+	}
+	if search.Name == "" {
+		search.Name = describeSearch(*search)
+	}
+	return nil
+}
+
+// describeSearch names a saved search after its filters, e.g. "Engineering Internship in Pune"
+func describeSearch(search models.SavedSearch) string {
+	var parts []string
+	for _, p := range []string{search.Skills, search.Domain, search.Category} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	name := strings.Join(parts, " ")
+	if name == "" {
+		name = "All postings"
+	}
+	if search.Location != "" {
+		name += " in " + search.Location
+	}
+	if len(name) > 120 {
+		name = name[:120]
+	}
+	return name
+}
+
+// wantsSkills reports whether a posting's skills include any the search asks for
+func wantsSkills(search models.SavedSearch, postingSkills []string) bool {
+	wanted := utils.ParseSkills(search.Skills)
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, w := range wanted {
+		for _, s := range postingSkills {
+			if strings.EqualFold(strings.TrimSpace(s), w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// savedSearchJobs finds active job listings matching a search, created after since (zero for all)
+func savedSearchJobs(search models.SavedSearch, since, until time.Time) ([]models.JobListing, error) {
+	query := DB.Preload("Company", selectPublicUser).Where("is_active = ? AND application_deadline > ?", true, time.Now())
+	if !since.IsZero() {
+		query = query.Where("created_at > ? AND created_at <= ?", since, until)
+	}
+	if search.Domain != "" {
+		query = query.Where("domain = ?", search.Domain)
+	}
+	if search.Category != "" {
+		query = query.Where("category = ?", search.Category)
+	}
+	if search.Location != "" {
+		query = query.Where("location LIKE ?", "%"+search.Location+"%")
+	}
+	if search.MinStipend > 0 {
+		query = query.Where("stipend >= ?", search.MinStipend)
+	}
+
+	var jobs []models.JobListing
+	if err := query.Order("created_at DESC").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	matched := jobs[:0]
+	for _, job := range jobs {
+		if wantsSkills(search, jsonStrings(job.Skills)) {
+			matched = append(matched, job)
+		}
+	}
+	return matched, nil
+}
+
+// savedSearchProjects finds open projects matching a search. Projects have no
+// domain, category or stipend, so only location and skills apply to them.
+func savedSearchProjects(search models.SavedSearch, since, until time.Time) ([]models.Project, error) {
+	query := DB.Where("deadline > ?", time.Now())
+	if !since.IsZero() {
+		query = query.Where("created_at > ? AND created_at <= ?", since, until)
+	}
+	if search.Location != "" {
+		query = query.Where("location LIKE ?", "%"+search.Location+"%")
+	}
+
+	var projects []models.Project
+	if err := query.Order("created_at DESC").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	matched := projects[:0]
+	for _, project := range projects {
+		if wantsSkills(search, utils.ParseSkills(project.Skills)) {
+			matched = append(matched, project)
+		}
+	}
+	return matched, nil
+}
+
+// CreateSavedSearch - Student saves a search to be alerted about new matching postings
+func CreateSavedSearch(c *gin.Context) {
+	studentID := c.GetUint("userID")
+
+	var input savedSearchInput
+	if !bindJSON(c, &input) {
+		return
+	}
+
+	var count int64
+	DB.Model(&models.SavedSearch{}).Where("user_id = ?", studentID).Count(&count)
+	if count >= maxSavedSearches {
+		apierror.Abort(c, apierror.BadRequest(fmt.Sprintf("You can keep at most %d saved searches", maxSavedSearches)))
+		return
+	}
+
+	// Only postings created from now on trigger alerts
+	search := models.SavedSearch{
+		UserID:          studentID,
+		IncludeJobs:     true,
+		IncludeProjects: true,
+		Frequency:       models.AlertInstant,
+		LastCheckedAt:   time.Now(),
+	}
+	if err := input.apply(&search); err != nil {
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
+		return
+	}
+	if err := DB.Create(&search).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to save search"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, savedSearchResponse{
+		Message:     "Search saved. You'll be notified about new matches.",
+		SavedSearch: search,
+	})
+}
+
+type savedSearchResponse struct {
+	Message     string             `json:"message"`
+	SavedSearch models.SavedSearch `json:"saved_search"`
+}
+
+type savedSearchesResponse struct {
+	SavedSearches []models.SavedSearch `json:"saved_searches"`
+	Count         int                  `json:"count"`
+}
+
+// GetSavedSearches - Student lists their saved searches
+func GetSavedSearches(c *gin.Context) {
+	var searches []models.SavedSearch
+	if err := DB.Where("user_id = ?", c.GetUint("userID")).Order("created_at DESC").Find(&searches).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch saved searches"))
+		return
+	}
+
+	c.JSON(http.StatusOK, savedSearchesResponse{
+		SavedSearches: searches,
+		Count:         len(searches),
+	})
+}
+
+// UpdateSavedSearch - Student changes a saved search's filters or alert frequency
+func UpdateSavedSearch(c *gin.Context) {
+	var search models.SavedSearch
+	if err := DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("userID")).First(&search).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Saved search not found"))
+		return
+	}
+
+	var input savedSearchInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if err := input.apply(&search); err != nil {
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
+		return
+	}
+	if err := DB.Save(&search).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to update saved search"))
+		return
+	}
+
+	c.JSON(http.StatusOK, savedSearchResponse{
+		Message:     "Saved search updated successfully",
+		SavedSearch: search,
+	})
+}
+
+// DeleteSavedSearch - Student stops alerts for a saved search
+func DeleteSavedSearch(c *gin.Context) {
+	result := DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("userID")).Delete(&models.SavedSearch{})
+	if result.Error != nil {
+		apierror.Abort(c, apierror.Internal("Failed to delete saved search"))
+		return
+	}
+	if result.RowsAffected == 0 {
+		apierror.Abort(c, apierror.NotFound("Saved search not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Saved search deleted successfully"})
+}
+
+// RunSavedSearch - Student runs a saved search now against all open postings
+func RunSavedSearch(c *gin.Context) {
+	var search models.SavedSearch
+	if err := DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("userID")).First(&search).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Saved search not found"))
+		return
+	}
+
+	jobs := []models.JobListing{}
+	projects := []models.Project{}
+	var err error
+	if search.IncludeJobs {
+		if jobs, err = savedSearchJobs(search, time.Time{}, time.Time{}); err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to run search"))
+			return
+		}
+	}
+	if search.IncludeProjects {
+		if projects, err = savedSearchProjects(search, time.Time{}, time.Time{}); err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to run search"))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, savedSearchResultsResponse{
+		SavedSearch: search,
+		Jobs:        jobs,
+		Projects:    projects,
+	})
+}
+
+type savedSearchResultsResponse struct {
+	SavedSearch models.SavedSearch  `json:"saved_search"`
+	Jobs        []models.JobListing `json:"jobs"`
+	Projects    []models.Project    `json:"projects"`
+}
+
+type savedSearchMatchesResponse struct {
+	SavedSearch models.SavedSearch        `json:"saved_search"`
+	Matches     []models.SavedSearchMatch `json:"matches"`
+}
+
+// GetSavedSearchMatches - Student lists postings their saved search has alerted them about
+func GetSavedSearchMatches(c *gin.Context) {
+	var search models.SavedSearch
+	if err := DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetUint("userID")).First(&search).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Saved search not found"))
+		return
+	}
+
+	var matches []models.SavedSearchMatch
+	if err := DB.Where("saved_search_id = ?", search.ID).Order("created_at DESC").Limit(100).Find(&matches).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch matches"))
+		return
+	}
+
+	c.JSON(http.StatusOK, savedSearchMatchesResponse{
+		SavedSearch: search,
+		Matches:     matches,
+	})
+}
+
+// ProcessSavedSearches finds postings created since each saved search was last
+// checked, records them, and notifies students: straight away for instant
+// alerts, or in one digest a day. It runs as a recurring background job.
+func ProcessSavedSearches() error {
+	var searches []models.SavedSearch
+	if err := DB.Find(&searches).Error; err != nil {
+		return fmt.Errorf("loading saved searches: %w", err)
+	}
+
+	for _, search := range searches {
+		now := time.Now()
+		var found []models.SavedSearchMatch
+		if search.IncludeJobs {
+			jobs, err := savedSearchJobs(search, search.LastCheckedAt, now)
+			if err != nil {
+				slog.Error("saved search: failed to query jobs", "search_id", search.ID, "error", err)
+				continue
+			}
+			for _, job := range jobs {
+				title := job.Title
+				if job.Company.CompanyName != "" {
+					title += " at " + job.Company.CompanyName
+				}
+				found = append(found, models.SavedSearchMatch{SavedSearchID: search.ID, Kind: "job", ItemID: job.ID, Title: title})
+			}
+		}
+		if search.IncludeProjects {
+			projects, err := savedSearchProjects(search, search.LastCheckedAt, now)
+			if err != nil {
+				slog.Error("saved search: failed to query projects", "search_id", search.ID, "error", err)
+				continue
+			}
+			for _, project := range projects {
+				found = append(found, models.SavedSearchMatch{SavedSearchID: search.ID, Kind: "project", ItemID: project.ID, Title: project.Title})
+			}
+		}
+
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			if len(found) > 0 {
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&found).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Model(&search).UpdateColumn("last_checked_at", now).Error
+		})
+		if err != nil {
+			slog.Error("saved search: failed to record matches", "search_id", search.ID, "error", err)
+			continue
+		}
+
+		if search.Frequency == models.AlertDaily {
+			if search.LastDigestAt == nil || now.Sub(*search.LastDigestAt) >= 24*time.Hour {
+				sendSavedSearchAlert(search, now, true)
+			}
+			continue
+		}
+		sendSavedSearchAlert(search, now, false)
+	}
+	return nil
+}
+
+// sendSavedSearchAlert notifies the student about a search's unannounced matches.
+// Instant alerts name a single match; several matches, or a digest, are summarised.
+func sendSavedSearchAlert(search models.SavedSearch, now time.Time, digest bool) {
+	var pending []models.SavedSearchMatch
+	DB.Where("saved_search_id = ? AND notified_at IS NULL", search.ID).Order("created_at ASC").Find(&pending)
+
+	if digest {
+		DB.Model(&search).UpdateColumn("last_digest_at", now)
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	var message string
+	if len(pending) == 1 {
+		message = fmt.Sprintf("New %s matching your saved search \"%s\": %s", pending[0].Kind, search.Name, pending[0].Title)
+	} else {
+		titles := make([]string, 0, 3)
+		for _, m := range pending {
+			if len(titles) == 3 {
+				break
+			}
+			titles = append(titles, m.Title)
+		}
+		message = fmt.Sprintf("%d new postings match your saved search \"%s\": %s", len(pending), search.Name, strings.Join(titles, "; "))
+		if len(pending) > len(titles) {
+			message += fmt.Sprintf(" and %d more", len(pending)-len(titles))
+		}
+	}
+
+	if err := utils.CreateNotification(DB, search.UserID, message); err != nil {
+		slog.Error("failed to send notification", "error", err)
+		return
+	}
+	ids := make([]uint, len(pending))
+	for i, m := range pending {
+		ids[i] = m.ID
+	}
+	DB.Model(&models.SavedSearchMatch{}).Where("id IN ?", ids).UpdateColumn("notified_at", now)
+}
@@ -1,10 +1,13 @@
 package controller
 
 import (
+	"SkillBridge/apierror"
 	"SkillBridge/models"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+
+	"gorm.io/gorm"
 )
 
 func GetAllGuides(c *gin.Context) {
@@ -12,60 +15,72 @@ func GetAllGuides(c *gin.Context) {
 	
 	// Get all users with role "guide"
 	if err := DB.Where("role = ?", "guide").Find(&guides).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch guides"})
+		apierror.Abort(c, apierror.Internal("Failed to fetch guides"))
 		return
 	}
 
 	// Return only public fields for guides
-	var publicGuides []gin.H
+	var publicGuides []publicGuide
 	for _, guide := range guides {
-		publicGuides = append(publicGuides, gin.H{
-			"id":           guide.ID,
-			"name":         guide.Name,
-			"bio":          guide.Bio,
-			"picture":      guide.Picture,
-			"github_url":   guide.GithubURL,
-			"linkedin":     guide.LinkedIn,
-			"university":   guide.University,
-			"major":        guide.Major,
-			"year":         guide.Year,
-			"position":     guide.Position,
+		publicGuides = append(publicGuides, publicGuide{
+			ID:         guide.ID,
+			Name:       guide.Name,
+			Bio:        guide.Bio,
+			Picture:    guide.Picture,
+			GithubURL:  guide.GithubURL,
+			LinkedIn:   guide.LinkedIn,
+			University: guide.University,
+			Major:      guide.Major,
+			Year:       guide.Year,
+			Position:   guide.Position,
 		})
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"guides": publicGuides,
-		"count":  len(publicGuides),
+	c.JSON(http.StatusOK, guidesResponse{
+		Guides: publicGuides,
+		Count:  len(publicGuides),
 	})
 }
 
+type guidesResponse struct {
+	Guides []publicGuide `json:"guides"`
+	Count  int           `json:"count"`
+}
+
+// publicGuide is the part of a guide's account shown in the guide directory
+type publicGuide struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	Bio        string `json:"bio"`
+	Picture    string `json:"picture"`
+	GithubURL  string `json:"github_url"`
+	LinkedIn   string `json:"linkedin"`
+	University string `json:"university"`
+	Major      string `json:"major"`
+	Year       string `json:"year"`
+	Position   string `json:"position"`
+}
+
 func GetPublicStudentProfile(c *gin.Context) {
 	idParam := c.Param("id")
 	userID, err := strconv.Atoi(idParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		apierror.Abort(c, apierror.BadRequest(err.Error()))
 		return
 	}
 
 	var user models.User
 	if err := DB.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found!"})
+		apierror.Abort(c, apierror.NotFound("Student not found!"))
 		return
 	}
 
 	if user.Role != "student" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User is not Student!"})
+		apierror.Abort(c, apierror.NotFound("Student not found!"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"id":           userID,
-		"name":         user.Name,
-		"email":        user.Email,
-		"bio":          user.Bio,
-		"github_url":   user.GithubURL,
-		"linkedIn_url": user.LinkedIn,
-	})
+	c.JSON(http.StatusOK, buildPortfolio(c, user))
 }
 
 func GetPublicCompanyProfile(c *gin.Context) {
@@ -73,15 +88,47 @@ func GetPublicCompanyProfile(c *gin.Context) {
 
 	var company models.User
 	if err := DB.Where("id = ? AND role = ?", companyID, "company").First(&company).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		apierror.Abort(c, apierror.NotFound("Company not found"))
 		return
 	}
 
 	// Return only public fields
-	c.JSON(http.StatusOK, gin.H{
-		"id":    company.ID,
-		"name":  company.Name,
-		"email": company.Email,
-		"bio":   company.Bio,
-	})
+	profile := publicCompanyProfile{
+		ID:    company.ID,
+		Name:  company.Name,
+		Email: company.Email,
+		Bio:   company.Bio,
+	}
+
+	// Recruiters belonging to an organisation show its profile alongside their own
+	if member, ok := organisationMembership(company.ID); ok {
+		var organisation models.Organisation
+		if err := DB.First(&organisation, member.OrganisationID).Error; err == nil {
+			public := publicOrganisationProfile(organisation)
+			profile.Organisation = &public
+			profile.OrganisationRole = member.Role
+		}
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// publicCompanyProfile is a company account's public profile. Organisation is
+// set for members of an organisation.
+type publicCompanyProfile struct {
+	ID               uint                `json:"id"`
+	Name             string              `json:"name"`
+	Email            string              `json:"email"`
+	Bio              string              `json:"bio"`
+	Organisation     *publicOrganisation `json:"organisation,omitempty"`
+	OrganisationRole string              `json:"organisation_role,omitempty"`
+}
+
+// publicUserColumns are the user fields that may be shown to other users
+var publicUserColumns = []string{"id", "name", "role", "bio", "picture", "github_url", "linked_in", "university", "major", "year", "company_name", "position", "portfolio_url", "skills"}
+
+// selectPublicUser is a Preload condition that loads only publicUserColumns,
+// so related users never carry password hashes, emails or phone numbers
+func selectPublicUser(db *gorm.DB) *gorm.DB {
+	return db.Select(publicUserColumns)
 }
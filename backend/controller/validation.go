@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report fields by their JSON names rather than Go field names
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+		v.RegisterValidation("httpurl", func(fl validator.FieldLevel) bool {
+			return isHTTPURL(fl.Field().String())
+		})
+	}
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL. Use the
+// "httpurl" binding tag for request fields.
+func isHTTPURL(s string) bool {
+	parsed, err := url.Parse(s)
+	return err == nil && (parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != ""
+}
+
+// bindJSON decodes and validates the request body into obj. On failure it
+// aborts with a 400 listing the invalid fields and returns false.
+func bindJSON(c *gin.Context, obj interface{}) bool {
+	err := c.ShouldBindJSON(obj)
+	if err == nil {
+		return true
+	}
+	apierror.Abort(c, validationError(err))
+	return false
+}
+
+// validationError describes a binding error
+func validationError(err error) *apierror.Error {
+	var invalid validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	var tooLarge *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &tooLarge):
+		return middleware.BodyTooLarge(tooLarge.Limit)
+	case errors.As(err, &invalid):
+		fields := make([]models.FieldError, 0, len(invalid))
+		for _, fe := range invalid {
+			fields = append(fields, models.FieldError{
+				Field:   fieldPath(fe),
+				Rule:    fe.Tag(),
+				Message: fieldMessage(fe),
+			})
+		}
+		return apierror.Validation("Invalid request", fields)
+	case errors.As(err, &typeErr):
+		return apierror.Validation("Invalid request", []models.FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: "must be " + jsonKind(typeErr.Type),
+		}})
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return apierror.BadRequest("Request body is not valid JSON")
+	case errors.Is(err, io.EOF):
+		return apierror.BadRequest("Request body is required")
+	}
+	return apierror.BadRequest("Invalid request: " + err.Error())
+}
+
+// fieldPath drops the struct name from a field's namespace, e.g.
+// "setRubricInput.criteria[0].name" becomes "criteria[0].name"
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+	return path
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	countable := fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map || fe.Kind() == reflect.Array
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "httpurl":
+		return "must be an http(s) URL"
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min", "gte":
+		switch {
+		case fe.Kind() == reflect.String:
+			return fmt.Sprintf("must be at least %s characters", fe.Param())
+		case countable:
+			return fmt.Sprintf("must have at least %s items", fe.Param())
+		}
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		switch {
+		case fe.Kind() == reflect.String:
+			return fmt.Sprintf("must be at most %s characters", fe.Param())
+		case countable:
+			return fmt.Sprintf("must have at most %s items", fe.Param())
+		}
+		return "must be at most " + fe.Param()
+	}
+	return fmt.Sprintf("failed the %s rule", fe.Tag())
+}
+
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	}
+	return "an object"
+}
@@ -0,0 +1,588 @@
+package controller
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/models"
+	"SkillBridge/notify"
+	"SkillBridge/queue"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Event types companies can subscribe their webhooks to
+const (
+	webhookApplicationCreated = "application.created"
+	webhookApplicationStatus  = "application.status_changed"
+	webhookSubmissionCreated  = "submission.created"
+	webhookSubmissionReviewed = "submission.reviewed"
+	webhookPing               = "ping"
+)
+
+type webhookEvent struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+var webhookEvents = []webhookEvent{
+	{webhookApplicationCreated, "A candidate applied to one of your jobs"},
+	{webhookApplicationStatus, "A job application moved to another pipeline stage"},
+	{webhookSubmissionCreated, "A student submitted work for one of your projects"},
+	{webhookSubmissionReviewed, "A company member or guide reviewed a project submission"},
+}
+
+const (
+	jobDeliverWebhook     = "deliver_webhook"
+	webhookMaxAttempts    = 8
+	maxWebhooksPerCompany = 10
+)
+
+type webhookDeliveryPayload struct {
+	DeliveryID uint `json:"delivery_id"`
+}
+
+// webhookInput is the body for creating or updating an endpoint
+type webhookInput struct {
+	URL         *string  `json:"url"`
+	Description *string  `json:"description"`
+	Events      []string `json:"events"`
+	Active      *bool    `json:"active"`
+}
+
+func validWebhookEvent(name string) bool {
+	for _, e := range webhookEvents {
+		if e.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func newEventID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "evt_" + hex.EncodeToString(b)
+}
+
+// subscribed reports whether the endpoint wants the event
+func subscribed(endpoint models.WebhookEndpoint, event string) bool {
+	for _, e := range strings.Split(endpoint.Events, ",") {
+		if strings.TrimSpace(e) == event {
+			return true
+		}
+	}
+	return false
+}
+
+// emitWebhookEvent queues an event for every active endpoint of the listing's
+// owners that subscribes to it. An organisation's listing goes to the
+// organisation's endpoints and to the creator's own endpoints only while they
+// are still a member. Pass a transaction as db to send the event only if the
+// surrounding changes commit.
+func emitWebhookEvent(db *gorm.DB, companyID uint, organisationID *uint, event string, data interface{}) error {
+	query := db.Where("active = ?", true)
+	if organisationID != nil {
+		members := db.Model(&models.OrganisationMember{}).Select("user_id").Where("organisation_id = ?", *organisationID)
+		query = query.Where("(organisation_id = ? OR (organisation_id IS NULL AND company_id = ? AND company_id IN (?)))", *organisationID, companyID, members)
+	} else {
+		query = query.Where("company_id = ? AND organisation_id IS NULL", companyID)
+	}
+	var endpoints []models.WebhookEndpoint
+	if err := query.Find(&endpoints).Error; err != nil {
+		return err
+	}
+
+	eventID := newEventID()
+	var payload json.RawMessage
+	for _, endpoint := range endpoints {
+		if !subscribed(endpoint, event) {
+			continue
+		}
+		if payload == nil {
+			var err error
+			payload, err = json.Marshal(gin.H{
+				"id":         eventID,
+				"type":       event,
+				"created_at": time.Now().UTC(),
+				"data":       data,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		delivery := models.WebhookDelivery{EndpointID: endpoint.ID, EventID: eventID, Event: event, Payload: payload, Status: models.DeliveryPending}
+		if err := queueWebhookDelivery(db, &delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emitJobWebhook sends an event to the webhooks of the company that owns the job
+func emitJobWebhook(db *gorm.DB, job models.JobListing, event string, data interface{}) {
+	if err := emitWebhookEvent(db, job.CompanyID, job.OrganisationID, event, data); err != nil {
+		slog.Error("failed to queue webhook", "event", event, "error", err)
+	}
+}
+
+// emitProjectWebhook sends an event to the webhooks of the company that owns the project
+func emitProjectWebhook(db *gorm.DB, project models.Project, event string, data interface{}) {
+	if err := emitWebhookEvent(db, project.CompanyID, project.OrganisationID, event, data); err != nil {
+		slog.Error("failed to queue webhook", "event", event, "error", err)
+	}
+}
+
+func queueWebhookDelivery(db *gorm.DB, delivery *models.WebhookDelivery) error {
+	if err := db.Create(delivery).Error; err != nil {
+		return err
+	}
+	_, err := queue.Enqueue(db, jobDeliverWebhook, webhookDeliveryPayload{DeliveryID: delivery.ID})
+	return err
+}
+
+// applicationWebhookData is the data of application events
+func applicationWebhookData(application models.JobApplication, applicant models.User) gin.H {
+	return gin.H{
+		"application_id":    application.ID,
+		"job_id":            application.JobListingID,
+		"status":            application.Status,
+		"applied_at":        application.AppliedAt,
+		"resume_file_id":    application.ResumeFileID,
+		"resume_version_id": application.ResumeVersionID,
+		"applicant": gin.H{
+			"id":    applicant.ID,
+			"name":  applicant.Name,
+			"email": applicant.Email,
+		},
+	}
+}
+
+// submissionWebhookData is the data of submission events
+func submissionWebhookData(submission models.Submission) gin.H {
+	return gin.H{
+		"submission_id":  submission.ID,
+		"project_id":     submission.ProjectID,
+		"project_title":  submission.Project.Title,
+		"student_id":     submission.StudentID,
+		"github_url":     submission.GithubURL,
+		"demo_url":       submission.DemoURL,
+		"status":         submission.Status,
+		"feedback":       submission.Feedback,
+		"review_status":  submission.ReviewStatus,
+		"review_comment": submission.ReviewComment,
+		"submitted_at":   submission.SubmittedAt,
+	}
+}
+
+// attemptWebhookDelivery posts a delivery and records the outcome on it. A
+// failed last attempt marks the delivery failed; otherwise it stays pending.
+func attemptWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery, endpoint models.WebhookEndpoint, last bool) error {
+	result, err := notify.PostWebhook(ctx, endpoint.URL, endpoint.Secret, map[string]string{
+		"X-SkillBridge-Event":    delivery.Event,
+		"X-SkillBridge-Delivery": strconv.FormatUint(uint64(delivery.ID), 10),
+	}, delivery.Payload)
+
+	delivery.Attempts++
+	delivery.ResponseStatus = result.StatusCode
+	delivery.ResponseBody = result.Body
+	delivery.DurationMS = result.Duration.Milliseconds()
+	switch {
+	case err == nil:
+		now := time.Now()
+		delivery.Status = models.DeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.Error = ""
+	case last || queue.IsPermanent(err):
+		delivery.Status = models.DeliveryFailed
+		delivery.Error = err.Error()
+	default:
+		delivery.Error = err.Error()
+	}
+	if saveErr := DB.Save(delivery).Error; saveErr != nil {
+		slog.Error("failed to record webhook delivery", "delivery_id", delivery.ID, "error", saveErr)
+	}
+	return err
+}
+
+// deliverWebhook is the background job that sends one delivery
+func deliverWebhook(ctx context.Context, payload json.RawMessage) error {
+	var p webhookDeliveryPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return queue.Permanent(err)
+	}
+	var delivery models.WebhookDelivery
+	if err := DB.First(&delivery, p.DeliveryID).Error; err != nil {
+		return queue.Permanent(err)
+	}
+	if delivery.Status != models.DeliveryPending {
+		return nil
+	}
+
+	var endpoint models.WebhookEndpoint
+	if err := DB.First(&endpoint, delivery.EndpointID).Error; err != nil || !endpoint.Active {
+		delivery.Status = models.DeliveryFailed
+		delivery.Error = "endpoint was disabled or deleted"
+		DB.Save(&delivery)
+		return nil
+	}
+	return attemptWebhookDelivery(ctx, &delivery, endpoint, delivery.Attempts+1 >= webhookMaxAttempts)
+}
+
+// loadOwnWebhook fetches an endpoint owned by the caller's company or organisation
+func loadOwnWebhook(c *gin.Context) (models.WebhookEndpoint, bool) {
+	var endpoint models.WebhookEndpoint
+	err := DB.Scopes(ownedByCompany("webhook_endpoints", c.GetUint("userID"))).Where("id = ?", c.Param("id")).First(&endpoint).Error
+	if err != nil {
+		apierror.Abort(c, apierror.NotFound("Webhook not found"))
+		return endpoint, false
+	}
+	return endpoint, true
+}
+
+// validateWebhookInput checks the URL and event list, returning an error message
+func validateWebhookInput(input webhookInput) string {
+	if input.URL != nil {
+		parsed, err := url.Parse(*input.URL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return "url must be an http(s) URL"
+		}
+	}
+	if input.Events != nil && len(input.Events) == 0 {
+		return "Subscribe to at least one event"
+	}
+	for _, event := range input.Events {
+		if !validWebhookEvent(event) {
+			return "Unknown event: " + event
+		}
+	}
+	return ""
+}
+
+// GetWebhookEvents - Company lists the event types webhooks can subscribe to
+func GetWebhookEvents(c *gin.Context) {
+	c.JSON(http.StatusOK, webhookEventsResponse{Events: webhookEvents})
+}
+
+type webhookEventsResponse struct {
+	Events []webhookEvent `json:"events"`
+}
+
+// webhookSecretResponse carries a signing secret, which is only ever shown once
+type webhookSecretResponse struct {
+	Message string                  `json:"message"`
+	Webhook *models.WebhookEndpoint `json:"webhook,omitempty"`
+	Secret  string                  `json:"secret"`
+}
+
+type webhooksResponse struct {
+	Webhooks []models.WebhookEndpoint `json:"webhooks"`
+}
+
+// CreateWebhook - Company registers an endpoint; the signing secret is only shown in this response
+func CreateWebhook(c *gin.Context) {
+	userID := c.GetUint("userID")
+
+	var input webhookInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if input.URL == nil || input.Events == nil {
+		apierror.Abort(c, apierror.BadRequest("url and events are required"))
+		return
+	}
+	if msg := validateWebhookInput(input); msg != "" {
+		apierror.Abort(c, apierror.BadRequest(msg))
+		return
+	}
+
+	var count int64
+	DB.Model(&models.WebhookEndpoint{}).Scopes(ownedByCompany("webhook_endpoints", userID)).Count(&count)
+	if count >= maxWebhooksPerCompany {
+		apierror.Abort(c, apierror.BadRequest("You can register at most "+strconv.Itoa(maxWebhooksPerCompany)+" webhooks"))
+		return
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to create webhook"))
+		return
+	}
+	endpoint := models.WebhookEndpoint{
+		CompanyID:      userID,
+		OrganisationID: organisationIDFor(userID),
+		URL:            *input.URL,
+		Events:         strings.Join(input.Events, ","),
+		Secret:         secret,
+		Active:         true,
+	}
+	if input.Description != nil {
+		endpoint.Description = *input.Description
+	}
+	if err := DB.Create(&endpoint).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to create webhook"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhookSecretResponse{
+		Message: "Webhook created. Store the secret now; it is not shown again.",
+		Webhook: &endpoint,
+		Secret:  secret,
+	})
+}
+
+// GetWebhooks - Company lists its webhook endpoints
+func GetWebhooks(c *gin.Context) {
+	var endpoints []models.WebhookEndpoint
+	if err := DB.Scopes(ownedByCompany("webhook_endpoints", c.GetUint("userID"))).Order("id ASC").Find(&endpoints).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch webhooks"))
+		return
+	}
+	c.JSON(http.StatusOK, webhooksResponse{Webhooks: endpoints})
+}
+
+// GetWebhook - Company views one endpoint
+func GetWebhook(c *gin.Context) {
+	endpoint, ok := loadOwnWebhook(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// UpdateWebhook - Company changes an endpoint's URL, events, description or enables/disables it
+func UpdateWebhook(c *gin.Context) {
+	endpoint, ok := loadOwnWebhook(c)
+	if !ok {
+		return
+	}
+
+	var input webhookInput
+	if !bindJSON(c, &input) {
+		return
+	}
+	if msg := validateWebhookInput(input); msg != "" {
+		apierror.Abort(c, apierror.BadRequest(msg))
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if input.URL != nil {
+		updates["url"] = *input.URL
+	}
+	if input.Description != nil {
+		updates["description"] = *input.Description
+	}
+	if input.Events != nil {
+		updates["events"] = strings.Join(input.Events, ",")
+	}
+	if input.Active != nil {
+		updates["active"] = *input.Active
+	}
+	if len(updates) > 0 {
+		if err := DB.Model(&endpoint).Updates(updates).Error; err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to update webhook"))
+			return
+		}
+	}
+	DB.First(&endpoint, endpoint.ID)
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// RotateWebhookSecret - Company replaces an endpoint's signing secret
+func RotateWebhookSecret(c *gin.Context) {
+	endpoint, ok := loadOwnWebhook(c)
+	if !ok {
+		return
+	}
+	secret, err := newWebhookSecret()
+	if err == nil {
+		err = DB.Model(&endpoint).Update("secret", secret).Error
+	}
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to rotate secret"))
+		return
+	}
+	c.JSON(http.StatusOK, webhookSecretResponse{
+		Message: "Secret rotated. Store it now; it is not shown again.",
+		Secret:  secret,
+	})
+}
+
+// DeleteWebhook - Company removes an endpoint; pending deliveries to it are dropped
+func DeleteWebhook(c *gin.Context) {
+	endpoint, ok := loadOwnWebhook(c)
+	if !ok {
+		return
+	}
+	if err := DB.Delete(&endpoint).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to delete webhook"))
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Webhook deleted"})
+}
+
+// GetWebhookDeliveries - Company views the delivery log of an endpoint, newest first
+func GetWebhookDeliveries(c *gin.Context) {
+	endpoint, ok := loadOwnWebhook(c)
+	if !ok {
+		return
+	}
+
+	query := DB.Model(&models.WebhookDelivery{}).Where("endpoint_id = ?", endpoint.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if event := c.Query("event"); event != "" {
+		query = query.Where("event = ?", event)
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	var total int64
+	query.Count(&total)
+	var deliveries []models.WebhookDelivery
+	// The log leaves out payloads and response bodies; fetch one delivery for those
+	if err := query.Omit("payload", "response_body").Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&deliveries).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to fetch deliveries"))
+		return
+	}
+	c.JSON(http.StatusOK, deliveriesResponse{
+		Deliveries: deliveries,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+	})
+}
+
+type deliveriesResponse struct {
+	Deliveries []models.WebhookDelivery `json:"deliveries"`
+	Total      int64                    `json:"total"`
+	Page       int                      `json:"page"`
+	Limit      int                      `json:"limit"`
+}
+
+type redeliverResponse struct {
+	Message  string                 `json:"message"`
+	Delivery models.WebhookDelivery `json:"delivery"`
+}
+
+// pingResponse reports the outcome of a test delivery
+type pingResponse struct {
+	Success        bool                   `json:"success"`
+	Delivery       models.WebhookDelivery `json:"delivery"`
+	ResponseStatus int                    `json:"response_status"`
+	DurationMS     int64                  `json:"duration_ms"`
+	Error          string                 `json:"error,omitempty"`
+}
+
+// loadOwnDelivery fetches a delivery of an endpoint the caller owns
+func loadOwnDelivery(c *gin.Context) (models.WebhookDelivery, models.WebhookEndpoint, bool) {
+	var delivery models.WebhookDelivery
+	endpoint, ok := loadOwnWebhook(c)
+	if !ok {
+		return delivery, endpoint, false
+	}
+	if err := DB.Where("id = ? AND endpoint_id = ?", c.Param("delivery_id"), endpoint.ID).First(&delivery).Error; err != nil {
+		apierror.Abort(c, apierror.NotFound("Delivery not found"))
+		return delivery, endpoint, false
+	}
+	return delivery, endpoint, true
+}
+
+// GetWebhookDelivery - Company views one delivery with its payload and the endpoint's response
+func GetWebhookDelivery(c *gin.Context) {
+	delivery, _, ok := loadOwnDelivery(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// RedeliverWebhook - Company replays a delivery. The payload and event ID are
+// unchanged so the receiver can tell it is a repeat.
+func RedeliverWebhook(c *gin.Context) {
+	original, endpoint, ok := loadOwnDelivery(c)
+	if !ok {
+		return
+	}
+	if !endpoint.Active {
+		apierror.Abort(c, apierror.BadRequest("Enable the webhook before redelivering"))
+		return
+	}
+
+	delivery := models.WebhookDelivery{
+		EndpointID:   endpoint.ID,
+		EventID:      original.EventID,
+		Event:        original.Event,
+		Payload:      original.Payload,
+		Status:       models.DeliveryPending,
+		RedeliveryOf: &original.ID,
+	}
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		return queueWebhookDelivery(tx, &delivery)
+	})
+	if err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to queue redelivery"))
+		return
+	}
+	c.JSON(http.StatusAccepted, redeliverResponse{Message: "Redelivery queued", Delivery: delivery})
+}
+
+// PingWebhook - Company sends a test event to an endpoint and sees the result straight away
+func PingWebhook(c *gin.Context) {
+	endpoint, ok := loadOwnWebhook(c)
+	if !ok {
+		return
+	}
+
+	eventID := newEventID()
+	payload, _ := json.Marshal(gin.H{
+		"id":         eventID,
+		"type":       webhookPing,
+		"created_at": time.Now().UTC(),
+		"data":       gin.H{"webhook_id": endpoint.ID, "message": "Hello from SkillBridge"},
+	})
+	delivery := models.WebhookDelivery{EndpointID: endpoint.ID, EventID: eventID, Event: webhookPing, Payload: payload, Status: models.DeliveryPending}
+	if err := DB.Create(&delivery).Error; err != nil {
+		apierror.Abort(c, apierror.Internal("Failed to send ping"))
+		return
+	}
+
+	// Pings are not retried; the caller sees the outcome and can fix the endpoint
+	err := attemptWebhookDelivery(c.Request.Context(), &delivery, endpoint, true)
+
+	response := pingResponse{
+		Success:        err == nil,
+		Delivery:       delivery,
+		ResponseStatus: delivery.ResponseStatus,
+		DurationMS:     delivery.DurationMS,
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	c.JSON(http.StatusOK, response)
+}
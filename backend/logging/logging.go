@@ -0,0 +1,109 @@
+// Package logging configures the process-wide structured logger. Every record
+// passes through Redact, so credentials never reach the log output, even when
+// LOG_LEVEL is debug.
+package logging
+
+import (
+	"SkillBridge/config"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Options controls the logger built by New
+type Options struct {
+	Level  slog.Level
+	Format string // "json" or "text"
+}
+
+// OptionsFromEnv reads LOG_LEVEL (debug, info, warn, error) and LOG_FORMAT
+// (text, json). Unknown values fall back to info and text.
+func OptionsFromEnv() Options {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(config.GetEnv("LOG_LEVEL", "info"))); err != nil {
+		level = slog.LevelInfo
+	}
+	return Options{
+		Level:  level,
+		Format: strings.ToLower(config.GetEnv("LOG_FORMAT", "text")),
+	}
+}
+
+// New returns a logger writing to w that redacts secrets from every record
+func New(w io.Writer, opts Options) *slog.Logger {
+	handlerOpts := &slog.HandlerOptions{Level: opts.Level, ReplaceAttr: redactAttr}
+	if opts.Format == "json" {
+		return slog.New(slog.NewJSONHandler(w, handlerOpts))
+	}
+	return slog.New(slog.NewTextHandler(w, handlerOpts))
+}
+
+// Setup installs the logger configured from the environment as the default.
+// Output from the standard log package is routed through it at info level.
+func Setup() *slog.Logger {
+	logger := New(os.Stderr, OptionsFromEnv())
+	slog.SetDefault(logger)
+	log.SetFlags(0)
+	return logger
+}
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are attribute key fragments whose values are never logged
+var sensitiveKeys = []string{
+	"password", "passwd", "secret", "token", "authorization", "cookie",
+	"api_key", "apikey", "credential", "signature", "hash",
+}
+
+// secretPatterns catch credentials embedded in messages and string values
+var secretPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`), "$1 " + redacted},
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), redacted},
+	{regexp.MustCompile(`\b(sbk|whsec)_[A-Za-z0-9]+`), "${1}_" + redacted},
+	{regexp.MustCompile(`\$2[abxy]?\$\d{2}\$[./A-Za-z0-9]{53}`), redacted},
+	{regexp.MustCompile(`(?i)\b(password|passwd|secret|token|api_key|apikey)(["']?\s*[:=]\s*["']?)[^\s"'&,}]+`), "$1$2" + redacted},
+}
+
+// Redact masks credentials found in s
+func Redact(s string) string {
+	for _, p := range secretPatterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
+
+// SensitiveKey reports whether values logged under key must be hidden
+func SensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range sensitiveKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+		return a
+	}
+	if SensitiveKey(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(v.String()))
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
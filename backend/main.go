@@ -3,34 +3,107 @@ package main
 import (
 	"SkillBridge/config"
 	"SkillBridge/controller"
+	"SkillBridge/logging"
+	"SkillBridge/metrics"
+	"SkillBridge/middleware"
 	"SkillBridge/models"
+	"SkillBridge/notify"
+	"SkillBridge/queue"
 	"SkillBridge/router"
+	"SkillBridge/server"
+	"SkillBridge/storage"
+	"SkillBridge/utils"
+	"context"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 )
 
 func main() {
+	// LOG_LEVEL and LOG_FORMAT; secrets are redacted at every level
+	logging.Setup()
+
+	// Secrets have no usable defaults outside APP_ENV=development
+	if err := middleware.CheckJWTSecret(); err != nil {
+		panic("Failed to load the JWT secret: " + err.Error())
+	}
+	if err := utils.CheckCertificateKey(); err != nil {
+		panic("Failed to load the certificate signing key: " + err.Error())
+	}
+	if err := utils.CheckFileURLKey(); err != nil {
+		panic("Failed to load the download link secret: " + err.Error())
+	}
+	if err := notify.CheckUnsubscribeKey(); err != nil {
+		panic("Failed to load the unsubscribe link secret: " + err.Error())
+	}
+	if err := controller.CheckMetricsToken(); err != nil {
+		panic("Failed to load the metrics token: " + err.Error())
+	}
+
 	db := config.ConnectDB()
-	db.AutoMigrate(&models.User{})
-	db.AutoMigrate(&models.Project{})
-	db.AutoMigrate(&models.Application{})
-	db.AutoMigrate(&models.Submission{})
-	db.AutoMigrate(&models.Notification{})
-	db.AutoMigrate(&models.Chat{})
-	db.AutoMigrate(&models.GuideConnectionRequest{})
-	db.AutoMigrate(&models.JobListing{})
-	db.AutoMigrate(&models.JobApplication{})
-	db.AutoMigrate(&models.UserSavedJob{})
-	db.AutoMigrate(&models.InterviewResource{}) // Add this
+	for _, table := range models.Tables {
+		if err := db.AutoMigrate(table); err != nil {
+			// Readiness reports the table as pending until a migration succeeds
+			slog.Error("migration failed", "table", fmt.Sprintf("%T", table), "error", err)
+		}
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.RegisterDBStats(sqlDB)
+	}
 	controller.InitAuth(db)
 	controller.InitJobDB(db)
+
+	store, err := storage.FromEnv()
+	if err != nil {
+		panic("Failed to set up file storage: " + err.Error())
+	}
+	controller.InitStorage(store)
+
+	mailer, err := notify.MailerFromEnv()
+	if err != nil {
+		panic("Failed to set up email: " + err.Error())
+	}
+	notify.Init(db, mailer)
+
+	middleware.SetAPIKeyResolver(controller.ResolveAPIKey)
+
 	controller.SeedInterviewResources() // Seed data
+
+	// Deadline expiry, reminders, alerts and other background work
+	if err := controller.RegisterBackgroundJobs(); err != nil {
+		panic("Failed to register background jobs: " + err.Error())
+	}
+	workers, _ := strconv.Atoi(config.GetEnv("QUEUE_WORKERS", "4"))
+	queueCtx, stopQueue := context.WithCancel(context.Background())
+	runner := queue.Start(queueCtx, db, workers)
+
 	//setup router
 	r := router.SetupRouter()
 
-	// Use PORT environment variable if set, otherwise default to 8080
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// SIGINT or SIGTERM stops the server: in-flight requests are drained first,
+	// then the queue stops claiming jobs and lets running ones finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	cfg := server.ConfigFromEnv()
+	if err := server.Run(ctx, r, cfg); err != nil {
+		slog.Error("server stopped", "error", err)
+	}
+
+	stopQueue()
+	drained := make(chan struct{})
+	go func() {
+		runner.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		slog.Info("background workers stopped")
+	case <-time.After(cfg.ShutdownTimeout):
+		// Jobs cut off here are requeued by another server once their lock goes stale
+		slog.Warn("gave up waiting for background workers", "timeout", cfg.ShutdownTimeout)
 	}
-	r.Run(":" + port)
 }
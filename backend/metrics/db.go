@@ -0,0 +1,26 @@
+package metrics
+
+import "database/sql"
+
+// RegisterDBStats exposes the connection pool statistics of db
+func RegisterDBStats(db *sql.DB) {
+	stat := func(field func(sql.DBStats) float64) func() float64 {
+		return func() float64 { return field(db.Stats()) }
+	}
+	NewGaugeFunc("skillbridge_db_max_open_connections", "Maximum number of open connections to the database.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }))
+	NewGaugeFunc("skillbridge_db_open_connections", "Established connections to the database, in use and idle.",
+		stat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+	NewGaugeFunc("skillbridge_db_in_use_connections", "Connections currently in use.",
+		stat(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+	NewGaugeFunc("skillbridge_db_idle_connections", "Idle connections in the pool.",
+		stat(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+	NewCounterFunc("skillbridge_db_wait_count_total", "Connections waited for because the pool was exhausted.",
+		stat(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+	NewCounterFunc("skillbridge_db_wait_duration_seconds_total", "Time spent waiting for a connection.",
+		stat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	NewCounterFunc("skillbridge_db_max_idle_closed_total", "Connections closed because the idle pool was full.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }))
+	NewCounterFunc("skillbridge_db_max_lifetime_closed_total", "Connections closed because they reached their maximum lifetime.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }))
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	externalRequests = NewCounter("skillbridge_external_requests_total",
+		"Calls to external services, by service and HTTP status (error when no response arrived).", "service", "status")
+	externalDuration = NewHistogram("skillbridge_external_request_duration_seconds",
+		"Time taken by calls to external services.", DefBuckets, "service")
+)
+
+// Transport wraps base, or http.DefaultTransport when base is nil, so calls
+// made through it are counted and timed under service
+func Transport(service string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &instrumentedTransport{service: service, base: base}
+}
+
+type instrumentedTransport struct {
+	service string
+	base    http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	externalDuration.Observe(time.Since(start).Seconds(), t.service)
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	externalRequests.Inc(t.service, status)
+	return resp, err
+}
@@ -0,0 +1,270 @@
+// Package metrics keeps counters, gauges and histograms in memory and serves
+// them in the Prometheus text exposition format. Metrics are registered with
+// the Default registry when they are created, normally in package-level vars.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets suits request latencies in seconds
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Default is the registry served by Handler
+var Default = &Registry{}
+
+// Registry is a set of metrics with unique names
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+type metric interface {
+	describe() *desc
+	write(w io.Writer)
+}
+
+type desc struct {
+	name   string
+	help   string
+	kind   string // counter, gauge or histogram
+	labels []string
+}
+
+func (d *desc) describe() *desc { return d }
+
+// key joins label values into a series key, checking there is one per label
+func (d *desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", d.name, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// register adds m to the registry. Names must be unique.
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.metrics {
+		if existing.describe().name == m.describe().name {
+			panic("metrics: duplicate metric " + m.describe().name)
+		}
+	}
+	r.metrics = append(r.metrics, m)
+}
+
+// Write writes every metric in the text exposition format, sorted by name
+func (r *Registry) Write(w io.Writer) {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].describe().name < metrics[j].describe().name })
+
+	buf := bufio.NewWriter(w)
+	for _, m := range metrics {
+		d := m.describe()
+		fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", d.name, escapeHelp(d.help), d.name, d.kind)
+		m.write(buf)
+	}
+	buf.Flush()
+}
+
+// Handler serves the Default registry
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Default.Write(w)
+	})
+}
+
+// Counter is a value that only goes up, partitioned by labels
+type Counter struct {
+	desc
+	mu     sync.Mutex
+	series map[string]*sample
+}
+
+type sample struct {
+	labels []string
+	value  float64
+}
+
+// NewCounter registers a counter with the Default registry
+func NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{desc: desc{name: name, help: help, kind: "counter", labels: labels}, series: map[string]*sample{}}
+	Default.register(c)
+	return c
+}
+
+// Inc adds one to the series with the given label values
+func (c *Counter) Inc(labelValues ...string) { c.Add(1, labelValues...) }
+
+// Add adds v, which must not be negative, to the series with the given label values
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		panic("metrics: counter " + c.name + " cannot decrease")
+	}
+	key := c.key(labelValues)
+	c.mu.Lock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &sample{labels: append([]string(nil), labelValues...)}
+		c.series[key] = s
+	}
+	s.value += v
+	c.mu.Unlock()
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.labels) == 0 && len(c.series) == 0 {
+		fmt.Fprintf(w, "%s 0\n", c.name)
+		return
+	}
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		fmt.Fprintf(w, "%s%s %s\n", c.name, labelPairs(c.labels, s.labels, "", ""), formatValue(s.value))
+	}
+}
+
+// valueFunc is a metric whose single value is read at scrape time
+type valueFunc struct {
+	desc
+	fn func() float64
+}
+
+// NewGaugeFunc registers a gauge whose value is fn's result at scrape time
+func NewGaugeFunc(name, help string, fn func() float64) {
+	Default.register(&valueFunc{desc: desc{name: name, help: help, kind: "gauge"}, fn: fn})
+}
+
+// NewCounterFunc registers a counter kept elsewhere, such as a driver statistic
+func NewCounterFunc(name, help string, fn func() float64) {
+	Default.register(&valueFunc{desc: desc{name: name, help: help, kind: "counter"}, fn: fn})
+}
+
+func (g *valueFunc) write(w io.Writer) {
+	fmt.Fprintf(w, "%s %s\n", g.name, formatValue(g.fn()))
+}
+
+// Histogram counts observations into cumulative buckets, partitioned by labels
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labels []string
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram registers a histogram with the Default registry. buckets are
+// upper bounds in increasing order; +Inf is implied.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		desc:    desc{name: name, help: help, kind: "histogram", labels: labels},
+		buckets: append([]float64(nil), buckets...),
+		series:  map[string]*histogramSeries{},
+	}
+	Default.register(h)
+	return h
+}
+
+// Observe records v in the series with the given label values
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	key := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{labels: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, labelPairs(h.labels, s.labels, "le", formatValue(upper)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, labelPairs(h.labels, s.labels, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, labelPairs(h.labels, s.labels, "", ""), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, labelPairs(h.labels, s.labels, "", ""), s.count)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// labelPairs renders {name="value",...}, with an optional extra pair such as le
+func labelPairs(names, values []string, extraName, extraValue string) string {
+	if len(names) == 0 && extraName == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", name, escapeLabel(values[i]))
+	}
+	if extraName != "" {
+		if len(names) > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", extraName, escapeLabel(extraValue))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+func escapeHelp(s string) string { return helpEscaper.Replace(s) }
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/config"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const rawBodyKey = "rawBody"
+
+// BodyLimitFromEnv returns the limit in the environment variable key, or fallback bytes
+func BodyLimitFromEnv(key string, fallback int64) int64 {
+	limit, err := strconv.ParseInt(config.GetEnv(key, ""), 10, 64)
+	if err != nil || limit <= 0 {
+		return fallback
+	}
+	return limit
+}
+
+// BodyLimit caps the request body at limit bytes. Requests that declare a
+// larger Content-Length are rejected with a 413 straight away; others fail
+// with a 413 when the handler reads past the limit. A BodyLimit further down
+// the chain, e.g. on an upload route inside a limited group, replaces the
+// group's limit rather than adding to it.
+func BodyLimit(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, ok := c.Get(rawBodyKey)
+		if !ok {
+			body = c.Request.Body
+			c.Set(rawBodyKey, body)
+		}
+		if c.Request.ContentLength > limit {
+			apierror.Abort(c, BodyTooLarge(limit))
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, body.(io.ReadCloser), limit)
+		c.Next()
+	}
+}
+
+// BodyTooLarge is the error for a request body over limit bytes
+func BodyTooLarge(limit int64) *apierror.Error {
+	return apierror.PayloadTooLarge(fmt.Sprintf("Request body must be at most %s", formatBytes(limit)))
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return fmt.Sprintf("%d MB", n>>20)
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
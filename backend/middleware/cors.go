@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/config"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig decides which browser origins may call the API
+type CORSConfig struct {
+	// AllowedOrigins are exact origins such as "https://app.example.com" or
+	// wildcard subdomains such as "https://*.example.com". "*" allows every
+	// origin, but then credentials are never allowed.
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration // how long browsers may cache a preflight response
+}
+
+// CORSOverride applies a different config to the paths matching Path, where a
+// ":name" segment matches any one segment and a trailing "/*" matches the rest
+type CORSOverride struct {
+	Path   string
+	Config CORSConfig
+}
+
+// DefaultCORSConfig reads CORS_ALLOWED_ORIGINS (comma separated),
+// CORS_ALLOW_CREDENTIALS and CORS_MAX_AGE (seconds). The defaults allow the
+// local frontend dev servers.
+func DefaultCORSConfig() CORSConfig {
+	maxAge, err := strconv.Atoi(config.GetEnv("CORS_MAX_AGE", "600"))
+	if err != nil {
+		maxAge = 600
+	}
+	return CORSConfig{
+		AllowedOrigins:   splitList(config.GetEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173,http://127.0.0.1:5173,http://localhost:3000")),
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "X-API-Key", RequestIDHeader, "Accept", "Origin", "Cache-Control", "X-Requested-With"},
+		ExposedHeaders:   []string{RequestIDHeader, "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After", "Content-Disposition"},
+		AllowCredentials: config.GetEnv("CORS_ALLOW_CREDENTIALS", "true") == "true",
+		MaxAge:           time.Duration(maxAge) * time.Second,
+	}
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// CORS answers preflight requests and adds CORS headers for allowed origins.
+// It runs for every request, including unmatched OPTIONS requests, so
+// overrides are chosen by path rather than by route.
+func CORS(defaults CORSConfig, overrides ...CORSOverride) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := defaults
+		for _, o := range overrides {
+			if matchPath(o.Path, c.Request.URL.Path) {
+				cfg = o.Config
+				break
+			}
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if origin == "" {
+			c.Next()
+			return
+		}
+		allowOrigin, ok := cfg.allowOrigin(origin)
+		if !ok {
+			if preflight {
+				apierror.Abort(c, apierror.Forbidden("Origin not allowed"))
+				return
+			}
+			// Serve the request; without CORS headers the browser hides the response
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Set("Access-Control-Allow-Origin", allowOrigin)
+		if cfg.AllowCredentials && allowOrigin != "*" {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+		if !preflight {
+			if len(cfg.ExposedHeaders) > 0 {
+				h.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ", "))
+			}
+			c.Next()
+			return
+		}
+
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowedMethods, ", "))
+		h.Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+		if cfg.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for origin
+func (cfg CORSConfig) allowOrigin(origin string) (string, bool) {
+	for _, allowed := range cfg.AllowedOrigins {
+		switch {
+		case allowed == "*":
+			if cfg.AllowCredentials {
+				// Browsers reject "*" with credentials, and echoing any origin
+				// would let every site act as the user
+				continue
+			}
+			return "*", true
+		case strings.EqualFold(allowed, origin):
+			return origin, true
+		case matchWildcardOrigin(allowed, origin):
+			return origin, true
+		}
+	}
+	return "", false
+}
+
+// matchWildcardOrigin matches patterns like "https://*.example.com", which
+// cover subdomains at any depth but not example.com itself
+func matchWildcardOrigin(pattern, origin string) bool {
+	scheme, host, ok := strings.Cut(pattern, "://*.")
+	if !ok {
+		return false
+	}
+	prefix, suffix := scheme+"://", "."+host
+	origin = strings.ToLower(origin)
+	if !strings.HasPrefix(origin, strings.ToLower(prefix)) || !strings.HasSuffix(origin, strings.ToLower(suffix)) {
+		return false
+	}
+	sub := origin[len(prefix) : len(origin)-len(suffix)]
+	if sub == "" {
+		return false
+	}
+	for _, r := range sub {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// matchPath matches a request path against a pattern such as
+// "/api/certificates/:id/verify" or "/api/calendar/*"
+func matchPath(pattern, path string) bool {
+	if rest, ok := strings.CutSuffix(pattern, "/*"); ok {
+		patternParts := strings.Split(rest, "/")
+		pathParts := strings.Split(path, "/")
+		return len(pathParts) > len(patternParts) && matchSegments(patternParts, pathParts[:len(patternParts)])
+	}
+	patternParts := strings.Split(pattern, "/")
+	pathParts := strings.Split(path, "/")
+	return len(patternParts) == len(pathParts) && matchSegments(patternParts, pathParts)
+}
+
+func matchSegments(pattern, path []string) bool {
+	for i, segment := range pattern {
+		if strings.HasPrefix(segment, ":") {
+			if path[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != path[i] {
+			return false
+		}
+	}
+	return true
+}
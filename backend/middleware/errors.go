@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/models"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID to and from clients
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limits the IDs accepted from clients and proxies
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestID assigns each request an ID, reusing a well-formed X-Request-ID
+// from the client or a proxy, and echoes it in the response
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Set("requestID", id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Errors renders the error a handler aborted with via apierror.Abort. Errors
+// added with c.Error that are not *apierror.Error become a 500.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err, ok := apierror.From(c)
+		if !ok {
+			Log(c).Error("unhandled error", "error", c.Errors.Last().Err)
+			err = apierror.Internal("Internal server error")
+		}
+		RenderError(c, err)
+	}
+}
+
+// RenderError writes err as the response body
+func RenderError(c *gin.Context, err *apierror.Error) {
+	c.AbortWithStatusJSON(err.Status, models.ErrorResponse{
+		Code:      string(err.Code),
+		Message:   err.Message,
+		Details:   err.Details,
+		RequestID: c.GetString("requestID"),
+		Error:     err.Message,
+	})
+}
+
+// Recovery turns a panic in a handler into a 500, logging the stack with the request ID
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if recovered == http.ErrAbortHandler {
+					// The client went away; there is nobody to respond to
+					panic(recovered)
+				}
+				Log(c).Error("panic serving request", "method", c.Request.Method, "route", c.FullPath(),
+					"panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+				if !c.Writer.Written() {
+					RenderError(c, apierror.Internal("Internal server error"))
+				} else {
+					c.Abort()
+				}
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"SkillBridge/logging"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const loggerKey = "logger"
+
+// probeRoutes are polled by orchestrators and scrapers; successful calls are
+// only logged at debug level
+var probeRoutes = map[string]bool{"/health": true, "/health/live": true, "/health/ready": true, "/metrics": true}
+
+// RequestLogger gives each request a logger carrying its request ID and logs
+// one line per request once the handlers are done. It must run after RequestID.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		logger := slog.Default().With("request_id", c.GetString("requestID"))
+		c.Set(loggerKey, logger)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case probeRoutes[c.FullPath()]:
+			level = slog.LevelDebug
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", requestPath(c)),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if userID, ok := c.Get("userID"); ok {
+			attrs = append(attrs, slog.Any("user_id", userID))
+		}
+		if keyID, ok := c.Get("apiKeyID"); ok {
+			attrs = append(attrs, slog.Any("key_id", keyID))
+		}
+		logger.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// requestPath is the request path with sensitive route parameters, such as
+// calendar feed tokens, masked
+func requestPath(c *gin.Context) string {
+	path := c.Request.URL.Path
+	for _, p := range c.Params {
+		if logging.SensitiveKey(p.Key) && p.Value != "" {
+			path = strings.Replace(path, p.Value, "[REDACTED]", 1)
+		}
+	}
+	return path
+}
+
+// Log returns the request's logger, or the default logger outside a request
+func Log(c *gin.Context) *slog.Logger {
+	if logger, ok := c.Get(loggerKey); ok {
+		return logger.(*slog.Logger)
+	}
+	return slog.Default()
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"SkillBridge/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	httpRequests = metrics.NewCounter("skillbridge_http_requests_total",
+		"HTTP requests served, by method, route and status.", "method", "route", "status")
+	httpDuration = metrics.NewHistogram("skillbridge_http_request_duration_seconds",
+		"Time taken to serve HTTP requests, by method and route.", metrics.DefBuckets, "method", "route")
+)
+
+// Metrics counts and times requests per route. Requests that match no route
+// share the "unmatched" route so unknown paths cannot grow the series.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpDuration.Observe(time.Since(start).Seconds(), c.Request.Method, route)
+		httpRequests.Inc(c.Request.Method, route, strconv.Itoa(c.Writer.Status()))
+	}
+}
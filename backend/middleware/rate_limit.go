@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"SkillBridge/apierror"
+	"SkillBridge/config"
+	"SkillBridge/ratelimit"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitPolicy limits a route group. Requests are counted per client IP
+// and, on authenticated routes, per user; a zero Limit turns either off.
+type RateLimitPolicy struct {
+	Name    string
+	PerIP   ratelimit.Limit
+	PerUser ratelimit.Limit
+	Store   ratelimit.Store // nil uses the default store
+}
+
+// RateLimitPolicyFromEnv builds the policy for a group, letting
+// RATE_LIMIT_<NAME>_IP and RATE_LIMIT_<NAME>_USER (e.g. "20/m" or "off")
+// override the defaults
+func RateLimitPolicyFromEnv(name string, perIP, perUser ratelimit.Limit) RateLimitPolicy {
+	env := "RATE_LIMIT_" + strings.ToUpper(name)
+	return RateLimitPolicy{
+		Name:    name,
+		PerIP:   limitFromEnv(env+"_IP", perIP),
+		PerUser: limitFromEnv(env+"_USER", perUser),
+	}
+}
+
+func limitFromEnv(key string, fallback ratelimit.Limit) ratelimit.Limit {
+	value := config.GetEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	limit, err := ratelimit.ParseLimit(value)
+	if err != nil {
+		slog.Warn("invalid rate limit, using default", "setting", key, "error", err, "default", fallback.String())
+		return fallback
+	}
+	return limit
+}
+
+// RateLimit enforces p with token buckets and reports the tightest bucket in
+// the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers. Place
+// it after AuthMiddleware for per-user limits to apply. If the store fails,
+// requests are let through.
+func RateLimit(p RateLimitPolicy) gin.HandlerFunc {
+	var policies []string
+	for _, limit := range []ratelimit.Limit{p.PerIP, p.PerUser} {
+		if !limit.Unlimited() {
+			policies = append(policies, fmt.Sprintf("%d;w=%d", limit.Burst, int(limit.Window().Seconds())))
+		}
+	}
+	policyHeader := strings.Join(policies, ", ")
+
+	return func(c *gin.Context) {
+		store := p.Store
+		if store == nil {
+			store = ratelimit.DefaultStore()
+		}
+		now := time.Now()
+		var tightest *ratelimit.Result
+		take := func(key string, limit ratelimit.Limit) bool {
+			if limit.Unlimited() {
+				return true
+			}
+			result, err := store.Take(c.Request.Context(), p.Name+":"+key, limit, now)
+			if err != nil {
+				Log(c).Error("rate limit store failed", "policy", p.Name, "error", err)
+				return true
+			}
+			if tightest == nil || !result.Allowed || result.Remaining < tightest.Remaining {
+				tightest = &result
+			}
+			return result.Allowed
+		}
+
+		allowed := take("ip:"+c.ClientIP(), p.PerIP)
+		if userID, ok := c.Get("userID"); ok && allowed {
+			allowed = take(fmt.Sprintf("user:%v", userID), p.PerUser)
+		}
+		if tightest == nil {
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", policyHeader)
+		c.Header("RateLimit-Limit", strconv.Itoa(tightest.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(tightest.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(tightest.Reset))
+		if !allowed {
+			Log(c).Info("rate limited", "policy", p.Name, "client_ip", c.ClientIP())
+			AbortRateLimited(c, tightest.RetryAfter, "Too many requests, please slow down")
+			return
+		}
+		c.Next()
+	}
+}
+
+// AbortRateLimited rejects the request with a 429 and a Retry-After header
+func AbortRateLimited(c *gin.Context, retryAfter time.Duration, message string) {
+	c.Header("Retry-After", ceilSeconds(retryAfter))
+	apierror.Abort(c, apierror.RateLimited(message).WithDetails(gin.H{"retry_after": int(math.Ceil(retryAfter.Seconds()))}))
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
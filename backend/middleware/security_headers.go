@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"SkillBridge/config"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SecurityHeadersConfig sets the response headers that tell browsers how to
+// treat the API's responses. An empty value leaves that header out.
+type SecurityHeadersConfig struct {
+	ContentSecurityPolicy string
+	FrameOptions          string
+	ReferrerPolicy        string
+	// HSTSMaxAge is sent in Strict-Transport-Security on HTTPS requests; zero disables it
+	HSTSMaxAge            time.Duration
+	HSTSIncludeSubdomains bool
+}
+
+// DefaultSecurityHeadersConfig suits a JSON API. SECURITY_CSP,
+// SECURITY_FRAME_OPTIONS and HSTS_MAX_AGE (seconds) override it; set a
+// header's variable to "off" to leave it out.
+func DefaultSecurityHeadersConfig() SecurityHeadersConfig {
+	hstsMaxAge, err := strconv.Atoi(config.GetEnv("HSTS_MAX_AGE", "31536000"))
+	if err != nil {
+		hstsMaxAge = 31536000
+	}
+	return SecurityHeadersConfig{
+		ContentSecurityPolicy: headerFromEnv("SECURITY_CSP", "default-src 'none'; frame-ancestors 'none'"),
+		FrameOptions:          headerFromEnv("SECURITY_FRAME_OPTIONS", "DENY"),
+		ReferrerPolicy:        "strict-origin-when-cross-origin",
+		HSTSMaxAge:            time.Duration(hstsMaxAge) * time.Second,
+		HSTSIncludeSubdomains: true,
+	}
+}
+
+func headerFromEnv(key, fallback string) string {
+	if value := config.GetEnv(key, fallback); value != "off" {
+		return value
+	}
+	return ""
+}
+
+// SecurityHeaders adds the configured headers plus X-Content-Type-Options to
+// every response. HSTS is only sent on HTTPS requests.
+func SecurityHeaders(cfg SecurityHeadersConfig) gin.HandlerFunc {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		if cfg.ContentSecurityPolicy != "" {
+			h.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+		if cfg.FrameOptions != "" {
+			h.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		if cfg.ReferrerPolicy != "" {
+			h.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		if hsts != "" && isHTTPS(c) {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+		c.Next()
+	}
+}
+
+// DocumentCSP is the policy for HTML pages the API renders itself, such as
+// resume previews and the unsubscribe page, which carry their styles inline
+const DocumentCSP = "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'"
+
+// ContentSecurityPolicy replaces the global policy on the routes it is added
+// to. It does nothing when SECURITY_CSP is "off".
+func ContentSecurityPolicy(policy string) gin.HandlerFunc {
+	disabled := headerFromEnv("SECURITY_CSP", "default") == ""
+	return func(c *gin.Context) {
+		if !disabled {
+			c.Header("Content-Security-Policy", policy)
+		}
+		c.Next()
+	}
+}
+
+// isHTTPS reports whether the client connected over TLS, directly or through a
+// proxy. Trusting X-Forwarded-Proto is harmless here: browsers ignore
+// Strict-Transport-Security received over plain HTTP.
+func isHTTPS(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
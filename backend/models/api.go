@@ -0,0 +1,33 @@
+package models
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Code      string      `json:"code"` // stable machine-readable code, see package apierror
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id"`
+	Error     string      `json:"error"` // same as message, kept for older clients
+}
+
+// FieldError is one invalid field of a request body
+type FieldError struct {
+	Field   string `json:"field"` // JSON path of the field, e.g. criteria[0].name
+	Rule    string `json:"rule"`  // the rule it failed, e.g. required, email, max
+	Message string `json:"message"`
+}
+
+// ValidationErrorResponse documents the 400 returned when a request body
+// cannot be decoded or fails validation. It is an ErrorResponse whose details
+// list the invalid fields.
+type ValidationErrorResponse struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id"`
+	Error     string       `json:"error"`
+}
+
+// MessageResponse is the body of actions that only report success
+type MessageResponse struct {
+	Message string `json:"message"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// API key scopes
+const (
+	ScopeJobsRead         = "jobs:read"
+	ScopeJobsWrite        = "jobs:write"
+	ScopeApplicationsRead = "applications:read"
+)
+
+// APIKeyScope is a scope with what it allows
+type APIKeyScope struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// APIKeyScopes lists every scope an API key can be given
+var APIKeyScopes = []APIKeyScope{
+	{ScopeJobsRead, "List your job listings and their hiring pipelines"},
+	{ScopeJobsWrite, "Create, update and delete job listings"},
+	{ScopeApplicationsRead, "List and export applications to your jobs"},
+}
+
+// APIKey lets a company script the API without a browser login. Only a hash of
+// the key is stored; the key itself is shown once when it is created.
+type APIKey struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	UserID         uint       `json:"user_id" gorm:"not null;index"`
+	OrganisationID *uint      `json:"organisation_id,omitempty" gorm:"index"`
+	Name           string     `json:"name" gorm:"size:100"`
+	Prefix         string     `json:"prefix" gorm:"size:16"` // start of the key, to recognise it
+	Hash           string     `json:"-" gorm:"size:64;uniqueIndex"`
+	Scopes         string     `json:"scopes"` // comma-separated
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP     string     `json:"last_used_ip,omitempty" gorm:"size:45"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
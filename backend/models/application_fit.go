@@ -0,0 +1,27 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ApplicationFit caches how well an applicant matches the job they applied to.
+// Changes to the job, the applicant's profile or their completed projects mark
+// it stale, and a background job computes it again.
+type ApplicationFit struct {
+	ID               uint            `gorm:"primaryKey" json:"-"`
+	JobApplicationID uint            `gorm:"uniqueIndex" json:"job_application_id"`
+	JobListingID     uint            `gorm:"index" json:"job_listing_id"`
+	UserID           uint            `gorm:"index" json:"user_id"`
+	Score            float64         `gorm:"index" json:"score"` // 0-100
+	SkillScore       float64         `json:"skill_score"`        // 0-1 components
+	RequirementScore float64         `json:"requirement_score"`
+	ExperienceScore  float64         `json:"experience_score"`
+	ProjectScore     float64         `json:"project_score"`
+	ExperienceYears  float64         `json:"experience_years"`
+	MatchedSkills    json.RawMessage `gorm:"type:json" json:"matched_skills"`  // JSON array
+	MissingSkills    json.RawMessage `gorm:"type:json" json:"missing_skills"`  // JSON array
+	VerifiedSkills   json.RawMessage `gorm:"type:json" json:"verified_skills"` // matched skills shown in completed SkillBridge projects
+	ComputedAt       time.Time       `json:"computed_at"`
+	Stale            bool            `json:"stale"` // a fresh score is queued
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Background job statuses
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed" // gave up after MaxAttempts or a permanent error
+)
+
+// BackgroundJob is a unit of work for the in-process queue. Jobs are stored so
+// they survive restarts and can be retried; see package queue.
+type BackgroundJob struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	Type        string          `json:"type" gorm:"size:100;not null;index"`
+	Payload     json.RawMessage `json:"payload" gorm:"type:json"`
+	Status      string          `json:"status" gorm:"size:16;not null;index:idx_job_poll,priority:1"`
+	RunAt       time.Time       `json:"run_at" gorm:"index:idx_job_poll,priority:2"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	UniqueKey   *string         `json:"unique_key,omitempty" gorm:"size:191;uniqueIndex"` // at most one unfinished job per key
+	LockedBy    string          `json:"locked_by,omitempty" gorm:"size:64"`
+	LockedAt    *time.Time      `json:"locked_at,omitempty"`
+	LastError   string          `json:"last_error,omitempty" gorm:"type:text"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Certificate is issued when a company accepts a student's project submission.
+// Credential holds the exact signed JSON so it can be re-verified byte for byte.
+type Certificate struct {
+	ID               uint       `json:"-" gorm:"primaryKey"`
+	CertificateID    string     `json:"certificate_id" gorm:"size:64;uniqueIndex;not null"`
+	SubmissionID     uint       `json:"submission_id" gorm:"uniqueIndex;not null"`
+	StudentID        uint       `json:"student_id" gorm:"index;not null"`
+	ProjectID        uint       `json:"project_id" gorm:"index;not null"`
+	CompanyID        uint       `json:"company_id" gorm:"index;not null"`
+	GuideID          *uint      `json:"guide_id,omitempty"`
+	StudentName      string     `json:"student_name"`
+	ProjectTitle     string     `json:"project_title"`
+	CompanyName      string     `json:"company_name"`
+	GuideName        string     `json:"guide_name,omitempty"`
+	CompletedAt      time.Time  `json:"completed_at"`
+	IssuedAt         time.Time  `json:"issued_at"`
+	Credential       string     `json:"credential" gorm:"type:text"`
+	Signature        string     `json:"signature"`
+	Revoked          bool       `json:"revoked" gorm:"default:false"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	RevokedByID      *uint      `json:"revoked_by_id,omitempty"`
+	RevocationReason string     `json:"revocation_reason,omitempty"`
+}
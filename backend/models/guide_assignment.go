@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultGuideCapacity is the number of projects a guide can be assigned to
+// when they have not set their own limit.
+const DefaultGuideCapacity = 3
+
+type ProjectGuideInvitation struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	ProjectID   uint           `json:"project_id" gorm:"not null;index"`
+	GuideID     uint           `json:"guide_id" gorm:"not null;index"`
+	InvitedByID uint           `json:"invited_by_id" gorm:"not null"`
+	Message     string         `json:"message" gorm:"type:text"`
+	Status      string         `json:"status" gorm:"default:'pending'"` // pending, accepted, declined, cancelled
+	RespondedAt *time.Time     `json:"responded_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Associations
+	Project Project `json:"project" gorm:"foreignKey:ProjectID"`
+	Guide   User    `json:"guide" gorm:"foreignKey:GuideID"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InterviewSlot is a time a company offers for interviewing candidates of a job.
+// StartsAt and EndsAt are stored in UTC; TimeZone is the IANA zone the slot was
+// offered in and is used when presenting it.
+type InterviewSlot struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	JobListingID uint           `json:"job_listing_id" gorm:"not null;index"`
+	CreatedByID  uint           `json:"created_by_id" gorm:"not null;index"`
+	StartsAt     time.Time      `json:"starts_at" gorm:"not null;index"`
+	EndsAt       time.Time      `json:"ends_at" gorm:"not null"`
+	TimeZone     string         `json:"time_zone" gorm:"size:64;default:'UTC'"`
+	Location     string         `json:"location"`
+	MeetingURL   string         `json:"meeting_url"`
+	Notes        string         `json:"notes" gorm:"type:text"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Associations
+	JobListing JobListing `json:"job_listing,omitempty" gorm:"foreignKey:JobListingID"`
+	CreatedBy  User       `json:"created_by,omitempty" gorm:"foreignKey:CreatedByID"`
+}
+
+// Interview is a candidate's booking of a slot. Rescheduling moves it to another
+// slot and bumps Sequence so calendar clients replace the earlier event.
+type Interview struct {
+	ID               uint       `json:"id" gorm:"primaryKey"`
+	UID              string     `json:"uid" gorm:"size:64;uniqueIndex;not null"` // stable iCalendar UID
+	SlotID           uint       `json:"slot_id" gorm:"not null;index"`
+	JobApplicationID uint       `json:"job_application_id" gorm:"not null;index"`
+	CandidateID      uint       `json:"candidate_id" gorm:"not null;index"`
+	Status           string     `json:"status" gorm:"default:'scheduled';index"` // scheduled, cancelled
+	Sequence         int        `json:"sequence" gorm:"default:0"`
+	CancelledByID    *uint      `json:"cancelled_by_id,omitempty"`
+	CancelReason     string     `json:"cancel_reason,omitempty" gorm:"type:text"`
+	CancelledAt      *time.Time `json:"cancelled_at,omitempty"`
+	ReminderSentAt   *time.Time `json:"reminder_sent_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+
+	// Associations
+	Slot           InterviewSlot  `json:"slot" gorm:"foreignKey:SlotID"`
+	JobApplication JobApplication `json:"job_application,omitempty" gorm:"foreignKey:JobApplicationID"`
+	Candidate      User           `json:"candidate,omitempty" gorm:"foreignKey:CandidateID"`
+}
+
+// CalendarFeed holds the secret token for a user's interview calendar subscription
+type CalendarFeed struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex;not null"`
+	Token     string    `json:"token" gorm:"size:64;uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -12,6 +12,7 @@ type JobListing struct {
 	ID                  uint            `gorm:"primaryKey" json:"id"`
 	CompanyID           uint            `json:"company_id"`
 	Company             User            `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
+	OrganisationID      *uint           `gorm:"index" json:"organisation_id,omitempty"` // owning organisation, if any
 	Title               string          `json:"title"`
 	Description         string          `gorm:"type:text" json:"description"`
 	Category            string          `json:"category"` // Internship, Full-time, Part-time
@@ -36,12 +37,20 @@ type JobApplication struct {
 	JobListing  JobListing     `gorm:"foreignKey:JobListingID" json:"job_listing,omitempty"`
 	UserID      uint           `json:"user_id"`
 	User        User           `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Status      string         `gorm:"default:Applied" json:"status"` // Applied, Shortlisted, Rejected, Accepted
-	Resume      string         `json:"resume"`                         // URL to uploaded resume
+	Status      string         `gorm:"default:Applied" json:"status"` // name of the current pipeline stage
+	StageChangedAt  *time.Time `json:"stage_changed_at,omitempty"`
+	RejectionReason string     `gorm:"type:text" json:"rejection_reason,omitempty"`
+	Resume      string         `json:"resume"`                         // external resume URL
+	ResumeFileID *uint         `gorm:"index" json:"resume_file_id,omitempty"` // uploaded resume attached to the application
+	ResumeFile   *ResumeFile   `gorm:"foreignKey:ResumeFileID" json:"resume_file,omitempty"`
+	ResumeVersionID *uint          `gorm:"index" json:"resume_version_id,omitempty"` // frozen resume draft version submitted
+	ResumeVersion   *ResumeVersion `gorm:"foreignKey:ResumeVersionID" json:"resume_version,omitempty"`
 	CoverLetter string         `gorm:"type:text" json:"cover_letter"`
 	AppliedAt   time.Time      `json:"applied_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	StageHistory []ApplicationStageEvent `gorm:"foreignKey:JobApplicationID" json:"stage_history,omitempty"`
+	Fit          *ApplicationFit         `gorm:"foreignKey:JobApplicationID" json:"fit,omitempty"`
 }
 
 type UserSavedJob struct {
@@ -84,12 +93,30 @@ type UpdateJobListingRequest struct {
 }
 
 type ApplyToJobRequest struct {
-	CoverLetter string `json:"cover_letter"`
-	Resume      string `json:"resume"`
+	CoverLetter  string `json:"cover_letter"`
+	Resume       string `json:"resume"`
+	ResumeFileID *uint  `json:"resume_file_id"` // one of the student's uploaded resumes
+	ResumeVersionID *uint `json:"resume_version_id"` // a saved version of one of the student's resume drafts
 }
 
 type UpdateApplicationStatusRequest struct {
-	Status string `json:"status" binding:"required"`
+	Status string `json:"status" binding:"required"` // pipeline stage name
+	Reason string `json:"reason"`                    // recorded with the move; used as the rejection reason
+}
+
+type BulkMoveApplicationsRequest struct {
+	ApplicationIDs []uint `json:"application_ids" binding:"required"`
+	Status         string `json:"status" binding:"required"`
+	Reason         string `json:"reason"`
+}
+
+type PipelineStageInput struct {
+	Name string `json:"name" binding:"required"`
+	Kind string `json:"kind"` // open (default), hired, rejected
+}
+
+type UpdatePipelineRequest struct {
+	Stages []PipelineStageInput `json:"stages" binding:"required"`
 }
 
 type JobApplicationResponse struct {
@@ -97,9 +124,14 @@ type JobApplicationResponse struct {
 	User        User           `json:"user"`
 	Status      string         `json:"status"`
 	Resume      string         `json:"resume"`
+	ResumeFileID *uint         `json:"resume_file_id,omitempty"`
+	ResumeVersionID *uint      `json:"resume_version_id,omitempty"`
 	CoverLetter string         `json:"cover_letter"`
 	AppliedAt   time.Time      `json:"applied_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
+	StageChangedAt  *time.Time `json:"stage_changed_at,omitempty"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	Fit         *ApplicationFit `json:"fit,omitempty"`
 }
 
 // Custom scan/value methods for JSON handling
@@ -5,6 +5,7 @@ import "gorm.io/gorm"
 type Notification struct {
 	gorm.Model
 	UserID  uint   `json:"user_id"`
+	Event   string `json:"event" gorm:"size:50"` // see package notify; empty for general notices
 	Message string `json:"message"`
 	Read    bool   `json:"read"`
 }
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Email delivery modes for a notification event
+const (
+	EmailOff     = "off"
+	EmailInstant = "instant" // sent as soon as the event happens
+	EmailDigest  = "digest"  // collected into one email a day
+)
+
+// NotificationPreference is how a user wants to hear about one kind of event.
+// Events without a row use the defaults in package notify.
+type NotificationPreference struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	UserID    uint      `json:"-" gorm:"not null;uniqueIndex:idx_notification_pref"`
+	Event     string    `json:"event" gorm:"size:50;not null;uniqueIndex:idx_notification_pref"`
+	InApp     bool      `json:"in_app"`
+	Email     string    `json:"email" gorm:"size:10"` // off, instant, digest
+	Webhook   bool      `json:"webhook"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// NotificationSettings holds a user's channel-wide settings
+type NotificationSettings struct {
+	ID            uint      `json:"-" gorm:"primaryKey"`
+	UserID        uint      `json:"-" gorm:"uniqueIndex;not null"`
+	EmailPaused   bool      `json:"email_paused"` // set by "unsubscribe from all"
+	WebhookURL    string    `json:"webhook_url" gorm:"size:500"`
+	WebhookSecret string    `json:"-" gorm:"size:64"` // signs webhook bodies
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NotificationDigestItem is an event waiting to go out in the user's daily digest email
+type NotificationDigestItem struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index:idx_digest_pending,priority:1"`
+	Event     string     `json:"event" gorm:"size:50"`
+	Subject   string     `json:"subject"`
+	Text      string     `json:"text" gorm:"type:text"`
+	SentAt    *time.Time `json:"sent_at,omitempty" gorm:"index:idx_digest_pending,priority:2"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Organisation is a company account that owns projects and job listings on
+// behalf of its member users. Users still author individual listings.
+type Organisation struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	Name        string          `json:"name" gorm:"not null"`
+	Slug        string          `json:"slug" gorm:"size:120;uniqueIndex"`
+	Description string          `json:"description" gorm:"type:text"`
+	LogoURL     string          `json:"logo_url"`
+	Website     string          `json:"website"`
+	Size        string          `json:"size"` // e.g. 1-10, 11-50, 51-200, 201-1000, 1000+
+	Industry    string          `json:"industry"`
+	Locations   json.RawMessage `json:"locations" gorm:"type:json"` // JSON array
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt  `json:"-" gorm:"index"`
+
+	Members []OrganisationMember `json:"members,omitempty" gorm:"foreignKey:OrganisationID"`
+}
+
+// Organisation member roles
+const (
+	OrgRoleOwner     = "owner"
+	OrgRoleRecruiter = "recruiter"
+	OrgRoleReviewer  = "reviewer"
+)
+
+// OrganisationMember links a company user to their organisation. A user belongs to at most one.
+type OrganisationMember struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	OrganisationID uint      `json:"organisation_id" gorm:"not null;index"`
+	UserID         uint      `json:"user_id" gorm:"not null;uniqueIndex"`
+	Role           string    `json:"role" gorm:"not null"` // owner, recruiter, reviewer
+	CreatedAt      time.Time `json:"created_at"`
+
+	User User `json:"user" gorm:"foreignKey:UserID"`
+}
+
+type OrganisationInvitation struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	OrganisationID uint       `json:"organisation_id" gorm:"not null;index"`
+	Email          string     `json:"email" gorm:"not null;index"`
+	Role           string     `json:"role" gorm:"not null"`
+	Token          string     `json:"-" gorm:"size:64;uniqueIndex"`
+	InvitedByID    uint       `json:"invited_by_id"`
+	Status         string     `json:"status" gorm:"default:'pending'"` // pending, accepted, revoked
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+
+	Organisation Organisation `json:"organisation,omitempty" gorm:"foreignKey:OrganisationID"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Stage kinds. Open stages are steps in the hiring process; hired and
+// rejected stages end it.
+const (
+	StageKindOpen     = "open"
+	StageKindHired    = "hired"
+	StageKindRejected = "rejected"
+)
+
+// PipelineStage is one step of a job's hiring pipeline. Jobs without custom
+// stages use DefaultPipelineStages. Applications store the current stage name
+// in JobApplication.Status.
+type PipelineStage struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	JobListingID uint      `json:"job_listing_id" gorm:"not null;index"`
+	Name         string    `json:"name" gorm:"size:100;not null"`
+	Kind         string    `json:"kind" gorm:"default:'open'"` // open, hired, rejected
+	Position     int       `json:"position"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DefaultPipelineStages returns the original fixed set of application statuses
+func DefaultPipelineStages(jobListingID uint) []PipelineStage {
+	return []PipelineStage{
+		{JobListingID: jobListingID, Name: "Applied", Kind: StageKindOpen, Position: 0},
+		{JobListingID: jobListingID, Name: "Shortlisted", Kind: StageKindOpen, Position: 1},
+		{JobListingID: jobListingID, Name: "Accepted", Kind: StageKindHired, Position: 2},
+		{JobListingID: jobListingID, Name: "Rejected", Kind: StageKindRejected, Position: 3},
+	}
+}
+
+// ApplicationStageEvent records a move of an application between stages
+type ApplicationStageEvent struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	JobApplicationID uint      `json:"job_application_id" gorm:"not null;index"`
+	FromStage        string    `json:"from_stage"`
+	ToStage          string    `json:"to_stage" gorm:"index"`
+	Reason           string    `json:"reason,omitempty" gorm:"type:text"`
+	MovedByID        *uint     `json:"moved_by_id,omitempty"` // nil when the student applied
+	CreatedAt        time.Time `json:"created_at"`
+
+	// Associations
+	MovedBy *User `json:"moved_by,omitempty" gorm:"foreignKey:MovedByID"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PortfolioSettings controls which parts of a student's public portfolio are visible.
+// Email is hidden unless the student opts in.
+type PortfolioSettings struct {
+	ID               uint      `json:"-" gorm:"primaryKey"`
+	UserID           uint      `json:"user_id" gorm:"uniqueIndex;not null"`
+	ShowEmail        bool      `json:"show_email" gorm:"default:false"`
+	ShowPhone        bool      `json:"show_phone" gorm:"default:false"`
+	ShowEducation    bool      `json:"show_education" gorm:"default:true"`
+	ShowLinks        bool      `json:"show_links" gorm:"default:true"`
+	ShowProjects     bool      `json:"show_projects" gorm:"default:true"`
+	ShowScores       bool      `json:"show_scores" gorm:"default:true"`
+	ShowCertificates bool      `json:"show_certificates" gorm:"default:true"`
+	ShowSkills       bool      `json:"show_skills" gorm:"default:true"`
+	ShowGithubStats  bool      `json:"show_github_stats" gorm:"default:true"`
+	ShowEndorsements bool      `json:"show_endorsements" gorm:"default:true"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	// Last GitHub stats fetched in the background, so portfolio views never wait on GitHub
+	GithubStats          json.RawMessage `json:"-" gorm:"type:json"`
+	GithubStatsFetchedAt *time.Time      `json:"-"`
+}
+
+// DefaultPortfolioSettings is used for students who have never saved their settings
+func DefaultPortfolioSettings(userID uint) PortfolioSettings {
+	return PortfolioSettings{
+		UserID:           userID,
+		ShowEducation:    true,
+		ShowLinks:        true,
+		ShowProjects:     true,
+		ShowScores:       true,
+		ShowCertificates: true,
+		ShowSkills:       true,
+		ShowGithubStats:  true,
+		ShowEndorsements: true,
+	}
+}
+
+// GuideEndorsement is a guide vouching for a student's skill
+type GuideEndorsement struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	GuideID   uint      `json:"guide_id" gorm:"not null;uniqueIndex:idx_endorsement"`
+	StudentID uint      `json:"student_id" gorm:"not null;uniqueIndex:idx_endorsement;index"`
+	Skill     string    `json:"skill" gorm:"size:100;not null;uniqueIndex:idx_endorsement"`
+	ProjectID *uint     `json:"project_id,omitempty"` // project where the skill was observed
+	Comment   string    `json:"comment" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Guide User `json:"guide" gorm:"foreignKey:GuideID"`
+}
@@ -12,9 +12,11 @@ type Project struct {
 	Requirements string    `json:"requirements"`
 	Skills       string    `json:"skills"`
 	Budget       string    `json:"budget"`
-	CompanyID    uint      `json:"company_id"`
+	CompanyID    uint      `json:"company_id"`         // User who posted the project
+	OrganisationID *uint   `json:"organisation_id,omitempty" gorm:"index"` // Owning organisation, if any
 	GuideID      *uint     `json:"guide_id,omitempty"` // Optional guide assignment
 	Deadline     time.Time `json:"deadline"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"` // set once the deadline has passed
 	Difficulty   string    `json:"difficulty"`   // beginner, intermediate, advanced
 	Duration     string    `json:"duration"`     // project duration
 	TeamSize     string    `json:"team_size"`    // number of team members
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ResumeDraft is a student's editable resume. Content holds the same JSON body
+// that GenerateResume accepts (experience, education, projects, ...).
+type ResumeDraft struct {
+	ID             uint            `json:"id" gorm:"primaryKey"`
+	UserID         uint            `json:"user_id" gorm:"not null;index"`
+	Name           string          `json:"name" gorm:"size:120;not null"`
+	Template       string          `json:"template"`
+	TargetJobID    *uint           `json:"target_job_id,omitempty"` // job this copy is tailored for
+	DuplicatedFrom *uint           `json:"duplicated_from,omitempty"`
+	Content        json.RawMessage `json:"content" gorm:"type:json"`
+	LatestVersion  int             `json:"latest_version" gorm:"default:0"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt  `json:"-" gorm:"index"`
+
+	Versions []ResumeVersion `json:"versions,omitempty" gorm:"foreignKey:DraftID"`
+}
+
+// ResumeVersion is an immutable snapshot of a draft together with the PDF
+// rendered from it, so an application shows exactly what was submitted
+type ResumeVersion struct {
+	ID           uint            `json:"id" gorm:"primaryKey"`
+	DraftID      uint            `json:"draft_id" gorm:"not null;uniqueIndex:idx_draft_version"`
+	Version      int             `json:"version" gorm:"not null;uniqueIndex:idx_draft_version"`
+	Label        string          `json:"label"`
+	UserID       uint            `json:"user_id" gorm:"not null;index"`
+	Template     string          `json:"template"`
+	Content      json.RawMessage `json:"content" gorm:"type:json"`
+	ResumeFileID *uint           `json:"resume_file_id,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ResumeFile is an uploaded resume. The file itself lives in the configured
+// storage backend under StorageKey.
+type ResumeFile struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	UserID      uint           `json:"user_id" gorm:"not null;index"`
+	FileName    string         `json:"file_name"`
+	ContentType string         `json:"content_type"`
+	Size        int64          `json:"size"`
+	Checksum    string         `json:"checksum" gorm:"size:64"` // hex SHA-256 of the contents
+	StorageKey  string         `json:"-" gorm:"size:255;not null"`
+	CreatedAt   time.Time      `json:"created_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
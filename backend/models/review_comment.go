@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReviewComment is a threaded comment on a submission, anchored either to a
+// line range of a file in the submitted repository or to a general section.
+type ReviewComment struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	SubmissionID uint           `json:"submission_id" gorm:"not null;index"`
+	AuthorID     uint           `json:"author_id" gorm:"not null"`
+	ParentID     *uint          `json:"parent_id,omitempty" gorm:"index"` // set on replies
+	FilePath     string         `json:"file_path,omitempty"`
+	StartLine    int            `json:"start_line,omitempty"`
+	EndLine      int            `json:"end_line,omitempty"`
+	Section      string         `json:"section,omitempty"` // e.g. general, readme, demo (when not anchored to a file)
+	Body         string         `json:"body" gorm:"type:text;not null"`
+	Resolved     bool           `json:"resolved" gorm:"default:false"`
+	ResolvedByID *uint          `json:"resolved_by_id,omitempty"`
+	ResolvedAt   *time.Time     `json:"resolved_at,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Associations
+	Author   User                   `json:"author" gorm:"foreignKey:AuthorID"`
+	Replies  []ReviewComment        `json:"replies,omitempty" gorm:"foreignKey:ParentID"`
+	Mentions []ReviewCommentMention `json:"mentions,omitempty" gorm:"foreignKey:CommentID"`
+}
+
+type ReviewCommentMention struct {
+	ID        uint `json:"id" gorm:"primaryKey"`
+	CommentID uint `json:"comment_id" gorm:"not null;index"`
+	UserID    uint `json:"user_id" gorm:"not null;index"`
+	User      User `json:"user" gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Rubric is the scoring guide a company attaches to a project
+type Rubric struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	ProjectID   uint              `json:"project_id" gorm:"not null;uniqueIndex"`
+	CreatedByID uint              `json:"created_by_id" gorm:"not null"`
+	Title       string            `json:"title"`
+	Criteria    []RubricCriterion `json:"criteria" gorm:"foreignKey:RubricID"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt    `json:"-" gorm:"index"`
+}
+
+type RubricCriterion struct {
+	ID          uint    `json:"id" gorm:"primaryKey"`
+	RubricID    uint    `json:"rubric_id" gorm:"not null;index"`
+	Name        string  `json:"name" gorm:"not null"`
+	Description string  `json:"description" gorm:"type:text"`
+	Weight      float64 `json:"weight" gorm:"default:1"`
+	MaxScore    int     `json:"max_score" gorm:"default:5"` // scores run from 0 to MaxScore
+	Position    int     `json:"position"`
+}
+
+// SubmissionReview is one reviewer's rubric scoring of a submission
+type SubmissionReview struct {
+	ID           uint             `json:"id" gorm:"primaryKey"`
+	SubmissionID uint             `json:"submission_id" gorm:"not null;uniqueIndex:idx_submission_reviewer"`
+	ReviewerID   uint             `json:"reviewer_id" gorm:"not null;uniqueIndex:idx_submission_reviewer"`
+	ReviewerRole string           `json:"reviewer_role"` // company or guide
+	RubricID     uint             `json:"rubric_id"`
+	OverallScore float64          `json:"overall_score"` // weighted percentage, 0-100
+	Comment      string           `json:"comment" gorm:"type:text"`
+	Scores       []CriterionScore `json:"scores" gorm:"foreignKey:ReviewID"`
+	Reviewer     User             `json:"reviewer" gorm:"foreignKey:ReviewerID"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+type CriterionScore struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	ReviewID    uint            `json:"review_id" gorm:"not null;index"`
+	CriterionID uint            `json:"criterion_id" gorm:"not null"`
+	Criterion   RubricCriterion `json:"criterion" gorm:"foreignKey:CriterionID"`
+	Score       int             `json:"score"`
+	Comment     string          `json:"comment" gorm:"type:text"`
+}
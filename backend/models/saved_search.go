@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Alert frequencies for saved searches
+const (
+	AlertInstant = "instant" // notify as soon as a match is found
+	AlertDaily   = "daily"   // one digest notification a day
+)
+
+// SavedSearch is a student's job/project search that is re-run in the
+// background so they hear about new postings that match it
+type SavedSearch struct {
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	UserID          uint           `json:"user_id" gorm:"not null;index"`
+	Name            string         `json:"name" gorm:"size:120"`
+	Domain          string         `json:"domain"`
+	Category        string         `json:"category"`
+	Location        string         `json:"location"`
+	Skills          string         `json:"skills"`      // comma-separated; a posting matches if it asks for any of them
+	MinStipend      int            `json:"min_stipend"` // jobs only
+	IncludeJobs     bool           `json:"include_jobs" gorm:"default:true"`
+	IncludeProjects bool           `json:"include_projects" gorm:"default:true"`
+	Frequency       string         `json:"frequency" gorm:"default:instant"` // instant, daily
+	LastCheckedAt   time.Time      `json:"last_checked_at"`                  // postings created after this are new
+	LastDigestAt    *time.Time     `json:"last_digest_at,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// SavedSearchMatch records a posting found by a saved search, so each one is
+// only announced once. NotifiedAt stays empty until it goes out in a digest.
+type SavedSearchMatch struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	SavedSearchID uint       `json:"saved_search_id" gorm:"not null;uniqueIndex:idx_search_match"`
+	Kind          string     `json:"kind" gorm:"size:16;uniqueIndex:idx_search_match"` // job, project
+	ItemID        uint       `json:"item_id" gorm:"uniqueIndex:idx_search_match"`
+	Title         string     `json:"title"`
+	NotifiedAt    *time.Time `json:"notified_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
@@ -21,4 +21,9 @@ type Submission struct {
 	Student       User      `gorm:"foreignKey:StudentID"`
 	Project       Project   `gorm:"foreignKey:ProjectID"`
 	SubmittedAt   time.Time `json:"submitted_at"`
+
+	// Rubric reviews from the company and the assigned guide
+	Reviews []SubmissionReview `json:"reviews,omitempty" gorm:"foreignKey:SubmissionID"`
+	// Threaded review comments; only top-level threads are loaded here
+	Comments []ReviewComment `json:"comments,omitempty" gorm:"foreignKey:SubmissionID"`
 }
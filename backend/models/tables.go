@@ -0,0 +1,32 @@
+package models
+
+// Tables lists every model migrated at startup, in migration order
+var Tables = []interface{}{
+	&User{},
+	&Project{},
+	&Application{},
+	&Submission{},
+	&Notification{},
+	&Chat{},
+	&GuideConnectionRequest{},
+	&JobListing{},
+	&ResumeFile{},
+	&ResumeDraft{}, &ResumeVersion{},
+	&JobApplication{},
+	&ApplicationFit{},
+	&UserSavedJob{},
+	&InterviewResource{},
+	&ProjectGuideInvitation{},
+	&Rubric{}, &RubricCriterion{}, &SubmissionReview{}, &CriterionScore{},
+	&ReviewComment{}, &ReviewCommentMention{},
+	&Certificate{},
+	&PortfolioSettings{}, &GuideEndorsement{},
+	&Organisation{}, &OrganisationMember{}, &OrganisationInvitation{},
+	&PipelineStage{}, &ApplicationStageEvent{},
+	&InterviewSlot{}, &Interview{}, &CalendarFeed{},
+	&SavedSearch{}, &SavedSearchMatch{},
+	&BackgroundJob{},
+	&NotificationPreference{}, &NotificationSettings{}, &NotificationDigestItem{},
+	&WebhookEndpoint{}, &WebhookDelivery{},
+	&APIKey{},
+}
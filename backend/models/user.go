@@ -1,6 +1,10 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
 	gorm.Model
@@ -21,4 +25,8 @@ type User struct {
 	Position     string `json:"position"`
 	PortfolioURL string `json:"portfolio_url"`
 	Skills       string `json:"skills"`
+	// GuideCapacity caps how many projects a guide can be assigned to at once (0 = default)
+	GuideCapacity int `json:"guide_capacity"`
+	// LastSeenAt is the last authenticated request, used to tell whether a user is online
+	LastSeenAt *time.Time `json:"-"`
 }
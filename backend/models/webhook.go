@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookEndpoint is a URL a company registered to receive SkillBridge events.
+// Like listings, it belongs to the creating company user and their organisation.
+type WebhookEndpoint struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	CompanyID      uint           `json:"company_id" gorm:"not null;index"`
+	OrganisationID *uint          `json:"organisation_id,omitempty" gorm:"index"`
+	URL            string         `json:"url" gorm:"size:500;not null"`
+	Description    string         `json:"description"`
+	Events         string         `json:"events"` // comma-separated event types
+	Secret         string         `json:"-" gorm:"size:64"`
+	Active         bool           `json:"active" gorm:"default:true"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// WebhookDelivery is one event sent, or being sent, to one endpoint. Redelivering
+// creates a new row with the same EventID so receivers can de-duplicate.
+type WebhookDelivery struct {
+	ID             uint            `json:"id" gorm:"primaryKey"`
+	EndpointID     uint            `json:"endpoint_id" gorm:"not null;index"`
+	EventID        string          `json:"event_id" gorm:"size:40;index"`
+	Event          string          `json:"event" gorm:"size:50"`
+	Payload        json.RawMessage `json:"payload,omitempty" gorm:"type:json"`
+	Status         string          `json:"status" gorm:"size:16;default:pending"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	ResponseBody   string          `json:"response_body,omitempty" gorm:"type:text"`
+	Error          string          `json:"error,omitempty" gorm:"type:text"`
+	DurationMS     int64           `json:"duration_ms"`
+	RedeliveryOf   *uint           `json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,65 @@
+package notify
+
+import "SkillBridge/models"
+
+// Events a user can set preferences for
+const (
+	EventSubmissionReviewed = "submission_reviewed"
+	EventApplicationStatus  = "application_status"
+	EventConnectionRequest  = "connection_request"
+	EventChatMessage        = "chat_message"
+	EventGeneral            = "general" // everything else
+)
+
+// EventInfo describes an event and how it is delivered when the user has not said otherwise
+type EventInfo struct {
+	Name        string
+	Description string
+	Default     models.NotificationPreference
+}
+
+var events = []EventInfo{
+	{EventSubmissionReviewed, "A company or guide reviewed your project submission",
+		models.NotificationPreference{InApp: true, Email: models.EmailInstant}},
+	{EventApplicationStatus, "Your job application moved to a new stage",
+		models.NotificationPreference{InApp: true, Email: models.EmailInstant}},
+	{EventConnectionRequest, "A student asked to connect with you",
+		models.NotificationPreference{InApp: true, Email: models.EmailInstant}},
+	{EventChatMessage, "Someone sent you a chat message while you were offline",
+		models.NotificationPreference{InApp: true, Email: models.EmailInstant}},
+	{EventGeneral, "Other updates such as invitations, certificates and alerts",
+		models.NotificationPreference{InApp: true, Email: models.EmailOff}},
+}
+
+// Events lists every event with its description and default delivery
+func Events() []EventInfo {
+	return events
+}
+
+func lookupEvent(name string) (EventInfo, bool) {
+	for _, e := range events {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return EventInfo{}, false
+}
+
+// ValidEvent reports whether name is a known event
+func ValidEvent(name string) bool {
+	_, ok := lookupEvent(name)
+	return ok
+}
+
+// defaultPreference returns the default delivery for an event; unknown events
+// are treated as general notices
+func defaultPreference(userID uint, event string) models.NotificationPreference {
+	info, ok := lookupEvent(event)
+	if !ok {
+		info, _ = lookupEvent(EventGeneral)
+	}
+	pref := info.Default
+	pref.UserID = userID
+	pref.Event = info.Name
+	return pref
+}
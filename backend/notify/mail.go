@@ -0,0 +1,201 @@
+package notify
+
+import (
+	"SkillBridge/config"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mail is one email with plain text and HTML bodies
+type Mail struct {
+	To      string
+	ToName  string
+	Subject string
+	Text    string
+	HTML    string
+	Headers map[string]string // extra headers such as List-Unsubscribe
+}
+
+// Mailer delivers email
+type Mailer interface {
+	Send(ctx context.Context, m Mail) error
+}
+
+// SMTPMailer sends mail through an SMTP server. STARTTLS is used when the
+// server offers it, so a local catcher such as MailHog (localhost:1025) works
+// with no credentials.
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string // address, optionally with a display name
+}
+
+// LogMailer writes emails to the log instead of sending them; it is used when
+// SMTP is not configured
+type LogMailer struct{}
+
+// Send logs the recipient and subject
+func (LogMailer) Send(ctx context.Context, m Mail) error {
+	slog.Info("email not sent, SMTP not configured", "to", m.To, "subject", m.Subject)
+	return nil
+}
+
+// MailerFromEnv builds the mailer from SMTP_HOST, SMTP_PORT, SMTP_USERNAME,
+// SMTP_PASSWORD and SMTP_FROM, falling back to LogMailer without SMTP_HOST
+func MailerFromEnv() (Mailer, error) {
+	host := config.GetEnv("SMTP_HOST", "")
+	if host == "" {
+		return LogMailer{}, nil
+	}
+	port, err := strconv.Atoi(config.GetEnv("SMTP_PORT", "587"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_PORT: %w", err)
+	}
+	from := config.GetEnv("SMTP_FROM", "SkillBridge <no-reply@skillbridge.local>")
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid SMTP_FROM: %w", err)
+	}
+	return SMTPMailer{
+		Host:     host,
+		Port:     port,
+		Username: config.GetEnv("SMTP_USERNAME", ""),
+		Password: config.GetEnv("SMTP_PASSWORD", ""),
+		From:     from,
+	}, nil
+}
+
+// Send delivers the message, giving up when ctx is done
+func (s SMTPMailer) Send(ctx context.Context, m Mail) error {
+	from, err := mail.ParseAddress(s.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender: %w", err)
+	}
+	to, err := mail.ParseAddress(m.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %w", err)
+	}
+	to.Name = m.ToName
+	body, err := buildMessage(from, to, m)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connecting to SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("starting SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+			return fmt.Errorf("starting TLS: %w", err)
+		}
+	}
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage renders a multipart/alternative message with text and HTML parts
+func buildMessage(from, to *mail.Address, m Mail) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + from.String(),
+		"To: " + to.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + messageID(from.Address),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + mw.Boundary(),
+	}
+	for key, value := range m.Headers {
+		headers = append(headers, textproto.CanonicalMIMEHeaderKey(key)+": "+value)
+	}
+	var out bytes.Buffer
+	out.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	parts := []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	}
+	for _, part := range parts {
+		if part.body == "" {
+			continue
+		}
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		qp.Close()
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	out.Write(buf.Bytes())
+	return out.Bytes(), nil
+}
+
+func messageID(from string) string {
+	domain := "skillbridge.local"
+	if i := strings.LastIndex(from, "@"); i >= 0 {
+		domain = from[i+1:]
+	}
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
@@ -0,0 +1,327 @@
+// Package notify routes notifications to users over the channels they chose:
+// in-app notifications, email (instant or a daily digest) and a personal
+// webhook. Email and webhook deliveries run as background jobs.
+package notify
+
+import (
+	"SkillBridge/models"
+	"SkillBridge/queue"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Background job types
+const (
+	jobSendEmail   = "notify_email"
+	jobSendWebhook = "notify_webhook"
+	jobSendDigests = "notify_digests"
+)
+
+// coalesceDelay is how long instant emails sharing a Coalesce key are held so
+// that a burst of events produces one email
+const coalesceDelay = 5 * time.Minute
+
+// maxDigestItems caps how many events one digest email lists
+const maxDigestItems = 50
+
+// Message is one event for one user
+type Message struct {
+	Event string            `json:"event"`
+	Text  string            `json:"text"`           // the in-app notification, also used in emails
+	Data  map[string]string `json:"data,omitempty"` // fields for the event's email template
+	Link  string            `json:"link,omitempty"` // path in the web app, e.g. /applications/12
+	// Coalesce holds instant emails with the same key for a few minutes and
+	// sends only the first, e.g. one email per chat conversation
+	Coalesce string `json:"coalesce,omitempty"`
+}
+
+type deliveryPayload struct {
+	UserID    uint      `json:"user_id"`
+	Message   Message   `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var (
+	DB     *gorm.DB
+	mailer Mailer = LogMailer{}
+)
+
+// Init sets the database used by delivery jobs and the mailer that sends email
+func Init(db *gorm.DB, m Mailer) {
+	DB = db
+	mailer = m
+}
+
+// RegisterJobs registers the delivery job handlers and the daily digest.
+// It must be called before the queue is started.
+func RegisterJobs() error {
+	queue.Register(jobSendEmail, queue.Definition{Handler: deliverEmail})
+	queue.Register(jobSendWebhook, queue.Definition{Handler: deliverWebhook, MaxAttempts: 8, Timeout: 30 * time.Second})
+	queue.Register(jobSendDigests, queue.Definition{Handler: sendDigests, Timeout: 15 * time.Minute})
+	return queue.Every("notification-digest", "0 8 * * *", jobSendDigests, nil)
+}
+
+// Send notifies a user of an event on each channel their preferences allow.
+// Pass a transaction as db to deliver only if the surrounding changes commit.
+func Send(db *gorm.DB, userID uint, msg Message) error {
+	if !ValidEvent(msg.Event) {
+		msg.Event = EventGeneral
+	}
+	pref := Preference(db, userID, msg.Event)
+	settings := Settings(db, userID)
+
+	if pref.InApp {
+		notification := models.Notification{UserID: userID, Event: msg.Event, Message: msg.Text}
+		if err := db.Create(&notification).Error; err != nil {
+			return err
+		}
+	}
+
+	if !settings.EmailPaused {
+		switch pref.Email {
+		case models.EmailInstant:
+			var opts []queue.Option
+			if msg.Coalesce != "" {
+				opts = append(opts, queue.Unique(fmt.Sprintf("email:%d:%s", userID, msg.Coalesce)), queue.After(coalesceDelay))
+			}
+			_, err := queue.Enqueue(db, jobSendEmail, deliveryPayload{UserID: userID, Message: msg, CreatedAt: time.Now()}, opts...)
+			if err != nil && !errors.Is(err, queue.ErrDuplicate) {
+				return err
+			}
+		case models.EmailDigest:
+			mail, err := render(msg.Event, templateData{Text: msg.Text, Data: msg.Data})
+			if err != nil {
+				return err
+			}
+			item := models.NotificationDigestItem{UserID: userID, Event: msg.Event, Subject: mail.Subject, Text: msg.Text}
+			if err := db.Create(&item).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if pref.Webhook && settings.WebhookURL != "" {
+		if _, err := queue.Enqueue(db, jobSendWebhook, deliveryPayload{UserID: userID, Message: msg, CreatedAt: time.Now()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Preference returns how a user wants to receive an event, falling back to the default
+func Preference(db *gorm.DB, userID uint, event string) models.NotificationPreference {
+	var pref models.NotificationPreference
+	if err := db.Where("user_id = ? AND event = ?", userID, event).First(&pref).Error; err != nil {
+		return defaultPreference(userID, event)
+	}
+	return pref
+}
+
+// Preferences returns the user's effective preference for every event
+func Preferences(db *gorm.DB, userID uint) []models.NotificationPreference {
+	var saved []models.NotificationPreference
+	db.Where("user_id = ?", userID).Find(&saved)
+	byEvent := map[string]models.NotificationPreference{}
+	for _, pref := range saved {
+		byEvent[pref.Event] = pref
+	}
+
+	prefs := make([]models.NotificationPreference, 0, len(events))
+	for _, e := range events {
+		if pref, ok := byEvent[e.Name]; ok {
+			prefs = append(prefs, pref)
+		} else {
+			prefs = append(prefs, defaultPreference(userID, e.Name))
+		}
+	}
+	return prefs
+}
+
+// SavePreference stores a user's preference for one event
+func SavePreference(db *gorm.DB, pref models.NotificationPreference) error {
+	pref.ID = 0
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "event"}},
+		DoUpdates: clause.AssignmentColumns([]string{"in_app", "email", "webhook", "updated_at"}),
+	}).Create(&pref).Error
+}
+
+// Settings returns the user's channel settings, or empty settings if none were saved
+func Settings(db *gorm.DB, userID uint) models.NotificationSettings {
+	var settings models.NotificationSettings
+	if err := db.Where("user_id = ?", userID).First(&settings).Error; err != nil {
+		return models.NotificationSettings{UserID: userID}
+	}
+	return settings
+}
+
+// Unsubscribe applies an unsubscribe token: it turns off email for the event
+// in the token, or pauses all email
+func Unsubscribe(db *gorm.DB, token string) (string, error) {
+	userID, event, ok := ParseUnsubscribeToken(token)
+	if !ok {
+		return "", errors.New("invalid unsubscribe link")
+	}
+	if event == AllEvents {
+		settings := models.NotificationSettings{UserID: userID, EmailPaused: true}
+		err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"email_paused", "updated_at"}),
+		}).Create(&settings).Error
+		return event, err
+	}
+	pref := Preference(db, userID, event)
+	pref.Email = models.EmailOff
+	return event, SavePreference(db, pref)
+}
+
+// deliverEmail sends one instant email, unless the user turned email off after it was queued
+func deliverEmail(ctx context.Context, payload json.RawMessage) error {
+	var p deliveryPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return queue.Permanent(err)
+	}
+	var user models.User
+	if err := DB.First(&user, p.UserID).Error; err != nil {
+		return nil // account deleted
+	}
+	if Settings(DB, user.ID).EmailPaused || Preference(DB, user.ID, p.Message.Event).Email != models.EmailInstant {
+		return nil
+	}
+
+	unsubscribe, err := unsubscribeURL(user.ID, p.Message.Event)
+	if err != nil {
+		return err
+	}
+	unsubscribeAll, err := unsubscribeURL(user.ID, AllEvents)
+	if err != nil {
+		return err
+	}
+
+	mail, err := render(p.Message.Event, templateData{
+		Name:              user.Name,
+		Text:              p.Message.Text,
+		Data:              p.Message.Data,
+		Link:              p.Message.Link,
+		UnsubscribeURL:    unsubscribe,
+		UnsubscribeAllURL: unsubscribeAll,
+	})
+	if err != nil {
+		return queue.Permanent(err)
+	}
+	return mailer.Send(ctx, withUnsubscribeHeaders(Mail{
+		To:      user.Email,
+		ToName:  user.Name,
+		Subject: mail.Subject,
+		Text:    mail.Text,
+		HTML:    mail.HTML,
+	}, unsubscribe))
+}
+
+// withUnsubscribeHeaders adds one-click unsubscribe headers (RFC 8058)
+func withUnsubscribeHeaders(m Mail, link string) Mail {
+	m.Headers = map[string]string{
+		"List-Unsubscribe":      "<" + link + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	return m
+}
+
+// sendDigests emails each user the events collected for their digest
+func sendDigests(ctx context.Context, _ json.RawMessage) error {
+	var userIDs []uint
+	if err := DB.Model(&models.NotificationDigestItem{}).Where("sent_at IS NULL").Distinct().Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := sendDigest(ctx, userID); err != nil {
+			errs = append(errs, fmt.Errorf("digest for user %d: %w", userID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sendDigest(ctx context.Context, userID uint) error {
+	var items []models.NotificationDigestItem
+	if err := DB.Where("user_id = ? AND sent_at IS NULL", userID).Order("id ASC").Limit(maxDigestItems).Find(&items).Error; err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	markSent := func() error {
+		return DB.Model(&models.NotificationDigestItem{}).Where("id IN ?", ids).Update("sent_at", time.Now()).Error
+	}
+
+	var user models.User
+	if err := DB.First(&user, userID).Error; err != nil || Settings(DB, userID).EmailPaused {
+		// Nobody to send to; drop the items rather than retrying forever
+		return markSent()
+	}
+
+	unsubscribeAll, err := unsubscribeURL(user.ID, AllEvents)
+	if err != nil {
+		return err
+	}
+
+	mail, err := render("digest", templateData{
+		Name:              user.Name,
+		Items:             items,
+		UnsubscribeAllURL: unsubscribeAll,
+	})
+	if err != nil {
+		return err
+	}
+	err = mailer.Send(ctx, withUnsubscribeHeaders(Mail{
+		To:      user.Email,
+		ToName:  user.Name,
+		Subject: mail.Subject,
+		Text:    mail.Text,
+		HTML:    mail.HTML,
+	}, unsubscribeAll))
+	if err != nil {
+		return err
+	}
+	return markSent()
+}
+
+// deliverWebhook posts the event to the user's webhook URL
+func deliverWebhook(ctx context.Context, payload json.RawMessage) error {
+	var p deliveryPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return queue.Permanent(err)
+	}
+	settings := Settings(DB, p.UserID)
+	if settings.WebhookURL == "" || !Preference(DB, p.UserID, p.Message.Event).Webhook {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"event":      p.Message.Event,
+		"user_id":    p.UserID,
+		"text":       p.Message.Text,
+		"data":       p.Message.Data,
+		"link":       p.Message.Link,
+		"created_at": p.CreatedAt,
+	})
+	if err != nil {
+		return queue.Permanent(err)
+	}
+	_, err = PostWebhook(ctx, settings.WebhookURL, settings.WebhookSecret, map[string]string{"X-SkillBridge-Event": p.Message.Event}, body)
+	return err
+}
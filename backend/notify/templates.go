@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"SkillBridge/config"
+	"SkillBridge/models"
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// templateData is what every email template is rendered with
+type templateData struct {
+	Name              string            // recipient
+	Text              string            // the in-app message
+	Data              map[string]string // event-specific fields
+	Items             []models.NotificationDigestItem
+	Link              string
+	UnsubscribeURL    string
+	UnsubscribeAllURL string
+}
+
+type renderedMail struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type mailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// mailTemplates holds the parsed templates for each event and the digest
+var mailTemplates = func() map[string]mailTemplate {
+	names := []string{"digest"}
+	for _, e := range events {
+		names = append(names, e.Name)
+	}
+	parsed := map[string]mailTemplate{}
+	for _, name := range names {
+		file := "templates/" + name + ".tmpl"
+		parsed[name] = mailTemplate{
+			text: texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/layout.txt", file)),
+			html: htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/layout.html", file)),
+		}
+	}
+	return parsed
+}()
+
+// appURL is the web app that email links point to
+func appURL() string {
+	return strings.TrimRight(config.GetEnv("APP_URL", "http://localhost:5173"), "/")
+}
+
+// publicURL is where this API is reachable from outside, for unsubscribe links
+func publicURL() string {
+	return strings.TrimRight(config.GetEnv("PUBLIC_URL", "http://localhost:8080"), "/")
+}
+
+// render produces the subject and both bodies of an email from a template
+func render(name string, data templateData) (renderedMail, error) {
+	tmpl, ok := mailTemplates[name]
+	if !ok {
+		tmpl = mailTemplates[EventGeneral]
+	}
+	if data.Link == "" {
+		data.Link = appURL()
+	} else if strings.HasPrefix(data.Link, "/") {
+		data.Link = appURL() + data.Link
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return renderedMail{}, fmt.Errorf("rendering %s subject: %w", name, err)
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "layout.txt", data); err != nil {
+		return renderedMail{}, fmt.Errorf("rendering %s text: %w", name, err)
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "layout.html", data); err != nil {
+		return renderedMail{}, fmt.Errorf("rendering %s html: %w", name, err)
+	}
+	return renderedMail{
+		// Subjects must stay on one line
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,80 @@
+package notify
+
+import (
+	"SkillBridge/config"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// AllEvents in an unsubscribe token stops every email to the user
+const AllEvents = "all"
+
+// unsubscribeKey signs unsubscribe links; see config.Secret
+func unsubscribeKey() ([]byte, error) {
+	secret, err := config.Secret("UNSUBSCRIBE_SECRET", "skillbridge-unsubscribe:development")
+	return []byte(secret), err
+}
+
+// CheckUnsubscribeKey reports why unsubscribe links cannot be signed, if they
+// can't. main calls it at startup so a missing secret stops the server.
+func CheckUnsubscribeKey() error {
+	_, err := unsubscribeKey()
+	return err
+}
+
+func unsubscribeSignature(payload string) (string, error) {
+	key, err := unsubscribeKey()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// UnsubscribeToken identifies a user and event without logging in. Tokens do
+// not expire, since old emails should keep working.
+func UnsubscribeToken(userID uint, event string) (string, error) {
+	payload := fmt.Sprintf("%d:%s", userID, event)
+	signature, err := unsubscribeSignature(payload)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + signature, nil
+}
+
+// ParseUnsubscribeToken checks a token made by UnsubscribeToken
+func ParseUnsubscribeToken(token string) (uint, string, bool) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return 0, "", false
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, "", false
+	}
+	payload := string(raw)
+	expected, err := unsubscribeSignature(payload)
+	if err != nil || !hmac.Equal([]byte(signature), []byte(expected)) {
+		return 0, "", false
+	}
+	id, event, found := strings.Cut(payload, ":")
+	userID, err := strconv.ParseUint(id, 10, 64)
+	if !found || err != nil || (event != AllEvents && !ValidEvent(event)) {
+		return 0, "", false
+	}
+	return uint(userID), event, true
+}
+
+func unsubscribeURL(userID uint, event string) (string, error) {
+	token, err := UnsubscribeToken(userID, event)
+	if err != nil {
+		return "", err
+	}
+	return publicURL() + "/api/notifications/unsubscribe?token=" + url.QueryEscape(token), nil
+}
@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"SkillBridge/config"
+	"SkillBridge/queue"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// maxWebhookResponse is how much of a webhook response body is kept
+const maxWebhookResponse = 4 << 10
+
+// WebhookResult is what a webhook endpoint sent back
+type WebhookResult struct {
+	StatusCode int
+	Body       string
+	Duration   time.Duration
+}
+
+var errPrivateAddress = errors.New("webhook URL resolves to a private or local address")
+
+// webhookClient refuses to connect to loopback, private and link-local
+// addresses so webhooks cannot be pointed at internal services. Set
+// WEBHOOK_ALLOW_PRIVATE_NETWORKS=true to allow them, e.g. in development.
+var webhookClient = func() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if config.GetEnv("WEBHOOK_ALLOW_PRIVATE_NETWORKS", "false") == "true" {
+				return nil
+			}
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{DialContext: dialer.DialContext, Proxy: http.ProxyFromEnvironment},
+		// Redirects could lead anywhere; receivers must use the registered URL
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}()
+
+// PostWebhook delivers a signed JSON body with the given extra headers. 4xx
+// responses other than 408 and 429 are permanent failures; anything else
+// that is not 2xx can be retried.
+func PostWebhook(ctx context.Context, url, secret string, headers map[string]string, body []byte) (WebhookResult, error) {
+	var result WebhookResult
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return result, queue.Permanent(err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SkillBridge-Webhooks/1.0")
+	req.Header.Set("X-SkillBridge-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-SkillBridge-Signature", SignWebhook(secret, timestamp, body))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	start := time.Now()
+	resp, err := webhookClient.Do(req)
+	result.Duration = time.Since(start)
+	if err != nil {
+		if errors.Is(err, errPrivateAddress) {
+			return result, queue.Permanent(err)
+		}
+		return result, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponse))
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	result.StatusCode = resp.StatusCode
+	result.Body = string(respBody)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return result, nil
+	}
+	err = fmt.Errorf("webhook responded with %s", resp.Status)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return result, queue.Permanent(err)
+	}
+	return result, err
+}
+
+// SignWebhook returns the signature sent with a webhook body: the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the endpoint's secret
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
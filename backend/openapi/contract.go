@@ -0,0 +1,234 @@
+package openapi
+
+import (
+	"SkillBridge/apierror"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Contract check modes
+const (
+	CheckOff    = "off"
+	CheckLog    = "log"    // log responses that drift from the spec
+	CheckStrict = "strict" // also replace them with a 500, for development and CI
+)
+
+// maxCheckedItems caps how many elements of each array are checked
+const maxCheckedItems = 25
+
+// Check compares a success response body with the documented response of a
+// route and returns every mismatch. Routes without a documented JSON response
+// always pass.
+func (s *Spec) Check(method, path string, status int, body []byte) []string {
+	op, ok := s.Operation(method, path)
+	if !ok || op.Response == nil || op.Produces != "" || status != op.status() {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{"response is not valid JSON: " + err.Error()}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var problems []string
+	s.validate(s.schemas.of(op.Response), value, "$", &problems)
+	return problems
+}
+
+func (s *Spec) resolve(schema *Schema) *Schema {
+	for schema.Ref != "" {
+		schema = s.schemas.components[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+func (s *Spec) validate(schema *Schema, value interface{}, at string, problems *[]string) {
+	schema = s.resolve(schema)
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			*problems = append(*problems, fmt.Sprintf("%s: is null", at))
+		}
+		return
+	}
+	for _, part := range schema.AllOf {
+		s.validate(part, value, at, problems)
+	}
+
+	mismatch := func() {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", at, schema.Type, jsonType(value)))
+	}
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			mismatch()
+			return
+		}
+		for _, name := range schema.Required {
+			if _, present := obj[name]; !present {
+				*problems = append(*problems, fmt.Sprintf("%s: missing field %q", at, name))
+			}
+		}
+		for name, v := range obj {
+			if prop, known := schema.Properties[name]; known {
+				s.validate(prop, v, at+"."+name, problems)
+			} else if schema.AdditionalProperties != nil {
+				s.validate(schema.AdditionalProperties, v, at+"."+name, problems)
+			} else if len(schema.Properties) > 0 {
+				*problems = append(*problems, fmt.Sprintf("%s: undocumented field %q", at, name))
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			mismatch()
+			return
+		}
+		for i, item := range items {
+			if i == maxCheckedItems {
+				break
+			}
+			s.validate(schema.Items, item, fmt.Sprintf("%s[%d]", at, i), problems)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			mismatch()
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			mismatch()
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			mismatch()
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			mismatch()
+		}
+	}
+}
+
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+// ContractCheck returns middleware that checks each response against the spec.
+// It is a no-op unless mode is CheckLog or CheckStrict.
+func (s *Spec) ContractCheck(mode string) gin.HandlerFunc {
+	if mode != CheckLog && mode != CheckStrict {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		op, ok := s.Operation(c.Request.Method, c.FullPath())
+		if !ok || op.Response == nil || op.Produces != "" {
+			c.Next()
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: c.Writer, buffer: mode == CheckStrict}
+		c.Writer = rec
+		c.Next()
+		c.Writer = rec.ResponseWriter
+		if !rec.Written() {
+			// Errors are rendered later by middleware.Errors
+			return
+		}
+
+		problems := s.Check(c.Request.Method, c.FullPath(), rec.Status(), rec.body.Bytes())
+		if len(problems) > 0 {
+			slog.Warn("openapi: response does not match the spec", "method", c.Request.Method, "route", c.FullPath(), "problems", strings.Join(problems, "; "))
+		}
+		if !rec.buffer {
+			return
+		}
+		if len(problems) > 0 {
+			apierror.Abort(c, apierror.Internal("Response does not match the API specification").WithDetails(problems))
+			return
+		}
+		rec.ResponseWriter.WriteHeader(rec.Status())
+		rec.ResponseWriter.Write(rec.body.Bytes())
+	}
+}
+
+// responseRecorder keeps a copy of the response body. When buffering, nothing
+// reaches the client until the check has passed.
+type responseRecorder struct {
+	gin.ResponseWriter
+	buffer bool
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.buffer {
+		r.status = code
+		return
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) WriteHeaderNow() {
+	if !r.buffer {
+		r.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	if r.buffer {
+		return len(b), nil
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	return r.Write([]byte(s))
+}
+
+func (r *responseRecorder) Status() int {
+	if r.buffer {
+		if r.status == 0 {
+			return http.StatusOK
+		}
+		return r.status
+	}
+	return r.ResponseWriter.Status()
+}
+
+func (r *responseRecorder) Written() bool {
+	if r.buffer {
+		return r.status != 0 || r.body.Len() > 0
+	}
+	return r.ResponseWriter.Written()
+}
+
+func (r *responseRecorder) Size() int {
+	if r.buffer {
+		if r.body.Len() == 0 && r.status == 0 {
+			return -1
+		}
+		return r.body.Len()
+	}
+	return r.ResponseWriter.Size()
+}
@@ -0,0 +1,434 @@
+package openapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"SkillBridge/controller"
+	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"SkillBridge/notify"
+	"SkillBridge/openapi"
+	"SkillBridge/router"
+	"SkillBridge/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// contractCase is one request against a documented route. URL and body may
+// refer to values saved by earlier cases as {name}.
+type contractCase struct {
+	method, path string
+	url          string            // defaults to path
+	as           string            // saved token to send, if any
+	body         string            // JSON
+	upload       []byte            // sent as the multipart field "file" instead of body
+	save         map[string]string // name -> dotted path into the response, e.g. "job.id"
+	prepare      func(db *gorm.DB, vars map[string]string)
+}
+
+// untested lists documented JSON routes the contract test cannot call, and why
+var untested = map[string]string{
+	"POST /api/google-oauth":      "verifies the token with Google",
+	"POST /api/github/token":      "verifies the token with GitHub",
+	"POST /api/webhooks/:id/ping": "delivers to the webhook URL over the network",
+}
+
+// minimalPDF passes the resume upload checks
+var minimalPDF = []byte("%PDF-1.4\n1 0 obj<<>>endobj\ntrailer<<>>\n%%EOF\n")
+
+func setupContract(t *testing.T) (*gin.Engine, *openapi.Spec, *gorm.DB) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	t.Setenv("APP_ENV", "development")
+	t.Setenv("RESUME_BACKEND", "local")
+	for _, group := range []string{"AUTH", "API", "APPLY", "CHAT"} {
+		t.Setenv("RATE_LIMIT_"+group+"_IP", "off")
+		t.Setenv("RATE_LIMIT_"+group+"_USER", "off")
+	}
+
+	dsn := "file:" + filepath.Join(t.TempDir(), "contract.db") + "?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range models.Tables {
+		if err := db.AutoMigrate(table); err != nil {
+			t.Fatalf("migrating %T: %v", table, err)
+		}
+	}
+	controller.InitAuth(db)
+	controller.InitJobDB(db)
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller.InitStorage(store)
+	notify.Init(db, notify.LogMailer{})
+	middleware.SetAPIKeyResolver(controller.ResolveAPIKey)
+	controller.SeedInterviewResources()
+	if err := controller.RegisterBackgroundJobs(); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := openapi.New("SkillBridge API", "test")
+	spec.Error = models.ErrorResponse{}
+	spec.ValidationError = models.ValidationErrorResponse{}
+	controller.DocumentAPI(spec)
+	return router.SetupRouter(), spec, db
+}
+
+// TestResponsesMatchSpec calls every documented JSON route and checks the
+// success response against its documented schema
+func TestResponsesMatchSpec(t *testing.T) {
+	engine, spec, db := setupContract(t)
+
+	admin := models.User{Name: "Admin", Email: "admin@example.com", Password: "password123", Role: "admin"}
+	if err := db.Create(&admin).Error; err != nil {
+		t.Fatal(err)
+	}
+	adminToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": admin.ID,
+		"role":    admin.Role,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString(middleware.JWT_SECRET)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]string{"admin": adminToken}
+
+	tomorrow := time.Now().Add(24 * time.Hour).UTC()
+	slotStart := func(day, hour int) string {
+		return time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day()+day, hour, 0, 0, 0, time.UTC).Format(time.RFC3339)
+	}
+	deadline := tomorrow.AddDate(0, 1, 0).Format(time.RFC3339)
+	jobDeadline := tomorrow.AddDate(0, 1, 0).Format("2006-01-02")
+
+	cases := []contractCase{
+		// Health
+		{method: "GET", path: "/health"},
+		{method: "GET", path: "/health/live"},
+		{method: "GET", path: "/health/ready"},
+
+		// Accounts
+		{method: "POST", path: "/api/signup", body: `{"name":"Stu Dent","email":"student@example.com","password":"password123","role":"student"}`, save: map[string]string{"student_token": "token"}},
+		{method: "POST", path: "/api/signup", body: `{"name":"Co Owner","email":"company@example.com","password":"password123","role":"company"}`, save: map[string]string{"company_token": "token"}},
+		{method: "POST", path: "/api/signup", body: `{"name":"Rec Ruiter","email":"member@example.com","password":"password123","role":"company"}`, save: map[string]string{"member_token": "token"}},
+		{method: "POST", path: "/api/signup", body: `{"name":"Gui De","email":"guide@example.com","password":"password123","role":"guide"}`, save: map[string]string{"guide_token": "token"}},
+		{method: "POST", path: "/api/login", body: `{"email":"student@example.com","password":"password123"}`},
+		{method: "POST", path: "/api/refresh-token", as: "student_token"},
+		{method: "GET", path: "/api/profile", as: "student_token", save: map[string]string{"student": "id"}},
+		{method: "GET", path: "/api/profile", as: "company_token", save: map[string]string{"company": "id"}},
+		{method: "GET", path: "/api/profile", as: "member_token", save: map[string]string{"member": "id"}},
+		{method: "GET", path: "/api/profile", as: "guide_token", save: map[string]string{"guide": "id"}},
+		{method: "PUT", path: "/api/profile", as: "student_token", body: `{"bio":"Backend developer","skills":"go,sql","university":"Example University","github_url":"https://github.com/example"}`},
+		{method: "DELETE", path: "/api/github/token", as: "student_token"},
+		{method: "GET", path: "/api/guides"},
+
+		// Organisations
+		{method: "POST", path: "/api/organisations", as: "company_token", body: `{"name":"Example Corp","locations":["Remote"]}`, save: map[string]string{"org": "organisation.id"}},
+		{method: "GET", path: "/api/organisation", as: "company_token"},
+		{method: "PUT", path: "/api/organisation", as: "company_token", body: `{"description":"We build things"}`},
+		{method: "GET", path: "/api/organisations/:id", url: "/api/organisations/{org}"},
+		{method: "POST", path: "/api/organisation/invitations", as: "company_token", body: `{"email":"member@example.com","role":"recruiter"}`, save: map[string]string{"invitation": "invitation.id"}},
+		{method: "POST", path: "/api/organisation/invitations", as: "company_token", body: `{"email":"nobody@example.com","role":"reviewer"}`, save: map[string]string{"unused_invitation": "invitation.id"}},
+		{method: "GET", path: "/api/organisation/invitations", as: "company_token"},
+		{method: "DELETE", path: "/api/organisation/invitations/:id", url: "/api/organisation/invitations/{unused_invitation}", as: "company_token"},
+		{method: "GET", path: "/api/my-organisation-invitations", as: "member_token"},
+		{method: "POST", path: "/api/organisation/invitations/accept", as: "member_token", body: `{"invitation_id":{invitation}}`},
+		{method: "PUT", path: "/api/organisation/members/:user_id", url: "/api/organisation/members/{member}", as: "company_token", body: `{"role":"reviewer"}`},
+		{method: "GET", path: "/api/company/:id", url: "/api/company/{company}"},
+
+		// Projects
+		{method: "POST", path: "/api/projects", as: "company_token", body: `{"title":"Inventory API","description":"Build a REST API","skills":"go,sql","deadline":"` + deadline + `"}`, save: map[string]string{"project": "project.id"}},
+		{method: "POST", path: "/api/projects", as: "company_token", body: `{"title":"Throwaway","description":"Deleted later","deadline":"` + deadline + `"}`, save: map[string]string{"spare_project": "project.id"}},
+		{method: "GET", path: "/api/projects"},
+		{method: "GET", path: "/api/projects/:id", url: "/api/projects/{project}"},
+		{method: "GET", path: "/api/company/projects", as: "company_token"},
+		{method: "PUT", path: "/api/projects/:id/rubric", url: "/api/projects/{project}/rubric", as: "company_token", body: `{"title":"Review","criteria":[{"name":"Code quality","weight":1,"max_score":5}]}`, save: map[string]string{"criterion": "rubric.criteria.0.id"}},
+		{method: "GET", path: "/api/projects/:id/rubric", url: "/api/projects/{project}/rubric", as: "student_token"},
+		{method: "POST", path: "/api/projects/:id/guide-invitations", url: "/api/projects/{project}/guide-invitations", as: "company_token", body: `{"guide_id":{guide},"message":"Please review"}`, save: map[string]string{"guide_invitation": "invitation.id"}},
+		{method: "GET", path: "/api/projects/:id/guide-invitations", url: "/api/projects/{project}/guide-invitations", as: "company_token"},
+		{method: "GET", path: "/api/guide/invitations", as: "guide_token"},
+		{method: "POST", path: "/api/guide/invitations/:id/respond", url: "/api/guide/invitations/{guide_invitation}/respond", as: "guide_token", body: `{"action":"accept"}`},
+		{method: "GET", path: "/api/guide/projects", as: "guide_token"},
+		{method: "PUT", path: "/api/guide/capacity", as: "guide_token", body: `{"capacity":5}`},
+		{method: "PUT", path: "/api/admin/projects/:id/guide", url: "/api/admin/projects/{project}/guide", as: "admin", body: `{"guide_id":{guide}}`},
+		{method: "POST", path: "/api/projects/apply", as: "student_token", body: `{"project_id":{project}}`, save: map[string]string{"project_application": "application.ID"}},
+		{method: "POST", path: "/api/projects/apply", as: "student_token", body: `{"project_id":{spare_project}}`},
+		{method: "GET", path: "/api/my-applications", as: "student_token"},
+		{method: "GET", path: "/api/projects/:id/applicants", url: "/api/projects/{project}/applicants", as: "company_token"},
+		{method: "GET", path: "/api/company/applications", as: "company_token"},
+		{method: "POST", path: "/api/projects/submit-github", as: "student_token", body: `{"application_id":{project_application},"github_repo_url":"https://github.com/example/inventory"}`},
+		{method: "POST", path: "/api/projects/:id/submit", url: "/api/projects/{project}/submit", as: "student_token", body: `{"github_repo_url":"https://github.com/example/inventory","description":"Done"}`, save: map[string]string{"submission": "submission.ID"}},
+		{method: "GET", path: "/api/my-submissions", as: "student_token"},
+		{method: "GET", path: "/api/projects/:id/submissions", url: "/api/projects/{project}/submissions", as: "company_token"},
+		{method: "GET", path: "/api/guide/submissions", as: "guide_token"},
+		{method: "GET", path: "/api/submissions/:id", url: "/api/submissions/{submission}", as: "student_token"},
+		{method: "POST", path: "/api/submissions/:id/comments", url: "/api/submissions/{submission}/comments", as: "guide_token", body: `{"body":"Looks good","file_path":"main.go","start_line":1,"end_line":2}`, save: map[string]string{"comment": "comment.id"}},
+		{method: "GET", path: "/api/submissions/:id/comments", url: "/api/submissions/{submission}/comments", as: "student_token"},
+		{method: "PATCH", path: "/api/comments/:id/resolve", url: "/api/comments/{comment}/resolve", as: "guide_token", body: `{"resolved":true}`},
+		{method: "DELETE", path: "/api/comments/:id", url: "/api/comments/{comment}", as: "guide_token"},
+		{method: "PUT", path: "/api/submissions/:id/review", url: "/api/submissions/{submission}/review", as: "guide_token", body: `{"review_status":"approved","review_comment":"Solid","scores":[{"criterion_id":{criterion},"score":4}]}`},
+		{method: "POST", path: "/api/submissions/:id/review", url: "/api/submissions/{submission}/review", as: "company_token", body: `{"status":"accepted","feedback":"Great work","scores":[{"criterion_id":{criterion},"score":5}]}`, save: map[string]string{"certificate": "certificate_id"}},
+		{method: "GET", path: "/api/projects/:id/review-scores", url: "/api/projects/{project}/review-scores", as: "company_token"},
+
+		// Certificates and portfolio
+		{method: "GET", path: "/api/my-certificates", as: "student_token"},
+		{method: "GET", path: "/api/certificates/public-key"},
+		{method: "GET", path: "/api/certificates/:certificate_id/verify", url: "/api/certificates/{certificate}/verify"},
+		{method: "GET", path: "/api/portfolio/settings", as: "student_token"},
+		{method: "PUT", path: "/api/portfolio/settings", as: "student_token", body: `{"show_email":true}`},
+		{method: "POST", path: "/api/student/:id/endorsements", url: "/api/student/{student}/endorsements", as: "guide_token", body: `{"skill":"go","comment":"Strong Go skills","project_id":{project}}`, save: map[string]string{"endorsement": "endorsement.id"}},
+		{method: "GET", path: "/api/student/:id", url: "/api/student/{student}"},
+		{method: "DELETE", path: "/api/endorsements/:id", url: "/api/endorsements/{endorsement}", as: "guide_token"},
+		{method: "POST", path: "/api/certificates/:certificate_id/revoke", url: "/api/certificates/{certificate}/revoke", as: "company_token", body: `{"reason":"Issued by mistake"}`},
+
+		// Chat
+		{method: "POST", path: "/api/chat/start", as: "student_token", body: `{"guide_id":{guide}}`},
+		{method: "GET", path: "/api/guide/pending-confirmations", as: "guide_token", save: map[string]string{"connection": "pending_requests.0.id"}},
+		{method: "POST", path: "/api/guide/confirm-connection", as: "guide_token", body: `{"request_id":{connection},"action":"accept"}`},
+		{method: "POST", path: "/api/chat/send", as: "student_token", body: `{"student_id":{student},"guide_id":{guide},"message":"Hello"}`},
+		{method: "GET", path: "/api/chat/history/:student_id/:guide_id", url: "/api/chat/history/{student}/{guide}", as: "guide_token"},
+		{method: "GET", path: "/api/chat/conversations", as: "student_token"},
+		{method: "GET", path: "/api/chat/connected-guides", as: "student_token"},
+
+		// Jobs
+		{method: "POST", path: "/api/jobs", as: "company_token", body: `{"title":"Backend intern","description":"Go services","category":"internship","domain":"engineering","location":"Remote","stipend":1000,"skills":["go","sql"],"application_deadline":"` + jobDeadline + `"}`, save: map[string]string{"job": "job.id"}},
+		{method: "POST", path: "/api/jobs", as: "company_token", body: `{"title":"Data intern","description":"SQL","category":"internship","domain":"data","location":"Remote","application_deadline":"` + jobDeadline + `"}`, save: map[string]string{"second_job": "job.id"}},
+		{method: "GET", path: "/api/jobs"},
+		{method: "GET", path: "/api/jobs/:id", url: "/api/jobs/{job}"},
+		{method: "PUT", path: "/api/jobs/:id", url: "/api/jobs/{job}", as: "company_token", body: `{"stipend":1200,"is_active":true}`},
+		{method: "GET", path: "/api/company/jobs", as: "company_token"},
+		{method: "PUT", path: "/api/jobs/:id/pipeline", url: "/api/jobs/{job}/pipeline", as: "company_token", body: `{"stages":[{"name":"Applied"},{"name":"Shortlisted"},{"name":"Interview"},{"name":"Accepted","kind":"hired"},{"name":"Rejected","kind":"rejected"}]}`},
+		{method: "GET", path: "/api/jobs/:id/pipeline", url: "/api/jobs/{job}/pipeline", as: "company_token"},
+
+		// Resumes
+		{method: "POST", path: "/api/resumes/upload", as: "student_token", upload: minimalPDF, save: map[string]string{"resume": "resume.id"}},
+		{method: "GET", path: "/api/resumes", as: "student_token"},
+		{method: "GET", path: "/api/resume/templates", as: "student_token"},
+		{method: "POST", path: "/api/resume/generate", as: "student_token", body: `{"summary":"Backend developer"}`, save: map[string]string{"generated_resume": "data.resume_file_id"}},
+		{method: "POST", path: "/api/resume/parse", url: "/api/resume/parse?resume_file_id={generated_resume}", as: "student_token"},
+		{method: "POST", path: "/api/resume/parse/apply", as: "student_token", body: `{"profile":{"major":"Computer Science"},"resume_content":{"summary":"Imported"},"draft_name":"Imported"}`},
+		{method: "POST", path: "/api/resume/parse/apply", as: "student_token", body: `{"profile":{"linkedin":"https://linkedin.com/in/student","github_url":"https://github.com/student"}}`},
+		{method: "POST", path: "/api/resume/drafts", as: "student_token", body: `{"name":"Main","content":{"summary":"Backend developer"}}`, save: map[string]string{"draft": "draft.id"}},
+		{method: "GET", path: "/api/resume/drafts", as: "student_token"},
+		{method: "GET", path: "/api/resume/drafts/:id", url: "/api/resume/drafts/{draft}", as: "student_token"},
+		{method: "PATCH", path: "/api/resume/drafts/:id", url: "/api/resume/drafts/{draft}", as: "student_token", body: `{"content":{"summary":"Go developer"}}`},
+		{method: "POST", path: "/api/resume/drafts/:id/duplicate", url: "/api/resume/drafts/{draft}/duplicate", as: "student_token", body: `{"name":"Tailored","target_job_id":{second_job}}`, save: map[string]string{"spare_draft": "draft.id"}},
+		{method: "POST", path: "/api/resume/drafts/:id/versions", url: "/api/resume/drafts/{draft}/versions", as: "student_token", body: `{"label":"v1"}`, save: map[string]string{"version": "version.version", "version_id": "version.id"}},
+		{method: "GET", path: "/api/resume/drafts/:id/versions/:version", url: "/api/resume/drafts/{draft}/versions/{version}", as: "student_token"},
+		{method: "POST", path: "/api/resume/drafts/:id/versions/:version/restore", url: "/api/resume/drafts/{draft}/versions/{version}/restore", as: "student_token"},
+
+		// Applications
+		{method: "POST", path: "/api/jobs/:id/apply", url: "/api/jobs/{job}/apply", as: "student_token", body: `{"cover_letter":"Hi","resume_file_id":{resume}}`, save: map[string]string{"application": "application_id"}},
+		{method: "POST", path: "/api/jobs/:id/apply", url: "/api/jobs/{second_job}/apply", as: "student_token", body: `{"cover_letter":"Hi","resume_version_id":{version_id}}`, save: map[string]string{"second_application": "application_id"}},
+		{method: "GET", path: "/api/my-job-applications", as: "student_token"},
+		{method: "POST", path: "/api/jobs/:id/applications/fit", url: "/api/jobs/{job}/applications/fit", as: "company_token"},
+		{method: "GET", path: "/api/jobs/:id/applications", url: "/api/jobs/{job}/applications", as: "company_token"},
+		{method: "GET", path: "/api/applications/:id", url: "/api/applications/{application}", as: "company_token"},
+		{method: "PATCH", path: "/api/applications/:id/status", url: "/api/applications/{application}/status", as: "company_token", body: `{"status":"Shortlisted"}`},
+		{method: "POST", path: "/api/jobs/:id/applications/move", url: "/api/jobs/{second_job}/applications/move", as: "company_token", body: `{"application_ids":[{second_application}],"status":"Shortlisted"}`},
+		{method: "GET", path: "/api/applications/:id/history", url: "/api/applications/{application}/history", as: "company_token"},
+		{method: "GET", path: "/api/applications/:id/resume-url", url: "/api/applications/{application}/resume-url", as: "company_token"},
+		{method: "GET", path: "/api/applications/:id/resume-version", url: "/api/applications/{second_application}/resume-version", as: "company_token"},
+		{method: "GET", path: "/api/company/application-stats", as: "company_token"},
+
+		// Interviews
+		{method: "POST", path: "/api/jobs/:id/interview-slots", url: "/api/jobs/{job}/interview-slots", as: "company_token", body: `{"time_zone":"UTC","meeting_url":"https://meet.example.com/room","slots":[{"starts_at":"` + slotStart(0, 10) + `","ends_at":"` + slotStart(0, 11) + `"},{"starts_at":"` + slotStart(1, 10) + `","ends_at":"` + slotStart(1, 11) + `"}]}`, save: map[string]string{"slot": "slots.0.id", "second_slot": "slots.1.id"}},
+		{method: "GET", path: "/api/jobs/:id/interview-slots", url: "/api/jobs/{job}/interview-slots", as: "company_token"},
+		{method: "GET", path: "/api/my-job-applications/:id/interview-slots", url: "/api/my-job-applications/{application}/interview-slots", as: "student_token"},
+		{method: "POST", path: "/api/interviews", as: "student_token", body: `{"application_id":{application},"slot_id":{slot}}`, save: map[string]string{"interview": "interview.id"}},
+		{method: "GET", path: "/api/interviews", as: "company_token"},
+		{method: "PUT", path: "/api/interviews/:id/reschedule", url: "/api/interviews/{interview}/reschedule", as: "student_token", body: `{"slot_id":{second_slot}}`},
+		{method: "GET", path: "/api/interviews/calendar-feed", as: "student_token"},
+		{method: "POST", path: "/api/interviews/:id/cancel", url: "/api/interviews/{interview}/cancel", as: "company_token", body: `{"reason":"Position filled"}`},
+		{method: "DELETE", path: "/api/interview-slots/:id", url: "/api/interview-slots/{slot}", as: "company_token"},
+		{method: "GET", path: "/api/interview-prep", as: "student_token"},
+
+		// Saved searches
+		{method: "POST", path: "/api/saved-searches", as: "student_token", body: `{"name":"Remote Go","location":"Remote","skills":"go"}`, save: map[string]string{"search": "saved_search.id"}},
+		{method: "GET", path: "/api/saved-searches", as: "student_token"},
+		{method: "PUT", path: "/api/saved-searches/:id", url: "/api/saved-searches/{search}", as: "student_token", body: `{"name":"Remote Go jobs","location":"Remote","skills":"go"}`},
+		{method: "GET", path: "/api/saved-searches/:id/results", url: "/api/saved-searches/{search}/results", as: "student_token"},
+		{method: "GET", path: "/api/saved-searches/:id/matches", url: "/api/saved-searches/{search}/matches", as: "student_token"},
+
+		// Notifications
+		{method: "GET", path: "/api/notifications/preferences", as: "student_token"},
+		{method: "PUT", path: "/api/notifications/preferences", as: "student_token", body: `{"email_paused":true}`},
+
+		// Webhooks
+		{method: "GET", path: "/api/webhooks/events", as: "company_token"},
+		{method: "POST", path: "/api/webhooks", as: "company_token", body: `{"url":"https://hooks.example.com/skillbridge","events":["application.created"]}`, save: map[string]string{"webhook": "webhook.id"}},
+		{method: "GET", path: "/api/webhooks", as: "company_token"},
+		{method: "GET", path: "/api/webhooks/:id", url: "/api/webhooks/{webhook}", as: "company_token"},
+		{method: "PUT", path: "/api/webhooks/:id", url: "/api/webhooks/{webhook}", as: "company_token", body: `{"description":"Hiring pipeline"}`},
+		{method: "POST", path: "/api/webhooks/:id/rotate-secret", url: "/api/webhooks/{webhook}/rotate-secret", as: "company_token"},
+		{method: "GET", path: "/api/webhooks/:id/deliveries", url: "/api/webhooks/{webhook}/deliveries", as: "company_token", prepare: func(db *gorm.DB, vars map[string]string) {
+			id, _ := strconv.Atoi(vars["webhook"])
+			delivery := models.WebhookDelivery{EndpointID: uint(id), EventID: "evt_test", Event: "application.created", Payload: json.RawMessage(`{}`), Status: models.DeliveryFailed}
+			db.Create(&delivery)
+			vars["delivery"] = strconv.Itoa(int(delivery.ID))
+		}},
+		{method: "GET", path: "/api/webhooks/:id/deliveries/:delivery_id", url: "/api/webhooks/{webhook}/deliveries/{delivery}", as: "company_token"},
+		{method: "POST", path: "/api/webhooks/:id/deliveries/:delivery_id/redeliver", url: "/api/webhooks/{webhook}/deliveries/{delivery}/redeliver", as: "company_token"},
+
+		// API keys
+		{method: "GET", path: "/api/api-keys/scopes", as: "company_token"},
+		{method: "POST", path: "/api/api-keys", as: "company_token", body: `{"name":"CI","scopes":["jobs:read"]}`, save: map[string]string{"api_key": "api_key.id"}},
+		{method: "GET", path: "/api/api-keys", as: "company_token"},
+
+		// Dashboards
+		{method: "GET", path: "/api/dashboard/student", as: "student_token"},
+		{method: "GET", path: "/api/dashboard/company", as: "company_token"},
+		{method: "GET", path: "/api/dashboard/guide", as: "guide_token"},
+		{method: "GET", path: "/api/dashboard/admin", as: "admin"},
+
+		// Background jobs
+		{method: "GET", path: "/api/admin/jobs", as: "admin", save: map[string]string{"background_job": "jobs.0.id"}},
+		{method: "GET", path: "/api/admin/jobs/:id", url: "/api/admin/jobs/{background_job}", as: "admin"},
+		{method: "POST", path: "/api/admin/jobs/:id/retry", url: "/api/admin/jobs/{background_job}/retry", as: "admin", prepare: func(db *gorm.DB, vars map[string]string) {
+			db.Model(&models.BackgroundJob{}).Where("id = ?", vars["background_job"]).Update("status", models.JobFailed)
+		}},
+		{method: "DELETE", path: "/api/admin/jobs/:id", url: "/api/admin/jobs/{background_job}", as: "admin"},
+
+		// Removals
+		{method: "DELETE", path: "/api/api-keys/:id", url: "/api/api-keys/{api_key}", as: "company_token"},
+		{method: "DELETE", path: "/api/webhooks/:id", url: "/api/webhooks/{webhook}", as: "company_token"},
+		{method: "DELETE", path: "/api/saved-searches/:id", url: "/api/saved-searches/{search}", as: "student_token"},
+		{method: "DELETE", path: "/api/resume/drafts/:id", url: "/api/resume/drafts/{spare_draft}", as: "student_token"},
+		{method: "DELETE", path: "/api/resumes/:id", url: "/api/resumes/{generated_resume}", as: "student_token"},
+		{method: "DELETE", path: "/api/projects/:id/apply", url: "/api/projects/{spare_project}/apply", as: "student_token"},
+		{method: "DELETE", path: "/api/projects/:id", url: "/api/projects/{spare_project}", as: "company_token"},
+		{method: "DELETE", path: "/api/jobs/:id", url: "/api/jobs/{second_job}", as: "company_token"},
+		{method: "DELETE", path: "/api/organisation/members/:user_id", url: "/api/organisation/members/{member}", as: "company_token"},
+	}
+
+	covered := map[string]bool{}
+	for _, tc := range cases {
+		route := tc.method + " " + tc.path
+		op, ok := spec.Operation(tc.method, tc.path)
+		if !ok {
+			t.Fatalf("%s is not documented", route)
+		}
+		covered[route] = true
+		if tc.prepare != nil {
+			tc.prepare(db, vars)
+		}
+
+		url := tc.url
+		if url == "" {
+			url = tc.path
+		}
+		req := newRequest(t, tc.method, expand(url, vars), expand(tc.body, vars), tc.upload)
+		if tc.as != "" {
+			req.Header.Set("Authorization", "Bearer "+vars[tc.as])
+		}
+		rec := httptest.NewRecorder()
+		engine.ServeHTTP(rec, req)
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if rec.Code != status {
+			t.Fatalf("%s: status %d, want %d: %s", route, rec.Code, status, rec.Body.String())
+		}
+		if problems := spec.Check(tc.method, tc.path, rec.Code, rec.Body.Bytes()); len(problems) > 0 {
+			t.Errorf("%s: response does not match the spec:\n  %s", route, strings.Join(problems, "\n  "))
+		}
+		for name, path := range tc.save {
+			value, ok := lookup(rec.Body.Bytes(), path)
+			if !ok {
+				t.Fatalf("%s: response has no %s: %s", route, path, rec.Body.String())
+			}
+			vars[name] = value
+		}
+	}
+
+	for _, route := range engine.Routes() {
+		key := route.Method + " " + route.Path
+		op, ok := spec.Operation(route.Method, route.Path)
+		if !ok || op.Response == nil || op.Produces != "" || covered[key] || untested[key] != "" {
+			continue
+		}
+		t.Errorf("%s has a documented JSON response but no contract test case", key)
+	}
+}
+
+func newRequest(t *testing.T, method, url, body string, upload []byte) *http.Request {
+	t.Helper()
+	if upload == nil {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req
+	}
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	part, err := form.CreateFormFile("file", "resume.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(upload)
+	form.Close()
+	req := httptest.NewRequest(method, url, &buf)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+// expand replaces each {name} with its saved value
+func expand(s string, vars map[string]string) string {
+	for name, value := range vars {
+		s = strings.ReplaceAll(s, "{"+name+"}", value)
+	}
+	return s
+}
+
+// lookup follows a dotted path such as "slots.0.id" through a JSON document
+func lookup(body []byte, path string) (string, bool) {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "", false
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i >= len(v) {
+				return "", false
+			}
+			value = v[i]
+		default:
+			return "", false
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
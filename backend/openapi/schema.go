@@ -0,0 +1,263 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Schema is an OpenAPI 3.0 schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// Object describes a JSON object built from a gin.H by example: each value's
+// type gives the field's schema, e.g. Object{"total": 0, "jobs": []models.JobListing{}}.
+// Every field is required unless wrapped in Optional.
+type Object map[string]interface{}
+
+// Any is a field of any JSON type
+type Any struct{}
+
+// Optional marks a field of an Object that is not always present
+func Optional(v interface{}) interface{} {
+	return optional{v}
+}
+
+type optional struct{ value interface{} }
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	anyType       = reflect.TypeOf(Any{})
+	objectType    = reflect.TypeOf(Object{})
+)
+
+// schemaSet turns Go types into schemas, collecting named structs as shared components
+type schemaSet struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{components: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+// of returns the schema for an example value
+func (s *schemaSet) of(v interface{}) *Schema {
+	if v == nil {
+		return &Schema{}
+	}
+	switch v := v.(type) {
+	case Object:
+		return s.object(v)
+	case []Object:
+		// A list of objects shaped like the first one
+		items := &Schema{Type: "object"}
+		if len(v) > 0 {
+			items = s.object(v[0])
+		}
+		return &Schema{Type: "array", Items: items, Nullable: true}
+	case optional:
+		return s.of(v.value)
+	}
+	return s.typ(reflect.TypeOf(v))
+}
+
+func (s *schemaSet) object(obj Object) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for name, v := range obj {
+		schema.Properties[name] = s.of(v)
+		if _, ok := v.(optional); !ok {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
+
+func (s *schemaSet) typ(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case deletedAtType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: true}
+	case rawJSONType, anyType:
+		return &Schema{}
+	case objectType:
+		return &Schema{Type: "object"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := s.typ(t.Elem())
+		if schema.Ref != "" {
+			// $ref siblings are ignored in OpenAPI 3.0, so wrap it to allow null
+			return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.typ(t.Elem()), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.typ(t.Elem()), Nullable: true}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.component(t)}
+	}
+	return &Schema{}
+}
+
+// component registers a named struct and returns its component name
+func (s *schemaSet) component(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := s.components[name]; taken {
+		pkg := t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	s.names[t] = name
+	s.components[name] = &Schema{} // placeholder so recursive types terminate
+	*s.components[name] = *s.structSchema(t)
+	return name
+}
+
+func (s *schemaSet) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	s.addFields(schema, t)
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// addFields adds a struct's fields the way encoding/json would marshal them,
+// flattening embedded structs such as gorm.Model
+func (s *schemaSet) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.addFields(schema, ft)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := s.typ(field.Type)
+		if applyBinding(prop, field.Tag.Get("binding")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = prop
+	}
+}
+
+// applyBinding copies validator rules from a binding tag onto the schema and
+// reports whether the field is required
+func applyBinding(schema *Schema, tag string) (required bool) {
+	if tag == "" || schema.Ref != "" || schema.AllOf != nil {
+		return strings.Contains(","+tag+",", ",required,")
+	}
+	// Rules after "dive" apply to the elements of a slice
+	target := schema
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			if target == schema {
+				required = true
+			}
+		case "dive":
+			if schema.Items == nil || schema.Items.Ref != "" {
+				return required
+			}
+			target = schema.Items
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				target.Enum = append(target.Enum, v)
+			}
+		case "min", "gte", "max", "lte":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			setBound(target, name == "min" || name == "gte", n)
+		}
+	}
+	return required
+}
+
+func setBound(schema *Schema, lower bool, n float64) {
+	count := int(n)
+	switch schema.Type {
+	case "string":
+		if lower {
+			schema.MinLength = &count
+		} else {
+			schema.MaxLength = &count
+		}
+	case "array":
+		if lower {
+			schema.MinItems = &count
+		} else {
+			schema.MaxItems = &count
+		}
+	case "integer", "number":
+		if lower {
+			schema.Minimum = &n
+		} else {
+			schema.Maximum = &n
+		}
+	}
+}
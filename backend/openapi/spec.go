@@ -0,0 +1,247 @@
+// Package openapi generates the OpenAPI 3 document for the HTTP API from the
+// routes registered on the gin engine and the request and response types
+// documented for them, and checks live responses against it.
+package openapi
+
+import (
+	"SkillBridge/apierror"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Operation documents one route
+type Operation struct {
+	Summary string
+	Tag     string
+	Public  bool // callable without logging in
+	Query   []Param
+	Request interface{} // example of the JSON body, e.g. models.LoginRequest{}
+	// OptionalBody marks a Request the client may leave out
+	OptionalBody bool
+	Response     interface{} // example of the success body, a type or an Object
+	Status       int         // success status, 200 if unset
+	Produces     string      // content type of a success response that is not JSON, e.g. text/csv
+}
+
+// Param is a query string parameter
+type Param struct {
+	Name        string
+	Description string
+	Required    bool
+}
+
+// Spec collects documented operations and builds the document from them
+type Spec struct {
+	Title   string
+	Version string
+	// Error and ValidationError are examples of the error bodies every route can return
+	Error           interface{}
+	ValidationError interface{}
+	// APIKeyScope reports the scope an API key needs to call a route, if keys are accepted
+	APIKeyScope func(method, fullPath string) (string, bool)
+
+	mu      sync.Mutex
+	ops     map[string]Operation
+	schemas *schemaSet
+	doc     []byte
+}
+
+// New returns an empty spec
+func New(title, version string) *Spec {
+	return &Spec{Title: title, Version: version, ops: map[string]Operation{}, schemas: newSchemaSet()}
+}
+
+// Add documents the route registered with method and path, e.g. "GET", "/api/jobs/:id"
+func (s *Spec) Add(method, path string, op Operation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ops[method+" "+path] = op
+	s.doc = nil
+}
+
+// Operation returns the documentation for a route
+func (s *Spec) Operation(method, path string) (Operation, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op, ok := s.ops[method+" "+path]
+	return op, ok
+}
+
+// Undocumented lists the routes that have no Operation
+func (s *Spec) Undocumented(routes gin.RoutesInfo) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var missing []string
+	for _, route := range routes {
+		if _, ok := s.ops[route.Method+" "+route.Path]; !ok {
+			missing = append(missing, route.Method+" "+route.Path)
+		}
+	}
+	return missing
+}
+
+// Handler serves the document for the engine's routes as JSON
+func (s *Spec) Handler(engine *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		doc, err := s.Build(engine.Routes())
+		if err != nil {
+			apierror.Abort(c, apierror.Internal("Failed to build the API specification"))
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", doc)
+	}
+}
+
+// Build renders the document. Routes without an Operation are still listed so
+// the document always covers the whole API.
+func (s *Spec) Build(routes gin.RoutesInfo) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.doc != nil {
+		return s.doc, nil
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	paths := map[string]map[string]interface{}{}
+	usedIDs := map[string]bool{}
+	for _, route := range routes {
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		if paths[path] == nil {
+			paths[path] = map[string]interface{}{}
+		}
+		paths[path][strings.ToLower(route.Method)] = s.operation(route, usedIDs)
+	}
+
+	doc := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]string{"title": s.Title, "version": s.Version},
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": s.schemas.components,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]string{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+				"apiKey":     map[string]string{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+			},
+		},
+	}
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	s.doc = out
+	return out, nil
+}
+
+// pathParam matches gin path parameters, ":id" and "*filepath"
+var pathParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+func (s *Spec) operation(route gin.RouteInfo, usedIDs map[string]bool) map[string]interface{} {
+	op := s.ops[route.Method+" "+route.Path]
+
+	id := operationID(route)
+	if usedIDs[id] {
+		id += strings.ToUpper(route.Method[:1]) + strings.ToLower(route.Method[1:])
+	}
+	usedIDs[id] = true
+	result := map[string]interface{}{"operationId": id}
+	if op.Summary != "" {
+		result["summary"] = op.Summary
+	}
+	if op.Tag != "" {
+		result["tags"] = []string{op.Tag}
+	}
+
+	params := []map[string]interface{}{}
+	for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+		params = append(params, map[string]interface{}{
+			"name": match[1], "in": "path", "required": true, "schema": &Schema{Type: "string"},
+		})
+	}
+	for _, q := range op.Query {
+		params = append(params, map[string]interface{}{
+			"name": q.Name, "in": "query", "required": q.Required, "description": q.Description, "schema": &Schema{Type: "string"},
+		})
+	}
+	if len(params) > 0 {
+		result["parameters"] = params
+	}
+
+	if op.Request != nil {
+		result["requestBody"] = map[string]interface{}{
+			"required": !op.OptionalBody,
+			"content":  map[string]interface{}{"application/json": map[string]interface{}{"schema": s.schemas.of(op.Request)}},
+		}
+	}
+
+	success := map[string]interface{}{"description": http.StatusText(op.status())}
+	switch {
+	case op.Produces != "":
+		success["content"] = map[string]interface{}{op.Produces: map[string]interface{}{}}
+	case op.Response != nil:
+		success["content"] = map[string]interface{}{"application/json": map[string]interface{}{"schema": s.schemas.of(op.Response)}}
+	}
+	responses := map[string]interface{}{strconv.Itoa(op.status()): success}
+	if op.Request != nil && !op.OptionalBody && s.ValidationError != nil {
+		responses["400"] = s.errorResponse("The request body is invalid", s.ValidationError)
+	}
+	if !op.Public {
+		responses["401"] = s.errorResponse("Missing or invalid credentials", s.Error)
+	}
+	responses["default"] = s.errorResponse("Error", s.Error)
+	result["responses"] = responses
+
+	if op.Public {
+		result["security"] = []map[string][]string{}
+	} else {
+		security := []map[string][]string{{"bearerAuth": {}}}
+		if s.APIKeyScope != nil {
+			if scope, ok := s.APIKeyScope(route.Method, route.Path); ok {
+				security = append(security, map[string][]string{"apiKey": {}})
+				result["x-api-key-scope"] = scope
+			}
+		}
+		result["security"] = security
+	}
+	return result
+}
+
+func (s *Spec) errorResponse(description string, body interface{}) map[string]interface{} {
+	response := map[string]interface{}{"description": description}
+	if body != nil {
+		response["content"] = map[string]interface{}{"application/json": map[string]interface{}{"schema": s.schemas.of(body)}}
+	}
+	return response
+}
+
+func (op Operation) status() int {
+	if op.Status == 0 {
+		return http.StatusOK
+	}
+	return op.Status
+}
+
+// operationID names an operation after its handler, e.g. "SkillBridge/controller.GetProfile"
+// becomes "GetProfile". Anonymous handlers are named after the route.
+func operationID(route gin.RouteInfo) string {
+	name := route.Handler[strings.LastIndex(route.Handler, ".")+1:]
+	if !strings.HasPrefix(name, "func") {
+		return strings.TrimSuffix(name, "-fm")
+	}
+	id := strings.ToLower(route.Method)
+	for _, part := range strings.FieldsFunc(route.Path, func(r rune) bool { return r == '/' || r == '-' || r == ':' || r == '*' || r == '.' }) {
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return id
+}
@@ -0,0 +1,233 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// cronKeyPrefix marks the unique keys of recurring runs
+const cronKeyPrefix = "cron:"
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // bit sets
+	domAny, dowAny                bool
+	every                         time.Duration
+}
+
+type recurring struct {
+	name     string
+	jobType  string
+	payload  interface{}
+	schedule Schedule
+	last     time.Time
+}
+
+var (
+	cronMu    sync.Mutex
+	recurrent []*recurring
+)
+
+// Every registers a recurring job. spec is a five-field cron expression
+// ("*/15 * * * *", "30 3 * * 1-5") in server local time, or "@every 10m",
+// "@hourly" or "@daily". Each run is enqueued once even with several servers.
+func Every(name, spec, jobType string, payload interface{}) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+	cronMu.Lock()
+	defer cronMu.Unlock()
+	recurrent = append(recurrent, &recurring{name: name, jobType: jobType, payload: payload, schedule: schedule})
+	return nil
+}
+
+// schedule enqueues recurring jobs as they come due until ctx is cancelled.
+// Runs missed while the server was down are skipped, not caught up.
+func schedule(ctx context.Context, db *gorm.DB) {
+	start := time.Now()
+	cronMu.Lock()
+	for _, r := range recurrent {
+		r.last = start
+	}
+	cronMu.Unlock()
+
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			cronMu.Lock()
+			for _, r := range recurrent {
+				due := r.schedule.Next(r.last)
+				if due.After(now) {
+					continue
+				}
+				// The key names the slot, so only one server enqueues each run
+				key := fmt.Sprintf("%s%s:%d", cronKeyPrefix, r.name, due.Unix())
+				if _, err := Enqueue(db, r.jobType, r.payload, Unique(key), At(due)); err != nil && !errors.Is(err, ErrDuplicate) {
+					slog.Error("queue: failed to enqueue recurring job", "job", r.name, "error", err)
+					continue
+				}
+				r.last = due
+				// Skip any further slots missed while this loop was busy
+				for next := r.schedule.Next(r.last); !next.After(now); next = r.schedule.Next(r.last) {
+					r.last = next
+				}
+			}
+			cronMu.Unlock()
+		}
+	}
+}
+
+// ParseSchedule parses a cron expression or @-descriptor
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	switch spec {
+	case "@hourly":
+		spec = "0 * * * *"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	}
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || d < time.Second {
+			return Schedule{}, fmt.Errorf("queue: invalid interval in %q", spec)
+		}
+		return Schedule{every: d}, nil
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("queue: cron expression %q must have 5 fields", spec)
+	}
+	var s Schedule
+	var err error
+	if s.minute, err = parseField(fields[0], 0, 59); err != nil {
+		return s, err
+	}
+	if s.hour, err = parseField(fields[1], 0, 23); err != nil {
+		return s, err
+	}
+	if s.dom, err = parseField(fields[2], 1, 31); err != nil {
+		return s, err
+	}
+	if s.month, err = parseField(fields[3], 1, 12); err != nil {
+		return s, err
+	}
+	if s.dow, err = parseField(fields[4], 0, 7); err != nil {
+		return s, err
+	}
+	if s.dow&(1<<7) != 0 { // 7 is also Sunday
+		s.dow |= 1
+	}
+	s.domAny = fields[2] == "*"
+	s.dowAny = fields[4] == "*"
+	return s, nil
+}
+
+// parseField reads one cron field: *, n, a-b, lists and /step
+func parseField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("queue: invalid step in cron field %q", field)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			a, err1 := strconv.Atoi(bounds[0])
+			b, err2 := strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("queue: invalid range in cron field %q", field)
+			}
+			lo, hi = a, b
+		default:
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return 0, fmt.Errorf("queue: invalid value in cron field %q", field)
+			}
+			lo = n
+			if step == 1 {
+				hi = n
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("queue: cron field %q is out of range %d-%d", field, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Next returns the first time strictly after t that matches the schedule
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		// Aligned to the epoch so every server agrees on the slots
+		return t.Truncate(s.every).Add(s.every)
+	}
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return limit
+}
+
+// dayMatches follows cron's rule: when both day fields are restricted, either may match
+func (s Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
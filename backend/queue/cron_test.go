@@ -0,0 +1,67 @@
+package queue
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleRejectsInvalidSpecs(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@every",
+		"@every 500ms",
+		"@every soon",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2024, time.January, 10, 14, 37, 20, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", from, time.Date(2024, 1, 10, 14, 38, 0, 0, time.UTC)},
+		{"*/15 * * * *", from, time.Date(2024, 1, 10, 14, 45, 0, 0, time.UTC)},
+		{"0,30 9-17 * * *", from, time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)},
+		{"@hourly", from, time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)},
+		{"@daily", from, time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", from, time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", from, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * 7", from, time.Date(2024, 1, 14, 2, 30, 0, 0, time.UTC)}, // 7 is Sunday
+		{"0 9 * * 1-5", time.Date(2024, 1, 12, 9, 0, 0, 0, time.UTC), time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", from, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matches
+		{"0 0 20 * 5", from, time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"@every 10m", from, time.Date(2024, 1, 10, 14, 40, 0, 0, time.UTC)},
+		{"@every 1h", time.Date(2024, 1, 10, 14, 0, 0, 0, time.UTC), time.Date(2024, 1, 10, 15, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := s.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%q.Next(%s) = %s, want %s", tt.spec, tt.from.Format(time.RFC3339), got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+		}
+	}
+}
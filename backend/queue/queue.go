@@ -0,0 +1,169 @@
+// Package queue is a small job queue that runs inside the server process.
+// Jobs are rows in the background_jobs table, so they survive restarts and
+// several server instances can share the work. Failed jobs are retried with
+// exponential backoff, and recurring jobs are scheduled with cron expressions.
+//
+// Handlers are registered by type at startup, jobs are added with Enqueue
+// (inside a transaction if needed), and Start runs the workers.
+package queue
+
+import (
+	"SkillBridge/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Handler does the work for one job. Returning an error schedules a retry
+// unless the error is wrapped with Permanent.
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Definition describes how jobs of one type are run
+type Definition struct {
+	Handler     Handler
+	MaxAttempts int           // default 5
+	Timeout     time.Duration // default 2 minutes
+}
+
+const (
+	defaultMaxAttempts = 5
+	defaultTimeout     = 2 * time.Minute
+)
+
+var (
+	mu          sync.RWMutex
+	definitions = map[string]Definition{}
+	wake        = make(chan struct{}, 1)
+)
+
+// ErrDuplicate is returned by Enqueue when an unfinished job already has the same unique key
+var ErrDuplicate = errors.New("queue: a job with this unique key is already pending")
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error as not worth retrying, e.g. a malformed payload
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	return errors.As(err, &permanentError{})
+}
+
+// Register adds the handler for a job type. It is meant to be called at startup.
+func Register(jobType string, def Definition) {
+	if def.MaxAttempts <= 0 {
+		def.MaxAttempts = defaultMaxAttempts
+	}
+	if def.Timeout <= 0 {
+		def.Timeout = defaultTimeout
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	definitions[jobType] = def
+}
+
+func definition(jobType string) (Definition, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	def, ok := definitions[jobType]
+	return def, ok
+}
+
+// Option adjusts a job being enqueued
+type Option func(*models.BackgroundJob)
+
+// At runs the job no earlier than t
+func At(t time.Time) Option {
+	return func(job *models.BackgroundJob) { job.RunAt = t }
+}
+
+// After delays the job by d
+func After(d time.Duration) Option {
+	return func(job *models.BackgroundJob) { job.RunAt = time.Now().Add(d) }
+}
+
+// Unique keeps a single unfinished job per key; enqueueing another while one
+// is pending or running returns ErrDuplicate. The key is released when the job finishes.
+func Unique(key string) Option {
+	return func(job *models.BackgroundJob) { job.UniqueKey = &key }
+}
+
+// MaxAttempts overrides the type's retry limit for this job
+func MaxAttempts(n int) Option {
+	return func(job *models.BackgroundJob) { job.MaxAttempts = n }
+}
+
+// Enqueue stores a job for the workers. Pass a transaction as db to enqueue
+// the job only if the surrounding changes commit.
+func Enqueue(db *gorm.DB, jobType string, payload interface{}, opts ...Option) (*models.BackgroundJob, error) {
+	def, ok := definition(jobType)
+	if !ok {
+		return nil, fmt.Errorf("queue: no handler registered for %q", jobType)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("queue: encoding %s payload: %w", jobType, err)
+	}
+
+	job := &models.BackgroundJob{
+		Type:        jobType,
+		Payload:     raw,
+		Status:      models.JobPending,
+		RunAt:       time.Now(),
+		MaxAttempts: def.MaxAttempts,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(job)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrDuplicate
+	}
+
+	// Let an idle worker pick it up straight away
+	if !job.RunAt.After(time.Now()) {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+	return job, nil
+}
+
+// Retry puts a failed job back in the queue with a fresh set of attempts
+func Retry(db *gorm.DB, id uint) error {
+	result := db.Model(&models.BackgroundJob{}).
+		Where("id = ? AND status = ?", id, models.JobFailed).
+		Updates(map[string]interface{}{
+			"status":      models.JobPending,
+			"attempts":    0,
+			"run_at":      time.Now(),
+			"finished_at": nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("queue: only failed jobs can be retried")
+	}
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
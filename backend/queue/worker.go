@@ -0,0 +1,226 @@
+package queue
+
+import (
+	"SkillBridge/models"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	pollInterval  = 5 * time.Second
+	sweepInterval = time.Minute
+	maxBackoff    = time.Hour
+	baseBackoff   = 30 * time.Second
+)
+
+// Runner is a set of running workers; cancel the context given to Start and
+// call Wait to let in-flight jobs finish
+type Runner struct {
+	wg sync.WaitGroup
+}
+
+// Wait blocks until every worker and the scheduler have stopped
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+// Start launches the given number of workers plus the recurring job scheduler
+func Start(ctx context.Context, db *gorm.DB, workers int) *Runner {
+	if workers <= 0 {
+		workers = 1
+	}
+	host, _ := os.Hostname()
+	r := &Runner{}
+
+	for i := 0; i < workers; i++ {
+		name := fmt.Sprintf("%s-%d-%d", host, os.Getpid(), i)
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			work(ctx, db, name)
+		}()
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		schedule(ctx, db)
+	}()
+	return r
+}
+
+// work claims and runs jobs until ctx is cancelled
+func work(ctx context.Context, db *gorm.DB, name string) {
+	sweep := time.NewTicker(sweepInterval)
+	defer sweep.Stop()
+	for {
+		// Drain everything that is due before sleeping
+		for ctx.Err() == nil {
+			job, err := claim(db, name)
+			if err != nil {
+				slog.Error("queue: failed to claim a job", "worker", name, "error", err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			run(ctx, db, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		case <-sweep.C:
+			recoverStale(db)
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// claim takes the oldest due job. The status check in the update makes the
+// claim safe when several workers or servers race for the same row.
+func claim(db *gorm.DB, name string) (*models.BackgroundJob, error) {
+	for tries := 0; tries < 3; tries++ {
+		var job models.BackgroundJob
+		err := db.Where("status = ? AND run_at <= ?", models.JobPending, time.Now()).
+			Order("run_at ASC, id ASC").
+			First(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		result := db.Model(&models.BackgroundJob{}).
+			Where("id = ? AND status = ?", job.ID, models.JobPending).
+			Updates(map[string]interface{}{
+				"status":    models.JobRunning,
+				"locked_by": name,
+				"locked_at": now,
+				"attempts":  gorm.Expr("attempts + 1"),
+			})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			job.Status = models.JobRunning
+			job.LockedBy = name
+			job.LockedAt = &now
+			job.Attempts++
+			return &job, nil
+		}
+		// Another worker got there first; try the next one
+	}
+	return nil, nil
+}
+
+// run executes a claimed job and records the outcome
+func run(ctx context.Context, db *gorm.DB, job *models.BackgroundJob) {
+	def, ok := definition(job.Type)
+	var err error
+	if !ok {
+		err = Permanent(fmt.Errorf("no handler registered for %q", job.Type))
+	} else {
+		err = invoke(ctx, def, job)
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"locked_by": "", "locked_at": nil}
+	switch {
+	case err == nil:
+		updates["status"] = models.JobSucceeded
+		updates["finished_at"] = now
+		updates["last_error"] = ""
+		updates["unique_key"] = releasedKey(job)
+	case job.Attempts >= job.MaxAttempts || IsPermanent(err):
+		slog.Error("queue: job failed permanently", "job_id", job.ID, "type", job.Type, "attempts", job.Attempts, "error", err)
+		updates["status"] = models.JobFailed
+		updates["finished_at"] = now
+		updates["last_error"] = err.Error()
+		updates["unique_key"] = releasedKey(job)
+	default:
+		delay := backoff(job.Attempts)
+		slog.Warn("queue: job failed, retrying", "job_id", job.ID, "type", job.Type, "attempt", job.Attempts, "retry_in", delay.Round(time.Second), "error", err)
+		updates["status"] = models.JobPending
+		updates["run_at"] = now.Add(delay)
+		updates["last_error"] = err.Error()
+	}
+
+	// Record the result even if shutdown has begun
+	if err := db.Model(&models.BackgroundJob{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+		slog.Error("queue: failed to record job result", "job_id", job.ID, "error", err)
+	}
+}
+
+// invoke calls the handler with the job's timeout, turning panics into errors
+func invoke(ctx context.Context, def Definition, job *models.BackgroundJob) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	// Jobs already started are allowed to finish during shutdown, up to their timeout
+	jobCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), def.Timeout)
+	defer cancel()
+	return def.Handler(jobCtx, job.Payload)
+}
+
+// releasedKey frees a finished job's unique key so the same work can be queued
+// again. Keys of recurring runs are kept: they record that the run happened.
+func releasedKey(job *models.BackgroundJob) interface{} {
+	if job.UniqueKey != nil && strings.HasPrefix(*job.UniqueKey, cronKeyPrefix) {
+		return *job.UniqueKey
+	}
+	return nil
+}
+
+// backoff grows exponentially from 30s, capped at an hour, with ±20% jitter
+func backoff(attempt int) time.Duration {
+	d := time.Duration(float64(baseBackoff) * math.Pow(2, float64(attempt-1)))
+	if d > maxBackoff || d <= 0 {
+		d = maxBackoff
+	}
+	jitter := 0.8 + rand.Float64()*0.4
+	return time.Duration(float64(d) * jitter)
+}
+
+// recoverStale returns jobs to the queue whose worker stopped without
+// reporting back, e.g. because the server crashed mid-job
+func recoverStale(db *gorm.DB) {
+	var running []models.BackgroundJob
+	if err := db.Where("status = ?", models.JobRunning).Find(&running).Error; err != nil {
+		return
+	}
+	for _, job := range running {
+		timeout := defaultTimeout
+		if def, ok := definition(job.Type); ok {
+			timeout = def.Timeout
+		}
+		if job.LockedAt == nil || time.Since(*job.LockedAt) < 2*timeout+time.Minute {
+			continue
+		}
+		slog.Warn("queue: requeueing abandoned job", "job_id", job.ID, "type", job.Type, "worker", job.LockedBy)
+		db.Model(&models.BackgroundJob{}).
+			Where("id = ? AND status = ?", job.ID, models.JobRunning).
+			Updates(map[string]interface{}{
+				"status":     models.JobPending,
+				"run_at":     time.Now(),
+				"locked_by":  "",
+				"locked_at":  nil,
+				"last_error": "worker stopped before the job finished",
+			})
+	}
+}
@@ -0,0 +1,156 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"SkillBridge/models"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{100, time.Hour},
+		{1000, time.Hour},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 50; i++ {
+			d := backoff(tt.attempt)
+			if lo, hi := tt.base*8/10, tt.base*12/10; d < lo || d > hi {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", tt.attempt, d, lo, hi)
+			}
+		}
+	}
+}
+
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to :memory: is a separate database
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(&models.BackgroundJob{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func reload(t *testing.T, db *gorm.DB, id uint) models.BackgroundJob {
+	t.Helper()
+	var job models.BackgroundJob
+	if err := db.First(&job, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestJobLifecycle(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+
+	var calls []int
+	outcomes := []error{errors.New("temporary outage"), Permanent(errors.New("bad payload")), nil}
+	Register("test_lifecycle", Definition{
+		MaxAttempts: 5,
+		Handler: func(ctx context.Context, payload json.RawMessage) error {
+			var p struct{ N int }
+			if err := json.Unmarshal(payload, &p); err != nil {
+				return err
+			}
+			calls = append(calls, p.N)
+			err := outcomes[0]
+			outcomes = outcomes[1:]
+			return err
+		},
+	})
+
+	job, err := Enqueue(db, "test_lifecycle", map[string]int{"N": 7}, Unique("lifecycle"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Enqueue(db, "test_lifecycle", map[string]int{"N": 8}, Unique("lifecycle")); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("second Enqueue with the same key: err = %v, want ErrDuplicate", err)
+	}
+
+	// First attempt fails and is rescheduled with a backoff
+	claimed, err := claim(db, "w1")
+	if err != nil || claimed == nil || claimed.ID != job.ID {
+		t.Fatalf("claim() = %v, %v; want job %d", claimed, err, job.ID)
+	}
+	if again, _ := claim(db, "w2"); again != nil {
+		t.Fatalf("claimed running job %d twice", again.ID)
+	}
+	run(ctx, db, claimed)
+	got := reload(t, db, job.ID)
+	if got.Status != models.JobPending || got.Attempts != 1 || got.LastError != "temporary outage" || got.LockedBy != "" {
+		t.Fatalf("after a failed attempt: %+v", got)
+	}
+	if !got.RunAt.After(time.Now().Add(20 * time.Second)) {
+		t.Fatalf("retry scheduled at %s, want a backoff", got.RunAt)
+	}
+	if again, _ := claim(db, "w1"); again != nil {
+		t.Fatal("claimed a job before its retry was due")
+	}
+
+	// Second attempt fails permanently despite attempts remaining
+	if err := db.Model(&got).Update("run_at", time.Now().Add(-time.Second)).Error; err != nil {
+		t.Fatal(err)
+	}
+	claimed, err = claim(db, "w1")
+	if err != nil || claimed == nil {
+		t.Fatalf("claim() = %v, %v; want the due retry", claimed, err)
+	}
+	run(ctx, db, claimed)
+	got = reload(t, db, job.ID)
+	if got.Status != models.JobFailed || got.Attempts != 2 || got.LastError != "bad payload" || got.FinishedAt == nil {
+		t.Fatalf("after a permanent failure: %+v", got)
+	}
+	if got.UniqueKey != nil {
+		t.Fatalf("unique key %q kept after the job failed", *got.UniqueKey)
+	}
+
+	// Retry requeues it with fresh attempts, and then it succeeds
+	if err := Retry(db, job.ID); err != nil {
+		t.Fatal(err)
+	}
+	got = reload(t, db, job.ID)
+	if got.Status != models.JobPending || got.Attempts != 0 || got.FinishedAt != nil {
+		t.Fatalf("after Retry: %+v", got)
+	}
+	if err := Retry(db, job.ID); err == nil {
+		t.Fatal("Retry of a pending job succeeded")
+	}
+	claimed, err = claim(db, "w1")
+	if err != nil || claimed == nil {
+		t.Fatalf("claim() = %v, %v; want the retried job", claimed, err)
+	}
+	run(ctx, db, claimed)
+	got = reload(t, db, job.ID)
+	if got.Status != models.JobSucceeded || got.Attempts != 1 || got.LastError != "" {
+		t.Fatalf("after success: %+v", got)
+	}
+
+	if len(calls) != 3 || calls[0] != 7 {
+		t.Fatalf("handler calls = %v, want three calls with N=7", calls)
+	}
+}
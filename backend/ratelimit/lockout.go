@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Lockout blocks a key, such as an account, after repeated failures. Once
+// Threshold failures happen in a row, each further failure locks the key for
+// twice as long as the one before, starting at Base and capped at Max.
+type Lockout struct {
+	Store     Store // nil uses the default store
+	Prefix    string
+	Threshold int
+	Window    time.Duration // failures further apart than this start a new run; keep it above Max
+	Base      time.Duration
+	Max       time.Duration
+}
+
+// Locked returns how long key stays locked, or zero when it is not
+func (l *Lockout) Locked(ctx context.Context, key string) (time.Duration, error) {
+	now := time.Now()
+	until, err := storeOrDefault(l.Store).LockedUntil(ctx, l.Prefix+key, now)
+	if err != nil || until.IsZero() {
+		return 0, err
+	}
+	return until.Sub(now), nil
+}
+
+// Fail records a failure for key and returns how long it is now locked, if at all
+func (l *Lockout) Fail(ctx context.Context, key string) (time.Duration, error) {
+	store := storeOrDefault(l.Store)
+	now := time.Now()
+	count, err := store.Fail(ctx, l.Prefix+key, l.Window, now)
+	if err != nil || count < l.Threshold {
+		return 0, err
+	}
+	lock := l.Base
+	for i := l.Threshold; i < count && lock < l.Max; i++ {
+		lock *= 2
+	}
+	if lock > l.Max {
+		lock = l.Max
+	}
+	return lock, store.Lock(ctx, l.Prefix+key, now.Add(lock))
+}
+
+// Reset forgets the failures for key, e.g. after a successful login
+func (l *Lockout) Reset(ctx context.Context, key string) error {
+	return storeOrDefault(l.Store).Reset(ctx, l.Prefix+key)
+}
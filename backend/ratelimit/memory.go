@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops state that has expired
+const sweepInterval = time.Minute
+
+// MemoryStore keeps state in the process. Limits are per instance.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	failures  map[string]*failures
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // when the bucket will have refilled; it can be dropped after
+}
+
+type failures struct {
+	count       int
+	last        time.Time
+	window      time.Duration
+	lockedUntil time.Time
+}
+
+// NewMemoryStore returns an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, failures: map[string]*failures{}}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+	tokens, result := take(b.tokens, b.updated, limit, now)
+	b.tokens, b.updated, b.full = tokens, now, now.Add(result.Reset)
+	return result, nil
+}
+
+func (s *MemoryStore) Fail(_ context.Context, key string, window time.Duration, now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	f, ok := s.failures[key]
+	if !ok {
+		f = &failures{}
+		s.failures[key] = f
+	}
+	if now.Sub(f.last) > window {
+		f.count = 0
+	}
+	f.count++
+	f.last = now
+	f.window = window
+	return f.count, nil
+}
+
+func (s *MemoryStore) Lock(_ context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.failures[key]
+	if !ok {
+		f = &failures{last: time.Now()}
+		s.failures[key] = f
+	}
+	f.lockedUntil = until
+	return nil
+}
+
+func (s *MemoryStore) LockedUntil(_ context.Context, key string, now time.Time) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f, ok := s.failures[key]; ok && f.lockedUntil.After(now) {
+		return f.lockedUntil, nil
+	}
+	return time.Time{}, nil
+}
+
+func (s *MemoryStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.failures, key)
+	return nil
+}
+
+// sweep drops full buckets and forgotten failures. The caller holds s.mu.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, f := range s.failures {
+		if now.Sub(f.last) > f.window && !f.lockedUntil.After(now) {
+			delete(s.failures, key)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package ratelimit implements token buckets and progressive lockouts on top
+// of a Store. MemoryStore keeps state in the process; run several instances
+// behind a load balancer with a shared Store so they enforce one limit.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket: Burst requests at once, refilled at Rate per second.
+// The zero Limit means unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute allows n requests a minute, all of which may arrive together
+func PerMinute(n int) Limit { return Limit{Rate: float64(n) / 60, Burst: n} }
+
+// Unlimited reports whether the limit never rejects requests
+func (l Limit) Unlimited() bool { return l.Rate <= 0 || l.Burst <= 0 }
+
+// Window is the time an empty bucket takes to refill
+func (l Limit) Window() time.Duration {
+	if l.Unlimited() {
+		return 0
+	}
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "off"
+	}
+	return fmt.Sprintf("%d per %s", l.Burst, l.Window())
+}
+
+var units = map[string]time.Duration{"s": time.Second, "m": time.Minute, "h": time.Hour}
+
+// ParseLimit reads limits such as "10/m" (ten a minute) or "5/s"; "off" or
+// "0" disables the limit
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if s == "off" || s == "0" {
+		return Limit{}, nil
+	}
+	count, unit, ok := strings.Cut(s, "/")
+	n, err := strconv.Atoi(count)
+	per, known := units[unit]
+	if !ok || err != nil || n < 0 || !known {
+		return Limit{}, fmt.Errorf("ratelimit: invalid limit %q, want e.g. 10/m", s)
+	}
+	return Limit{Rate: float64(n) / per.Seconds(), Burst: n}, nil
+}
+
+// Result describes the bucket after a Take
+type Result struct {
+	Allowed    bool
+	Limit      int           // bucket size
+	Remaining  int           // whole tokens left
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next token, when not allowed
+}
+
+// Store keeps bucket and lockout state by key. Implementations must be safe
+// for concurrent use.
+type Store interface {
+	// Take removes a token from the bucket at key, creating it full if needed
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+	// Fail records a failure at key and returns the number of failures in a
+	// row. The count starts again when the last failure is older than window.
+	Fail(ctx context.Context, key string, window time.Duration, now time.Time) (int, error)
+	// Lock blocks key until the given time
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil returns the time a lock on key ends, or the zero time
+	LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, error)
+	// Reset clears the failures and lock at key
+	Reset(ctx context.Context, key string) error
+}
+
+var defaultStore Store = NewMemoryStore()
+
+// DefaultStore is used by limiters and lockouts that don't name a store
+func DefaultStore() Store { return defaultStore }
+
+// SetDefaultStore replaces the default store, e.g. with a shared one. Call it
+// before serving requests.
+func SetDefaultStore(s Store) { defaultStore = s }
+
+func storeOrDefault(s Store) Store {
+	if s != nil {
+		return s
+	}
+	return defaultStore
+}
+
+// take is the token bucket arithmetic shared by stores: it refills tokens,
+// last updated at updated, and removes one if it can
+func take(tokens float64, updated time.Time, limit Limit, now time.Time) (float64, Result) {
+	if elapsed := now.Sub(updated).Seconds(); elapsed > 0 {
+		tokens = math.Min(float64(limit.Burst), tokens+elapsed*limit.Rate)
+	}
+	result := Result{Limit: limit.Burst}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+	result.Remaining = int(tokens)
+	result.Reset = seconds((float64(limit.Burst) - tokens) / limit.Rate)
+	return tokens, result
+}
+
+func seconds(s float64) time.Duration { return time.Duration(s * float64(time.Second)) }
@@ -0,0 +1,527 @@
+package resume
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrNoText is returned when a file holds no text that can be read, such as a
+// scanned PDF or one whose fonts use custom encodings
+var ErrNoText = errors.New("resume: no readable text found in file")
+
+// maxDecodedBytes caps how much a compressed file may expand to while its text
+// is extracted, across all of its parts, so a small upload can't exhaust memory
+const maxDecodedBytes = 20 << 20
+
+// errTooLarge is returned when a file expands past maxDecodedBytes
+var errTooLarge = errors.New("resume: file expands to too much data to read")
+
+// MIME types accepted by ExtractText
+const (
+	ContentTypePDF  = "application/pdf"
+	ContentTypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	ContentTypeText = "text/plain"
+)
+
+// ExtractText returns the plain text of a PDF, DOCX or text file, one line per visual line
+func ExtractText(contentType string, data []byte) (string, error) {
+	var text string
+	var err error
+	switch contentType {
+	case ContentTypePDF:
+		text, err = pdfText(data)
+	case ContentTypeDOCX:
+		text, err = docxText(data)
+	case ContentTypeText:
+		if !utf8.Valid(data) {
+			return "", errors.New("resume: text file is not valid UTF-8")
+		}
+		text = string(data)
+	default:
+		return "", ErrUnsupported
+	}
+	if err != nil {
+		return "", err
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	letters := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if letters < 20 {
+		return "", ErrNoText
+	}
+	return text, nil
+}
+
+// docxText reads the paragraphs of word/document.xml
+func docxText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", errors.New("resume: file is not a valid DOCX document")
+	}
+	for _, f := range archive.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+
+		var out strings.Builder
+		decoder := xml.NewDecoder(io.LimitReader(rc, maxDecodedBytes))
+		inText := false
+		for {
+			token, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", errors.New("resume: DOCX document is malformed")
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				switch t.Name.Local {
+				case "t":
+					inText = true
+				case "tab":
+					out.WriteByte('\t')
+				case "br", "cr":
+					out.WriteByte('\n')
+				}
+			case xml.EndElement:
+				switch t.Name.Local {
+				case "t":
+					inText = false
+				case "p":
+					out.WriteByte('\n')
+				}
+			case xml.CharData:
+				if inText {
+					out.Write(t)
+				}
+			}
+		}
+		return out.String(), nil
+	}
+	return "", errors.New("resume: file is not a valid DOCX document")
+}
+
+// pdfText pulls the text shown by every content stream in the file. It handles
+// uncompressed and Flate-compressed streams with simple (WinAnsi/Latin-1) fonts,
+// which covers resumes exported by word processors and our own renderer.
+func pdfText(data []byte) (string, error) {
+	if !bytes.HasPrefix(data, []byte("%PDF")) {
+		return "", errors.New("resume: file is not a valid PDF")
+	}
+
+	var out strings.Builder
+	rest := data
+	budget := int64(maxDecodedBytes)
+	for {
+		start := bytes.Index(rest, []byte("stream"))
+		if start < 0 {
+			break
+		}
+		// Skip the "stream" at the end of "endstream"
+		if start >= 3 && string(rest[start-3:start]) == "end" {
+			rest = rest[start+6:]
+			continue
+		}
+		dict := rest[:start]
+		if obj := bytes.LastIndex(dict, []byte(" obj")); obj >= 0 {
+			dict = dict[obj:]
+		}
+		body := rest[start+6:]
+		body = bytes.TrimPrefix(body, []byte("\r"))
+		body = bytes.TrimPrefix(body, []byte("\n"))
+		end := bytes.Index(body, []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		content := body[:end]
+		rest = body[end+9:]
+
+		if bytes.Contains(dict, []byte("/Image")) || bytes.Contains(dict, []byte("/Length1")) {
+			continue
+		}
+		if bytes.Contains(dict, []byte("/Filter")) {
+			if !bytes.Contains(dict, []byte("/FlateDecode")) {
+				continue
+			}
+			r, err := zlib.NewReader(bytes.NewReader(content))
+			if err != nil {
+				continue
+			}
+			// Read one byte past the budget to tell a stream that fits exactly from one that doesn't
+			decoded, _ := io.ReadAll(io.LimitReader(r, budget+1))
+			r.Close()
+			if int64(len(decoded)) > budget {
+				return "", errTooLarge
+			}
+			budget -= int64(len(decoded))
+			content = decoded
+		}
+		if !bytes.Contains(content, []byte("BT")) {
+			continue
+		}
+		showPDFText(&out, content)
+	}
+	return out.String(), nil
+}
+
+// showPDFText interprets the text operators of one content stream. Fragments
+// on the same baseline are joined left to right, since generators often draw
+// things like bullets and right-aligned dates after the text they sit beside.
+func showPDFText(out *strings.Builder, content []byte) {
+	type fragment struct {
+		x    float64
+		text string
+	}
+	var operands []pdfToken
+	var line []fragment
+	var lineX, lineY, leading float64
+	currentY := math.NaN()
+
+	flush := func() {
+		if len(line) == 0 {
+			return
+		}
+		sort.SliceStable(line, func(i, j int) bool { return line[i].x < line[j].x })
+		parts := make([]string, len(line))
+		for i, f := range line {
+			parts[i] = strings.TrimSpace(f.text)
+		}
+		out.WriteString(strings.Join(parts, " "))
+		out.WriteByte('\n')
+		line = line[:0]
+	}
+	show := func(s string) {
+		if strings.TrimSpace(s) == "" {
+			return
+		}
+		if !math.IsNaN(currentY) && math.Abs(lineY-currentY) > 0.5 {
+			flush()
+		}
+		currentY = lineY
+		line = append(line, fragment{x: lineX, text: s})
+	}
+	number := func(i int) float64 {
+		if i < 0 || i >= len(operands) {
+			return 0
+		}
+		return operands[i].num
+	}
+
+	lex := &pdfLexer{data: content}
+	for {
+		tok, ok := lex.next()
+		if !ok {
+			break
+		}
+		if tok.kind != pdfOperator {
+			operands = append(operands, tok)
+			continue
+		}
+		n := len(operands)
+		switch tok.text {
+		case "BT":
+			lineX, lineY = 0, 0
+		case "Td", "TD":
+			lineX += number(n - 2)
+			lineY += number(n - 1)
+			if tok.text == "TD" {
+				leading = -number(n - 1)
+			}
+		case "Tm":
+			lineX, lineY = number(n-2), number(n-1)
+		case "TL":
+			leading = number(n - 1)
+		case "T*":
+			lineY -= math.Max(leading, 1)
+		case "Tj", "TJ":
+			if n > 0 {
+				show(operands[n-1].text)
+			}
+		case "'", "\"":
+			lineY -= math.Max(leading, 1)
+			if n > 0 {
+				show(operands[n-1].text)
+			}
+		}
+		operands = operands[:0]
+	}
+	flush()
+}
+
+type pdfTokenKind int
+
+const (
+	pdfNumber pdfTokenKind = iota
+	pdfString
+	pdfArray // a TJ array, flattened to its text
+	pdfName
+	pdfOperator
+)
+
+type pdfToken struct {
+	kind pdfTokenKind
+	text string
+	num  float64
+}
+
+// pdfLexer tokenises a content stream just far enough to read text operators
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func (l *pdfLexer) next() (pdfToken, bool) {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		case isPDFSpace(c):
+			l.pos++
+		case c == '(':
+			return pdfToken{kind: pdfString, text: decodePDFBytes(l.literal())}, true
+		case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+			l.skipDict()
+		case c == '<':
+			return pdfToken{kind: pdfString, text: decodePDFBytes(l.hex())}, true
+		case c == '[':
+			return pdfToken{kind: pdfArray, text: l.array()}, true
+		case c == '/':
+			start := l.pos
+			l.pos++
+			for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+				l.pos++
+			}
+			return pdfToken{kind: pdfName, text: string(l.data[start:l.pos])}, true
+		case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+			start := l.pos
+			l.pos++
+			for l.pos < len(l.data) && (l.data[l.pos] == '.' || (l.data[l.pos] >= '0' && l.data[l.pos] <= '9')) {
+				l.pos++
+			}
+			num, _ := strconv.ParseFloat(string(l.data[start:l.pos]), 64)
+			return pdfToken{kind: pdfNumber, num: num}, true
+		case isPDFDelimiter(c):
+			l.pos++
+		default:
+			start := l.pos
+			for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+				l.pos++
+			}
+			op := string(l.data[start:l.pos])
+			// Inline images hold binary data up to EI
+			if op == "ID" {
+				if end := bytes.Index(l.data[l.pos:], []byte("EI")); end >= 0 {
+					l.pos += end + 2
+				} else {
+					l.pos = len(l.data)
+				}
+				continue
+			}
+			return pdfToken{kind: pdfOperator, text: op}, true
+		}
+	}
+	return pdfToken{}, false
+}
+
+// literal reads a (string) with nested parentheses and escapes
+func (l *pdfLexer) literal() []byte {
+	var out []byte
+	depth := 0
+	l.pos++ // opening paren
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+			out = append(out, c)
+		case ')':
+			if depth == 0 {
+				return out
+			}
+			depth--
+			out = append(out, c)
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// hex reads a <hex string>
+func (l *pdfLexer) hex() []byte {
+	l.pos++
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			return nil
+		}
+		out = append(out, byte(v))
+	}
+	return out
+}
+
+// array flattens a TJ array: strings are joined, and large negative kerning
+// (a gap wider than a quarter em) becomes a space
+func (l *pdfLexer) array() string {
+	l.pos++
+	var out strings.Builder
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == ']' {
+			l.pos++
+			break
+		}
+		tok, ok := l.next()
+		if !ok {
+			break
+		}
+		switch tok.kind {
+		case pdfString:
+			out.WriteString(tok.text)
+		case pdfNumber:
+			if tok.num < -250 && !strings.HasSuffix(out.String(), " ") {
+				out.WriteByte(' ')
+			}
+		}
+	}
+	return out.String()
+}
+
+func (l *pdfLexer) skipDict() {
+	depth := 0
+	for l.pos+1 < len(l.data) {
+		if l.data[l.pos] == '<' && l.data[l.pos+1] == '<' {
+			depth++
+			l.pos += 2
+			continue
+		}
+		if l.data[l.pos] == '>' && l.data[l.pos+1] == '>' {
+			depth--
+			l.pos += 2
+			if depth == 0 {
+				return
+			}
+			continue
+		}
+		l.pos++
+	}
+	l.pos = len(l.data)
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+// winAnsiHigh maps the 0x80-0x9F range of WinAnsiEncoding; other bytes are Latin-1
+var winAnsiHigh = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡',
+	0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž', 0x91: '‘', 0x92: '’',
+	0x93: '“', 0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—', 0x99: '™', 0x9A: 'š',
+	0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+// decodePDFBytes turns string bytes into text. Strings that are mostly control
+// characters come from fonts with custom encodings and are dropped.
+func decodePDFBytes(raw []byte) string {
+	if len(raw) >= 2 && raw[0] == 0xFE && raw[1] == 0xFF {
+		var out []rune
+		for i := 2; i+1 < len(raw); i += 2 {
+			out = append(out, rune(raw[i])<<8|rune(raw[i+1]))
+		}
+		return string(out)
+	}
+
+	var out strings.Builder
+	control := 0
+	for _, b := range raw {
+		switch {
+		case b == '\t' || b == '\n' || b == '\r':
+			out.WriteByte(' ')
+		case b < 32 || b == 127:
+			control++
+		case b < 128:
+			out.WriteByte(b)
+		case b < 0xA0:
+			if r, ok := winAnsiHigh[b]; ok {
+				out.WriteRune(r)
+			} else {
+				control++
+			}
+		default:
+			out.WriteRune(rune(b))
+		}
+	}
+	if control*2 > len(raw) {
+		return ""
+	}
+	return out.String()
+}
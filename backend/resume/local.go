@@ -0,0 +1,395 @@
+package resume
+
+import (
+	"SkillBridge/utils"
+	"bytes"
+	"context"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+// Local renders resumes in-process
+type Local struct{}
+
+// style controls the look of one template in both PDF and HTML output
+type style struct {
+	Description    string
+	CenteredHeader bool
+	HeadingRule    bool    // line under section headings
+	HeadingBand    bool    // grey band behind section headings
+	InlineSkills   bool    // skills as one comma-separated paragraph
+	NameSize       float64 // points
+	HeadingSize    float64
+	BodySize       float64
+	Margin         float64
+	Accent         string // CSS colour for the HTML template
+}
+
+var styles = map[string]style{
+	"classic": {
+		Description:    "Centred header with ruled section headings",
+		CenteredHeader: true,
+		HeadingRule:    true,
+		NameSize:       24,
+		HeadingSize:    12,
+		BodySize:       10.5,
+		Margin:         50,
+		Accent:         "#222222",
+	},
+	"modern": {
+		Description: "Left-aligned header with shaded section headings",
+		HeadingBand: true,
+		NameSize:    26,
+		HeadingSize: 12,
+		BodySize:    10.5,
+		Margin:      48,
+		Accent:      "#1f5fbf",
+	},
+	"compact": {
+		Description:  "Dense single page layout for long histories",
+		HeadingRule:  true,
+		InlineSkills: true,
+		NameSize:     18,
+		HeadingSize:  10.5,
+		BodySize:     9,
+		Margin:       36,
+		Accent:       "#333333",
+	},
+}
+
+// DefaultTemplate is used when none is requested
+const DefaultTemplate = "classic"
+
+// Templates lists the local templates with their descriptions
+func Templates() map[string]string {
+	list := make(map[string]string, len(styles))
+	for name, s := range styles {
+		list[name] = s.Description
+	}
+	return list
+}
+
+// TemplateNames returns the template names in a stable order
+func TemplateNames() []string {
+	names := make([]string, 0, len(styles))
+	for name := range styles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (Local) Name() string { return "local" }
+
+func (Local) Formats() []string { return []string{FormatPDF, FormatHTML} }
+
+// Render produces a PDF or HTML resume
+func (Local) Render(ctx context.Context, doc Document, opts Options) (Result, error) {
+	name := opts.Template
+	if name == "" {
+		name = DefaultTemplate
+	}
+	s, ok := styles[name]
+	if !ok {
+		return Result{}, ErrUnsupported
+	}
+
+	switch opts.Format {
+	case "", FormatPDF:
+		return Result{ContentType: "application/pdf", Data: renderPDF(doc, s)}, nil
+	case FormatHTML:
+		data, err := renderHTML(doc, s)
+		if err != nil {
+			return Result{}, err
+		}
+		return Result{ContentType: "text/html; charset=utf-8", Data: data}, nil
+	default:
+		return Result{}, ErrUnsupported
+	}
+}
+
+// dateRange joins start and end dates, omitting whichever is missing
+func dateRange(start, end string) string {
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	switch {
+	case start != "" && end != "":
+		return start + " - " + end
+	case start != "":
+		return start + " - Present"
+	default:
+		return end
+	}
+}
+
+// contactLine joins the non-empty contact details
+func contactLine(doc Document) string {
+	var parts []string
+	for _, part := range append([]string{doc.Email, doc.Phone, doc.Location}, doc.Links...) {
+		if strings.TrimSpace(part) != "" {
+			parts = append(parts, strings.TrimSpace(part))
+		}
+	}
+	return strings.Join(parts, "  |  ")
+}
+
+// skillsText lists the skills on one line
+func skillsText(doc Document, s style) string {
+	if s.InlineSkills {
+		return strings.Join(doc.Skills, ", ")
+	}
+	return strings.Join(doc.Skills, "  •  ")
+}
+
+// pdfWriter lays content out top to bottom, starting new pages as needed
+type pdfWriter struct {
+	doc *utils.PDFDocument
+	s   style
+	y   float64
+}
+
+func (w *pdfWriter) width() float64 {
+	return utils.PDFPageWidth - 2*w.s.Margin
+}
+
+// ensure starts a new page when fewer than height points remain
+func (w *pdfWriter) ensure(height float64) {
+	if w.y-height < w.s.Margin {
+		w.doc.AddPage()
+		w.y = utils.PDFPageHeight - w.s.Margin
+	}
+}
+
+func (w *pdfWriter) text(text string, size float64, bold bool, indent float64) {
+	w.ensure(size * 1.4)
+	w.y -= size * 1.4
+	w.doc.Text(w.s.Margin+indent, w.y, size, bold, text)
+}
+
+func (w *pdfWriter) paragraph(text string, size float64, indent float64) {
+	for _, line := range utils.PDFWrapText(text, size, w.width()-indent) {
+		w.text(line, size, false, indent)
+	}
+}
+
+// row draws left text with right-aligned text on the same line
+func (w *pdfWriter) row(left, right string, size float64, bold bool) {
+	w.text(left, size, bold, 0)
+	if right != "" {
+		x := utils.PDFPageWidth - w.s.Margin - utils.PDFTextWidth(right, size)
+		w.doc.Text(x, w.y, size, false, right)
+	}
+}
+
+func (w *pdfWriter) bullet(text string) {
+	size := w.s.BodySize
+	lines := utils.PDFWrapText(text, size, w.width()-14)
+	for i, line := range lines {
+		w.text(line, size, false, 14)
+		if i == 0 {
+			w.doc.Text(w.s.Margin+4, w.y, size, false, "•")
+		}
+	}
+}
+
+func (w *pdfWriter) heading(title string) {
+	size := w.s.HeadingSize
+	// Keep a heading with at least a couple of lines of its section
+	w.ensure(size*2.2 + w.s.BodySize*3)
+	w.y -= size * 0.8
+	if w.s.HeadingBand {
+		w.doc.Rect(w.s.Margin-4, w.y-size*1.75, w.width()+8, size*1.6, 0, 0.88, true)
+	}
+	w.text(strings.ToUpper(title), size, true, 0)
+	if w.s.HeadingRule {
+		w.doc.Line(w.s.Margin, w.y-4, utils.PDFPageWidth-w.s.Margin, w.y-4, 0.6)
+	}
+	w.y -= 4
+}
+
+func (w *pdfWriter) gap(points float64) {
+	w.y -= points
+}
+
+func renderPDF(doc Document, s style) []byte {
+	w := &pdfWriter{doc: utils.NewPDFDocument(), s: s, y: utils.PDFPageHeight - s.Margin}
+	w.doc.AddPage()
+
+	// Header
+	w.y -= s.NameSize
+	if s.CenteredHeader {
+		w.doc.CenteredText(w.y, s.NameSize, true, doc.Name)
+		if doc.Title != "" {
+			w.y -= s.BodySize * 1.8
+			w.doc.CenteredText(w.y, s.BodySize+2, false, doc.Title)
+		}
+		if contact := contactLine(doc); contact != "" {
+			w.y -= s.BodySize * 1.6
+			w.doc.CenteredText(w.y, s.BodySize-1, false, contact)
+		}
+	} else {
+		w.doc.Text(s.Margin, w.y, s.NameSize, true, doc.Name)
+		if doc.Title != "" {
+			w.text(doc.Title, s.BodySize+2, false, 0)
+		}
+		if contact := contactLine(doc); contact != "" {
+			w.paragraph(contact, s.BodySize-1, 0)
+		}
+	}
+	w.gap(s.BodySize)
+
+	if doc.Bio != "" {
+		w.heading("Summary")
+		w.paragraph(doc.Bio, s.BodySize, 0)
+	}
+
+	if len(doc.Skills) > 0 {
+		w.heading("Skills")
+		w.paragraph(skillsText(doc, s), s.BodySize, 0)
+	}
+
+	if len(doc.Experience) > 0 {
+		w.heading("Experience")
+		for _, e := range doc.Experience {
+			title := e.Position
+			if e.Company != "" {
+				if title != "" {
+					title += ", "
+				}
+				title += e.Company
+			}
+			w.row(title, dateRange(e.Start, e.End), s.BodySize+0.5, true)
+			if e.Location != "" {
+				w.text(e.Location, s.BodySize-1, false, 0)
+			}
+			if e.Summary != "" {
+				w.paragraph(e.Summary, s.BodySize, 0)
+			}
+			for _, h := range e.Highlights {
+				w.bullet(h)
+			}
+			w.gap(s.BodySize * 0.5)
+		}
+	}
+
+	if len(doc.Projects) > 0 {
+		w.heading("Projects")
+		for _, p := range doc.Projects {
+			w.row(p.Name, p.URL, s.BodySize+0.5, true)
+			if len(p.Technologies) > 0 {
+				w.text(strings.Join(p.Technologies, ", "), s.BodySize-1, false, 0)
+			}
+			if p.Description != "" {
+				w.paragraph(p.Description, s.BodySize, 0)
+			}
+			for _, h := range p.Highlights {
+				w.bullet(h)
+			}
+			w.gap(s.BodySize * 0.5)
+		}
+	}
+
+	if len(doc.Education) > 0 {
+		w.heading("Education")
+		for _, e := range doc.Education {
+			w.row(e.Institution, dateRange(e.Start, e.End), s.BodySize+0.5, true)
+			degree := strings.TrimSpace(strings.Join([]string{e.Degree, e.Field}, " "))
+			if e.Degree != "" && e.Field != "" {
+				degree = e.Degree + " in " + e.Field
+			}
+			if degree != "" {
+				w.text(degree, s.BodySize, false, 0)
+			}
+			w.gap(s.BodySize * 0.4)
+		}
+	}
+
+	if len(doc.Certifications) > 0 {
+		w.heading("Certifications")
+		for _, cert := range doc.Certifications {
+			line := cert.Name
+			if cert.Issuer != "" {
+				line += " - " + cert.Issuer
+			}
+			w.row(line, cert.Date, s.BodySize, false)
+			if cert.VerifyURL != "" {
+				w.text("Verify: "+cert.VerifyURL, s.BodySize-1.5, false, 0)
+			}
+		}
+	}
+
+	return w.doc.Bytes()
+}
+
+var htmlTemplate = template.Must(template.New("resume").Funcs(template.FuncMap{
+	"dates": dateRange,
+	"join":  strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Doc.Name}} - Resume</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: {{.Style.BodySize}}pt; color: #222; max-width: 800px; margin: {{.Style.Margin}}px auto; line-height: 1.4; }
+header { text-align: {{if .Style.CenteredHeader}}center{{else}}left{{end}}; margin-bottom: 1em; }
+header h1 { font-size: {{.Style.NameSize}}pt; margin: 0; color: {{.Style.Accent}}; }
+header .title { font-size: 1.2em; margin: 0.2em 0; }
+header .contact { font-size: 0.9em; color: #555; }
+h2 { font-size: {{.Style.HeadingSize}}pt; text-transform: uppercase; letter-spacing: 0.05em; color: {{.Style.Accent}}; margin: 1.2em 0 0.4em;{{if .Style.HeadingRule}} border-bottom: 1px solid #999;{{end}}{{if .Style.HeadingBand}} background: #e0e0e0; padding: 2px 6px;{{end}} }
+.entry { margin-bottom: 0.6em; }
+.row { display: flex; justify-content: space-between; font-weight: bold; }
+.row .date { font-weight: normal; color: #555; }
+.sub { color: #555; font-size: 0.9em; }
+ul { margin: 0.2em 0 0 1.2em; padding: 0; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Doc.Name}}</h1>
+{{with .Doc.Title}}<div class="title">{{.}}</div>{{end}}
+{{with .Contact}}<div class="contact">{{.}}</div>{{end}}
+</header>
+{{with .Doc.Bio}}<section><h2>Summary</h2><p>{{.}}</p></section>{{end}}
+{{with .Skills}}<section><h2>Skills</h2><p>{{.}}</p></section>{{end}}
+{{if .Doc.Experience}}<section><h2>Experience</h2>
+{{range .Doc.Experience}}<div class="entry">
+<div class="row"><span>{{.Position}}{{if and .Position .Company}}, {{end}}{{.Company}}</span><span class="date">{{dates .Start .End}}</span></div>
+{{with .Location}}<div class="sub">{{.}}</div>{{end}}
+{{with .Summary}}<p>{{.}}</p>{{end}}
+{{if .Highlights}}<ul>{{range .Highlights}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</div>{{end}}
+</section>{{end}}
+{{if .Doc.Projects}}<section><h2>Projects</h2>
+{{range .Doc.Projects}}<div class="entry">
+<div class="row"><span>{{.Name}}</span>{{with .URL}}<span class="date"><a href="{{.}}">{{.}}</a></span>{{end}}</div>
+{{if .Technologies}}<div class="sub">{{join .Technologies ", "}}</div>{{end}}
+{{with .Description}}<p>{{.}}</p>{{end}}
+{{if .Highlights}}<ul>{{range .Highlights}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</div>{{end}}
+</section>{{end}}
+{{if .Doc.Education}}<section><h2>Education</h2>
+{{range .Doc.Education}}<div class="entry">
+<div class="row"><span>{{.Institution}}</span><span class="date">{{dates .Start .End}}</span></div>
+<div>{{.Degree}}{{if and .Degree .Field}} in {{end}}{{.Field}}</div>
+</div>{{end}}
+</section>{{end}}
+{{if .Doc.Certifications}}<section><h2>Certifications</h2>
+{{range .Doc.Certifications}}<div class="entry">
+<div class="row"><span>{{.Name}}{{with .Issuer}} - {{.}}{{end}}</span><span class="date">{{.Date}}</span></div>
+{{with .VerifyURL}}<div class="sub">Verify: <a href="{{.}}">{{.}}</a></div>{{end}}
+</div>{{end}}
+</section>{{end}}
+</body>
+</html>
+`))
+
+func renderHTML(doc Document, s style) ([]byte, error) {
+	var buf bytes.Buffer
+	err := htmlTemplate.Execute(&buf, struct {
+		Doc     Document
+		Style   style
+		Contact string
+		Skills  string
+	}{doc, s, contactLine(doc), skillsText(doc, s)})
+	return buf.Bytes(), err
+}
@@ -0,0 +1,513 @@
+package resume
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Section names recognised by Parse
+const (
+	sectionHeader         = ""
+	sectionSummary        = "summary"
+	sectionEducation      = "education"
+	sectionExperience     = "experience"
+	sectionProjects       = "projects"
+	sectionSkills         = "skills"
+	sectionCertifications = "certifications"
+	sectionOther          = "other"
+)
+
+// sectionHeadings lists the heading texts, lower-cased, that start each section
+var sectionHeadings = map[string][]string{
+	sectionSummary:        {"summary", "profile", "about", "about me", "objective", "career objective", "professional summary"},
+	sectionEducation:      {"education", "academic background", "academics", "education and training"},
+	sectionExperience:     {"experience", "work experience", "professional experience", "employment", "employment history", "work history", "internships", "internship experience", "relevant experience"},
+	sectionProjects:       {"projects", "personal projects", "academic projects", "selected projects", "key projects"},
+	sectionSkills:         {"skills", "technical skills", "core skills", "key skills", "technologies", "tools and technologies", "skills and tools"},
+	sectionCertifications: {"certifications", "certificates", "licenses and certifications", "certifications and awards"},
+	sectionOther:          {"awards", "achievements", "languages", "interests", "hobbies", "references", "volunteering", "publications"},
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`\+?\(?\d[\d\s().\-]{7,}\d`)
+	urlPattern   = regexp.MustCompile(`(?i)\b(?:https?://)?(?:www\.)?(?:[a-z0-9\-]+\.)+[a-z]{2,}(?:/[^\s|,;]*)?`)
+
+	month     = `(?:jan|feb|mar|apr|may|jun|jul|aug|sep|sept|oct|nov|dec)[a-z]*\.?`
+	datePoint = `(?:` + month + `\s+\d{4}|\d{1,2}/\d{4}|\d{4})`
+	// dateRangePattern matches "Jan 2022 - Present", "06/2021 – 08/2021", "2019 to 2023" or a lone year
+	dateRangePattern = regexp.MustCompile(`(?i)(` + datePoint + `)\s*(?:-|–|—|to|until)\s*(` + datePoint + `|present|current|now|ongoing)|\b(` + month + `\s+\d{4}|(?:19|20)\d{2})\b`)
+
+	degreePattern = regexp.MustCompile(`(?i)\b(ph\.?\s?d|doctorate|master(?:'?s)?|m\.?\s?sc|m\.?\s?s|m\.?\s?a|m\.?\s?tech|mba|bachelor(?:'?s)?|b\.?\s?sc|b\.?\s?s|b\.?\s?a|b\.?\s?e|b\.?\s?tech|associate(?:'?s)?|diploma|high school)\b\.?(?:\s+(?:of|in)\s+(?:science|arts|engineering|technology))?`)
+	fieldPattern  = regexp.MustCompile(`(?i)\b(?:in|of)\s+([A-Z][A-Za-z&/ ]{2,60}?)(?:\s*[,|(–—\-]|\s+\d|$)`)
+	schoolPattern = regexp.MustCompile(`(?i)\b(university|college|institute|school|academy|polytechnic)\b`)
+
+	bulletPrefix = regexp.MustCompile(`^\s*(?:[•▪◦●○■□‣⁃∙·*\-–—>]|\d+[.)])\s*`)
+	techPrefix   = regexp.MustCompile(`(?i)^(?:tech(?:nologies|nology| stack)?|stack|tools|built with)\s*[:\-–]\s*`)
+	labelPrefix  = regexp.MustCompile(`^[A-Za-z][A-Za-z &/]{1,30}:\s*`)
+	titleAtCo    = regexp.MustCompile(`(?i)^(.+?)\s+(?:at|@)\s+(.+)$`)
+	separators   = regexp.MustCompile(`\s*(?:\||,|–|—| - )\s*`)
+	// skillSeparators leaves "/" alone so "CI/CD" and "TCP/IP" stay whole
+	skillSeparators = regexp.MustCompile(`\s*(?:[,;|•·]|\s{2,})\s*`)
+)
+
+// Parse reads resume text into a Document. It is heuristic: sections are found
+// by their headings, entries by date ranges and bullet points, so fields the
+// parser is unsure of are left empty rather than guessed.
+func Parse(text string) Document {
+	var doc Document
+	sections := map[string][]string{}
+	current := sectionHeader
+	for _, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(strings.ReplaceAll(raw, "\t", " "))
+		line = strings.Join(strings.Fields(line), " ")
+		if section, ok := headingSection(line); ok {
+			current = section
+			continue
+		}
+		sections[current] = append(sections[current], line)
+	}
+
+	parseContact(&doc, text, sections[sectionHeader])
+	doc.Bio = strings.Join(nonEmpty(sections[sectionSummary]), " ")
+	doc.Skills = parseSkills(sections[sectionSkills])
+	doc.Education = parseEducation(sections[sectionEducation])
+	doc.Experience = parseExperience(sections[sectionExperience])
+	doc.Projects = parseProjects(sections[sectionProjects])
+	for _, line := range nonEmpty(sections[sectionCertifications]) {
+		doc.Certifications = append(doc.Certifications, Certification{Name: bulletPrefix.ReplaceAllString(line, "")})
+	}
+	return doc
+}
+
+// headingSection reports whether a line is a section heading
+func headingSection(line string) (string, bool) {
+	if line == "" || len(line) > 40 {
+		return "", false
+	}
+	key := strings.ToLower(strings.TrimRight(line, ": "))
+	key = strings.ReplaceAll(key, "&", "and")
+	for section, headings := range sectionHeadings {
+		if containsString(headings, key) {
+			return section, true
+		}
+	}
+	return "", false
+}
+
+// parseContact fills name, email, phone, links, title and location from the top of the resume
+func parseContact(doc *Document, text string, header []string) {
+	doc.Email = emailPattern.FindString(text)
+	for _, line := range header {
+		if doc.Phone == "" {
+			for _, candidate := range phonePattern.FindAllString(line, -1) {
+				// Date ranges like "2019 - 2023" also look like numbers
+				if digits := countDigits(candidate); digits >= 9 && digits <= 15 {
+					doc.Phone = strings.TrimSpace(candidate)
+					break
+				}
+			}
+		}
+		if doc.Location == "" && strings.Contains(line, "|") {
+			for _, part := range strings.Split(line, "|") {
+				if part = strings.TrimSpace(part); isLocation(part) {
+					doc.Location = part
+					break
+				}
+			}
+		}
+		withoutEmail := emailPattern.ReplaceAllString(line, " ")
+		for _, link := range urlPattern.FindAllString(withoutEmail, -1) {
+			if strings.Contains(link, ".") && !containsString(doc.Links, link) {
+				doc.Links = append(doc.Links, strings.TrimRight(link, ".)"))
+			}
+		}
+	}
+
+	for i, line := range nonEmpty(header) {
+		if i > 4 {
+			break
+		}
+		if isContactLine(line) {
+			continue
+		}
+		switch {
+		case doc.Name == "" && looksLikeName(line):
+			doc.Name = line
+		case doc.Name != "" && doc.Title == "" && len(line) <= 60 && !strings.Contains(line, ","):
+			doc.Title = line
+		case doc.Location == "" && isLocation(line):
+			doc.Location = line
+		}
+	}
+}
+
+// isLocation accepts short "City, Region" style text
+func isLocation(s string) bool {
+	return strings.Contains(s, ",") && len(s) <= 60 && countDigits(s) == 0 && !emailPattern.MatchString(s) && !urlPattern.MatchString(s)
+}
+
+func isContactLine(line string) bool {
+	return emailPattern.MatchString(line) || urlPattern.MatchString(line) || countDigits(line) >= 7 || strings.Contains(line, "|")
+}
+
+// looksLikeName accepts two to five capitalised words without digits
+func looksLikeName(line string) bool {
+	words := strings.Fields(line)
+	if len(words) < 2 || len(words) > 5 || countDigits(line) > 0 {
+		return false
+	}
+	for _, w := range words {
+		r := []rune(w)
+		if !unicode.IsUpper(r[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSkills splits a skills section on commas, bullets and similar separators,
+// dropping category labels such as "Languages:"
+func parseSkills(lines []string) []string {
+	var skills []string
+	seen := map[string]bool{}
+	for _, line := range nonEmpty(lines) {
+		line = bulletPrefix.ReplaceAllString(line, "")
+		line = labelPrefix.ReplaceAllString(line, "")
+		for _, part := range skillSeparators.Split(line, -1) {
+			part = strings.Trim(part, " .")
+			if part == "" || len(part) > 40 || seen[strings.ToLower(part)] {
+				continue
+			}
+			seen[strings.ToLower(part)] = true
+			skills = append(skills, part)
+		}
+	}
+	return skills
+}
+
+// parseEducation starts a new entry at each line naming a school or a degree
+func parseEducation(lines []string) []Education {
+	var entries []Education
+	var current *Education
+	for _, line := range nonEmpty(lines) {
+		line = bulletPrefix.ReplaceAllString(line, "")
+		start, end, rest := splitDates(line)
+		school := schoolPattern.MatchString(rest)
+		degree := degreePattern.FindString(rest)
+
+		if current == nil || (school && current.Institution != "") || (degree != "" && current.Degree != "") {
+			entries = append(entries, Education{})
+			current = &entries[len(entries)-1]
+		}
+		if start != "" && current.Start == "" {
+			current.Start, current.End = start, end
+		}
+		if school && current.Institution == "" {
+			current.Institution = schoolName(rest)
+		}
+		if degree != "" && current.Institution == "" {
+			// "MIT — B.S. in Computer Science": the part that isn't the degree names the school
+			for _, part := range separators.Split(rest, -1) {
+				if part = strings.TrimSpace(part); part != "" && !strings.Contains(part, degree) && countDigits(part) == 0 {
+					current.Institution = part
+					break
+				}
+			}
+		}
+		if degree != "" && current.Degree == "" {
+			current.Degree = strings.TrimSpace(degree)
+			after := rest[strings.Index(rest, degree)+len(degree):]
+			if m := fieldPattern.FindStringSubmatch(after); m != nil {
+				current.Field = strings.TrimSpace(m[1])
+			}
+		}
+	}
+
+	var result []Education
+	for _, e := range entries {
+		if e.Institution != "" || e.Degree != "" {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// schoolName picks the part of a line that names the institution
+func schoolName(line string) string {
+	for _, part := range separators.Split(line, -1) {
+		if schoolPattern.MatchString(part) {
+			return strings.TrimSpace(part)
+		}
+	}
+	return strings.TrimSpace(line)
+}
+
+// parseExperience treats a non-bullet line carrying a date range, or the line
+// just before one, as the start of a new role
+func parseExperience(lines []string) []Experience {
+	var entries []Experience
+	var current *Experience
+	lines = nonEmpty(lines)
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if bulletPrefix.MatchString(line) && current != nil {
+			if point := bulletPrefix.ReplaceAllString(line, ""); point != "" {
+				current.Highlights = append(current.Highlights, point)
+			}
+			continue
+		}
+
+		start, end, rest := splitDates(line)
+		headerLines := []string{rest}
+		if start == "" && i+1 < len(lines) && !bulletPrefix.MatchString(lines[i+1]) {
+			// "Software Engineer Intern" on one line, "Acme Corp  Jun 2023 - Aug 2023" on the next
+			if s, e, r := splitDates(lines[i+1]); s != "" {
+				start, end = s, e
+				headerLines = append(headerLines, r)
+				i++
+			}
+		}
+		if start == "" {
+			if current == nil {
+				continue
+			}
+			// A short line straight after the header is usually the location
+			if current.Location == "" && current.Summary == "" && len(current.Highlights) == 0 && len(line) <= 40 && !strings.HasSuffix(line, ".") {
+				current.Location = line
+				continue
+			}
+			if current.Summary != "" {
+				current.Summary += " "
+			}
+			current.Summary += line
+			continue
+		}
+
+		entries = append(entries, Experience{Start: start, End: end})
+		current = &entries[len(entries)-1]
+		current.Position, current.Company, current.Location = roleParts(headerLines)
+	}
+	return entries
+}
+
+// roleParts reads position, company and location from an entry's header lines
+func roleParts(lines []string) (position, company, location string) {
+	var parts []string
+	for _, line := range lines {
+		if m := titleAtCo.FindStringSubmatch(line); m != nil {
+			parts = append(parts, m[1], m[2])
+			continue
+		}
+		for _, p := range separators.Split(line, -1) {
+			if p = strings.TrimSpace(p); p != "" {
+				parts = append(parts, p)
+			}
+		}
+	}
+	if len(parts) > 0 {
+		position = parts[0]
+	}
+	if len(parts) > 1 {
+		company = parts[1]
+	}
+	if len(parts) > 2 {
+		location = strings.Join(parts[2:], ", ")
+	}
+	return position, company, location
+}
+
+// parseProjects starts a new project at each non-bullet line that isn't a
+// technology list; bullets become highlights
+func parseProjects(lines []string) []Project {
+	var entries []Project
+	var current *Project
+	for _, line := range nonEmpty(lines) {
+		if bulletPrefix.MatchString(line) && current != nil {
+			point := bulletPrefix.ReplaceAllString(line, "")
+			if techPrefix.MatchString(point) {
+				current.Technologies = append(current.Technologies, parseSkills([]string{techPrefix.ReplaceAllString(point, "")})...)
+			} else if point != "" {
+				current.Highlights = append(current.Highlights, point)
+			}
+			continue
+		}
+		if techPrefix.MatchString(line) && current != nil {
+			current.Technologies = append(current.Technologies, parseSkills([]string{techPrefix.ReplaceAllString(line, "")})...)
+			continue
+		}
+		// Between a project's title and its bullets come a technology list and a description
+		if current != nil && len(current.Highlights) == 0 {
+			if current.Description == "" && len(current.Technologies) == 0 && isList(line) {
+				current.Technologies = parseSkills([]string{line})
+				continue
+			}
+			if current.Description == "" || len(line) > 60 {
+				current.Description = strings.TrimSpace(current.Description + " " + line)
+				continue
+			}
+		}
+
+		_, _, name := splitDates(line)
+		entry := Project{Name: name}
+		if parts := separators.Split(name, 2); len(parts) == 2 && len(parts[0]) > 0 {
+			entry.Name = strings.TrimSpace(parts[0])
+			entry.Technologies = parseSkills([]string{parts[1]})
+		}
+		if link := urlPattern.FindString(line); link != "" && strings.Contains(link, "/") {
+			entry.URL = link
+		}
+		entries = append(entries, entry)
+		current = &entries[len(entries)-1]
+	}
+	return entries
+}
+
+// splitDates removes the first date range from a line, returning its start, end and the remaining text
+func splitDates(line string) (start, end, rest string) {
+	loc := dateRangePattern.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return "", "", line
+	}
+	if loc[2] >= 0 {
+		start, end = line[loc[2]:loc[3]], line[loc[4]:loc[5]]
+	} else {
+		start = line[loc[6]:loc[7]]
+	}
+	rest = strings.TrimSpace(line[:loc[0]] + " " + line[loc[1]:])
+	rest = strings.Trim(rest, " ,|–—-()")
+	if strings.EqualFold(end, "current") || strings.EqualFold(end, "now") || strings.EqualFold(end, "ongoing") {
+		end = "Present"
+	}
+	return start, end, rest
+}
+
+// isList reports whether a line is a comma-separated list of short items
+func isList(line string) bool {
+	items := strings.Split(line, ",")
+	if len(items) < 2 {
+		return false
+	}
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item == "" || len(item) > 25 {
+			return false
+		}
+	}
+	return true
+}
+
+func nonEmpty(lines []string) []string {
+	var out []string
+	for _, line := range lines {
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+var monthNumbers = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+// parseResumeDate reads "Jan 2022", "January 2022", "06/2022", "2022" or "Present"
+func parseResumeDate(s string, now time.Time) (time.Time, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "present", "current", "now", "ongoing":
+		return now, true
+	}
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == '/' || r == '.' || r == ',' })
+	if len(fields) == 0 {
+		return time.Time{}, false
+	}
+	year, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || year < 1950 || year > 2100 {
+		return time.Time{}, false
+	}
+	month := time.January
+	if len(fields) > 1 {
+		if m, err := strconv.Atoi(fields[0]); err == nil && m >= 1 && m <= 12 {
+			month = time.Month(m)
+		} else if len(fields[0]) >= 3 {
+			if m, ok := monthNumbers[fields[0][:3]]; ok {
+				month = m
+			}
+		}
+	}
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC), true
+}
+
+// ExperienceYears totals the time covered by a resume's experience entries.
+// Overlapping roles are only counted once; entries without dates are skipped.
+func ExperienceYears(doc Document, now time.Time) float64 {
+	type span struct{ from, to time.Time }
+	var spans []span
+	for _, e := range doc.Experience {
+		from, ok := parseResumeDate(e.Start, now)
+		if !ok {
+			continue
+		}
+		to, ok := parseResumeDate(e.End, now)
+		if !ok || e.End == "" {
+			// A lone year or month covers that period
+			to = from.AddDate(0, 1, 0)
+			if strings.TrimSpace(e.Start) == strconv.Itoa(from.Year()) {
+				to = from.AddDate(1, 0, 0)
+			}
+		} else {
+			to = to.AddDate(0, 1, 0) // end months are inclusive
+		}
+		if to.After(now) {
+			to = now
+		}
+		if to.After(from) {
+			spans = append(spans, span{from, to})
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i].from.Before(spans[j].from) })
+	var total time.Duration
+	var merged *span
+	for i := range spans {
+		if merged != nil && !spans[i].from.After(merged.to) {
+			if spans[i].to.After(merged.to) {
+				merged.to = spans[i].to
+			}
+			continue
+		}
+		if merged != nil {
+			total += merged.to.Sub(merged.from)
+		}
+		merged = &spans[i]
+	}
+	if merged != nil {
+		total += merged.to.Sub(merged.from)
+	}
+	return total.Hours() / 24 / 365.25
+}
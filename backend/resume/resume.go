@@ -0,0 +1,110 @@
+// Package resume renders resumes from structured content. The local backend
+// produces PDF and HTML in-process; the useresume.ai backend is optional and
+// only available when USERESUME_API_KEY is set.
+package resume
+
+import (
+	"SkillBridge/config"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Document is everything that appears on a resume
+type Document struct {
+	Name           string          `json:"name"`
+	Email          string          `json:"email"`
+	Phone          string          `json:"phone"`
+	Location       string          `json:"location"`
+	Title          string          `json:"title"`
+	Bio            string          `json:"bio"`
+	Links          []string        `json:"links"`
+	Skills         []string        `json:"skills"`
+	Experience     []Experience    `json:"experience"`
+	Education      []Education     `json:"education"`
+	Projects       []Project       `json:"projects"`
+	Certifications []Certification `json:"certifications"`
+}
+
+type Experience struct {
+	Company    string   `json:"company"`
+	Position   string   `json:"position"`
+	Location   string   `json:"location"`
+	Start      string   `json:"start"`
+	End        string   `json:"end"`
+	Summary    string   `json:"summary"`
+	Highlights []string `json:"highlights"`
+}
+
+type Education struct {
+	Institution string `json:"institution"`
+	Degree      string `json:"degree"`
+	Field       string `json:"field"`
+	Start       string `json:"start"`
+	End         string `json:"end"`
+}
+
+type Project struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Technologies []string `json:"technologies"`
+	Highlights   []string `json:"highlights"`
+	URL          string   `json:"url"`
+}
+
+type Certification struct {
+	Name      string `json:"name"`
+	Issuer    string `json:"issuer"`
+	Date      string `json:"date"`
+	VerifyURL string `json:"verify_url"`
+}
+
+// Output formats
+const (
+	FormatPDF  = "pdf"
+	FormatHTML = "html"
+)
+
+// Options select the template and output format
+type Options struct {
+	Template string
+	Format   string
+}
+
+// Result is a rendered resume. Local rendering fills Data; remote providers
+// that host the file themselves fill URL and Raw with their response.
+type Result struct {
+	ContentType string
+	Data        []byte
+	URL         string
+	Raw         []byte
+}
+
+// Backend renders a Document
+type Backend interface {
+	Name() string
+	Formats() []string
+	Render(ctx context.Context, doc Document, opts Options) (Result, error)
+}
+
+// ErrUnsupported is returned for a template or format a backend does not offer
+var ErrUnsupported = errors.New("resume: unsupported template or format")
+
+// Get returns the named backend. An empty name picks RESUME_BACKEND, then "local".
+func Get(name string) (Backend, error) {
+	if name == "" {
+		name = config.GetEnv("RESUME_BACKEND", "local")
+	}
+	switch name {
+	case "local":
+		return Local{}, nil
+	case "useresume":
+		key := config.GetEnv("USERESUME_API_KEY", "")
+		if key == "" {
+			return nil, errors.New("resume: the useresume backend needs USERESUME_API_KEY")
+		}
+		return UseResume{APIKey: key}, nil
+	default:
+		return nil, fmt.Errorf("resume: unknown backend %q", name)
+	}
+}
@@ -0,0 +1,140 @@
+package resume
+
+import (
+	"SkillBridge/metrics"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const useResumeEndpoint = "https://useresume.ai/api/v3/resume/create"
+
+// useResumeTransport counts and times calls to the resume provider
+var useResumeTransport = metrics.Transport("useresume", nil)
+
+// UseResume renders through the hosted useresume.ai API, which returns a link to the file
+type UseResume struct {
+	APIKey string
+}
+
+func (UseResume) Name() string { return "useresume" }
+
+func (UseResume) Formats() []string { return []string{FormatPDF} }
+
+// Render sends the document in useresume.ai's content format
+func (u UseResume) Render(ctx context.Context, doc Document, opts Options) (Result, error) {
+	if opts.Format != "" && opts.Format != FormatPDF {
+		return Result{}, ErrUnsupported
+	}
+
+	skills := make([]map[string]string, 0, len(doc.Skills))
+	for _, skill := range doc.Skills {
+		skills = append(skills, map[string]string{"name": skill})
+	}
+	experience := make([]map[string]interface{}, 0, len(doc.Experience))
+	for _, e := range doc.Experience {
+		experience = append(experience, map[string]interface{}{
+			"company":    e.Company,
+			"position":   e.Position,
+			"location":   e.Location,
+			"startDate":  e.Start,
+			"endDate":    e.End,
+			"summary":    e.Summary,
+			"highlights": nonNil(e.Highlights),
+		})
+	}
+	education := make([]map[string]interface{}, 0, len(doc.Education))
+	for _, e := range doc.Education {
+		education = append(education, map[string]interface{}{
+			"institution": e.Institution,
+			"degree":      e.Degree,
+			"field":       e.Field,
+			"startDate":   e.Start,
+			"endDate":     e.End,
+		})
+	}
+	projects := make([]map[string]interface{}, 0, len(doc.Projects))
+	for _, p := range doc.Projects {
+		projects = append(projects, map[string]interface{}{
+			"name":         p.Name,
+			"description":  p.Description,
+			"technologies": nonNil(p.Technologies),
+			"highlights":   nonNil(p.Highlights),
+		})
+	}
+	certifications := make([]map[string]string, 0, len(doc.Certifications))
+	for _, cert := range doc.Certifications {
+		certifications = append(certifications, map[string]string{"name": cert.Name})
+	}
+
+	template := opts.Template
+	if template == "" || template == "modern" {
+		template = "modern-pro"
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"content": map[string]interface{}{
+			"name":           doc.Name,
+			"email":          doc.Email,
+			"phone":          doc.Phone,
+			"location":       doc.Location,
+			"title":          doc.Title,
+			"bio":            doc.Bio,
+			"skills":         skills,
+			"experience":     experience,
+			"projects":       projects,
+			"education":      education,
+			"certifications": certifications,
+		},
+		"style": map[string]string{"template": template},
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, useResumeEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+u.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 60 * time.Second, Transport: useResumeTransport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("resume: failed to reach useresume.ai: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Result{}, err
+	}
+	if resp.StatusCode >= 300 {
+		return Result{}, fmt.Errorf("resume: useresume.ai returned %s: %s", resp.Status, body)
+	}
+
+	var parsed struct {
+		Data struct {
+			FileURL string `json:"file_url"`
+			URL     string `json:"url"`
+		} `json:"data"`
+	}
+	json.Unmarshal(body, &parsed)
+	url := parsed.Data.FileURL
+	if url == "" {
+		url = parsed.Data.URL
+	}
+	return Result{ContentType: "application/json", URL: url, Raw: body}, nil
+}
+
+// nonNil keeps empty lists from being sent as null
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -1,56 +1,104 @@
 package router
 
 import (
+	"SkillBridge/apierror"
+	"SkillBridge/config"
 	"SkillBridge/controller"
 	"SkillBridge/middleware"
+	"SkillBridge/models"
+	"SkillBridge/openapi"
+	"SkillBridge/ratelimit"
+	"log/slog"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 func SetupRouter() *gin.Engine {
-	router := gin.Default()
+	router := gin.New()
+	// Every request gets an ID and a logger; errors and panics are rendered with it
+	router.Use(middleware.RequestID(), middleware.RequestLogger(), middleware.Metrics(), middleware.Errors(), middleware.Recovery())
+	router.NoRoute(func(c *gin.Context) {
+		apierror.Abort(c, apierror.NotFound("Route not found"))
+	})
 
 	// Set trusted proxies - only trust localhost for development
 	// In production, set this to your actual proxy IPs
 	router.SetTrustedProxies([]string{"127.0.0.1", "::1"})
 
-	// CORS middleware
-	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
+	// 🛡️ CORS and security headers. Public, credential-free resources may be
+	// fetched from any origin; everything else only from CORS_ALLOWED_ORIGINS.
+	cors := middleware.DefaultCORSConfig()
+	public := cors
+	public.AllowedOrigins = []string{"*"}
+	public.AllowedMethods = []string{"GET", "OPTIONS"}
+	public.AllowCredentials = false
+	router.Use(middleware.SecurityHeaders(middleware.DefaultSecurityHeadersConfig()))
+	router.Use(middleware.CORS(cors,
+		middleware.CORSOverride{Path: "/openapi.json", Config: public},
+		middleware.CORSOverride{Path: "/api/certificates/public-key", Config: public},
+		middleware.CORSOverride{Path: "/api/certificates/:certificate_id/verify", Config: public},
+		middleware.CORSOverride{Path: "/api/certificates/:certificate_id/pdf", Config: public},
+		middleware.CORSOverride{Path: "/api/calendar/:token/interviews.ics", Config: public},
+	))
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
+	// 📘 API specification; OPENAPI_CONTRACT_CHECK=log|strict checks responses against it
+	spec := openapi.New("SkillBridge API", "1.0.0")
+	spec.Error = models.ErrorResponse{}
+	spec.ValidationError = models.ValidationErrorResponse{}
+	spec.APIKeyScope = middleware.APIKeyScope
+	router.Use(spec.ContractCheck(config.GetEnv("OPENAPI_CONTRACT_CHECK", openapi.CheckOff)))
+	router.GET("/openapi.json", spec.Handler(router))
 
-		c.Next()
-	})
+	// ❤️ Health and metrics: liveness never touches the database, readiness does
+	router.GET("/health", controller.Readiness)
+	router.GET("/health/live", controller.Liveness)
+	router.GET("/health/ready", controller.Readiness)
+	router.GET("/metrics", controller.Metrics)
+
+	// 📦 Request body limits: MAX_BODY_BYTES for everything, less for auth and
+	// more for file uploads (MAX_UPLOAD_BYTES, which must exceed RESUME_MAX_BYTES)
+	router.Use(middleware.BodyLimit(middleware.BodyLimitFromEnv("MAX_BODY_BYTES", 1<<20)))
+	authBody := middleware.BodyLimit(middleware.BodyLimitFromEnv("MAX_AUTH_BODY_BYTES", 64<<10))
+	uploadBody := middleware.BodyLimit(middleware.BodyLimitFromEnv("MAX_UPLOAD_BYTES", 25<<20))
+
+	// 🚦 Rate limits per route group; override with RATE_LIMIT_<GROUP>_IP and
+	// RATE_LIMIT_<GROUP>_USER, e.g. RATE_LIMIT_AUTH_IP=20/m or =off
+	authLimit := middleware.RateLimit(middleware.RateLimitPolicyFromEnv("auth", ratelimit.PerMinute(10), ratelimit.Limit{}))
+	apiLimit := middleware.RateLimit(middleware.RateLimitPolicyFromEnv("api", ratelimit.PerMinute(1200), ratelimit.PerMinute(600)))
+	applyLimit := middleware.RateLimit(middleware.RateLimitPolicyFromEnv("apply", ratelimit.PerMinute(30), ratelimit.PerMinute(10)))
+	chatLimit := middleware.RateLimit(middleware.RateLimitPolicyFromEnv("chat", ratelimit.PerMinute(120), ratelimit.PerMinute(60)))
 
 	// 🔓 Public routes
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok", "message": "Backend is running"})
-	})
-	router.POST("/api/signup", controller.SignUp)
-	router.POST("/api/login", controller.Login)
-	router.POST("/api/google-oauth", controller.GoogleOAuth)
+	router.POST("/api/signup", authLimit, authBody, controller.SignUp)
+	router.POST("/api/login", authLimit, authBody, controller.Login)
+	router.POST("/api/google-oauth", authLimit, authBody, controller.GoogleOAuth)
 
 	// 🔍 Publicly accessible project listing
 	router.GET("/api/projects", controller.GetAllProjects)
 	router.GET("/api/projects/:id", controller.GetProjectById)
 	router.GET("/api/student/:id", controller.GetPublicStudentProfile)
 	router.GET("/api/company/:id", controller.GetPublicCompanyProfile)
+	router.GET("/api/organisations/:id", controller.GetPublicOrganisation)
+	router.GET("/api/calendar/:token/interviews.ics", controller.GetCalendarFeed)
+	router.GET("/api/files/resumes/:id", controller.DownloadSignedResume)
+	router.GET("/api/notifications/unsubscribe", middleware.ContentSecurityPolicy(middleware.DocumentCSP), controller.UnsubscribeEmail)
+	router.POST("/api/notifications/unsubscribe", middleware.ContentSecurityPolicy(middleware.DocumentCSP), controller.UnsubscribeEmail)
 	router.GET("/api/guides", controller.GetAllGuides)
 
 	// 🔍 Publicly accessible job listings
 	router.GET("/api/jobs", controller.GetAllJobListings)
 	router.GET("/api/jobs/:id", controller.GetJobListingByID)
 
+	// 🏅 Public certificate verification
+	router.GET("/api/certificates/public-key", controller.GetCertificatePublicKey)
+	router.GET("/api/certificates/:certificate_id/verify", controller.VerifyCertificate)
+	router.GET("/api/certificates/:certificate_id/pdf", controller.GetCertificatePDF)
+
 	// ✅ Protected routes
 	authorized := router.Group("/api")
-	authorized.Use(middleware.AuthMiddleware())
+	authorized.Use(middleware.AuthMiddleware(), apiLimit)
+	authorized.Use(controller.TrackPresence())
 	{
 		authorized.GET("/dashboard/admin", middleware.AuthorizeRoles("admin"), controller.AdminDashboard)
 
@@ -59,13 +107,19 @@ func SetupRouter() *gin.Engine {
 		authorized.PUT("/profile", controller.UpdateProfile)
 		authorized.POST("/refresh-token", controller.RefreshToken)
 
+		// 🗂️ Portfolio
+		authorized.GET("/portfolio/settings", middleware.AuthorizeRoles("student"), controller.GetPortfolioSettings)
+		authorized.PUT("/portfolio/settings", middleware.AuthorizeRoles("student"), controller.UpdatePortfolioSettings)
+		authorized.POST("/student/:id/endorsements", middleware.AuthorizeRoles("guide"), controller.EndorseStudent)
+		authorized.DELETE("/endorsements/:id", middleware.AuthorizeRoles("guide"), controller.DeleteEndorsement)
+
 		// GitHub integration routes
 		authorized.POST("/github/token", controller.SetGithubToken)
 		authorized.DELETE("/github/token", controller.RemoveGithubToken)
 
 		// 📤 Only 'company' can post projects
-		authorized.POST("/projects", middleware.AuthorizeRoles("company"), controller.PostProject)
-		authorized.DELETE("/projects/:id", middleware.AuthorizeRoles("company"), controller.DeleteProject)
+		authorized.POST("/projects", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.PostProject)
+		authorized.DELETE("/projects/:id", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.DeleteProject)
 		authorized.DELETE("/projects/:id/apply", middleware.AuthorizeRoles("student"), controller.WithdrawApplication)
 
 		// 🧑‍🎓 Only 'student' can apply to a project
@@ -75,8 +129,22 @@ func SetupRouter() *gin.Engine {
 
 		authorized.POST("/projects/:id/submit", middleware.AuthorizeRoles("student"), controller.SubmitProject)
 		authorized.GET("/projects/:id/submissions", middleware.AuthorizeRoles("company"), controller.GetProjectSubmissions)
+		authorized.PUT("/projects/:id/rubric", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.SetProjectRubric)
+		authorized.GET("/projects/:id/rubric", controller.GetProjectRubric)
+		authorized.GET("/projects/:id/review-scores", middleware.AuthorizeRoles("company"), controller.GetProjectReviewScores)
 		authorized.POST("/submissions/:id/review", middleware.AuthorizeRoles("company"), controller.ReviewSubmission)
 		authorized.GET("/my-submissions", middleware.AuthorizeRoles("student"), controller.GetMySubmissions)
+
+		// 🏅 Certificates
+		authorized.GET("/my-certificates", middleware.AuthorizeRoles("student"), controller.GetMyCertificates)
+		authorized.POST("/certificates/:certificate_id/revoke", middleware.AuthorizeRoles("company", "admin"), controller.RevokeCertificate)
+
+		// 💬 Submission detail and inline review comments
+		authorized.GET("/submissions/:id", controller.GetSubmissionDetail)
+		authorized.GET("/submissions/:id/comments", controller.GetSubmissionComments)
+		authorized.POST("/submissions/:id/comments", controller.AddSubmissionComment)
+		authorized.PATCH("/comments/:id/resolve", controller.ResolveSubmissionComment)
+		authorized.DELETE("/comments/:id", controller.DeleteSubmissionComment)
 		authorized.GET("/dashboard/student", middleware.AuthorizeRoles("student"), controller.StudentDashboard)
 		authorized.GET("/dashboard/company", middleware.AuthorizeRoles("company"), controller.CompanyDashboard)
 		authorized.GET("/dashboard/guide", middleware.AuthorizeRoles("guide"), controller.GuideDashboard)
@@ -86,9 +154,59 @@ func SetupRouter() *gin.Engine {
 		authorized.GET("/guide/submissions", middleware.AuthorizeRoles("guide"), controller.GetGuideSubmissions)
 		authorized.PUT("/submissions/:id/review", middleware.AuthorizeRoles("company", "guide"), controller.ReviewSubmission)
 
+		// 🧭 Guide assignment
+		authorized.POST("/projects/:id/guide-invitations", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.InviteGuideToProject)
+		authorized.GET("/projects/:id/guide-invitations", middleware.AuthorizeRoles("company"), controller.GetProjectGuideInvitations)
+		authorized.GET("/guide/invitations", middleware.AuthorizeRoles("guide"), controller.GetGuideInvitations)
+		authorized.POST("/guide/invitations/:id/respond", middleware.AuthorizeRoles("guide"), controller.RespondToGuideInvitation)
+		authorized.GET("/guide/projects", middleware.AuthorizeRoles("guide"), controller.GetGuideProjects)
+		authorized.PUT("/guide/capacity", middleware.AuthorizeRoles("guide"), controller.UpdateGuideCapacity)
+		authorized.PUT("/admin/projects/:id/guide", middleware.AuthorizeRoles("admin"), controller.ReassignProjectGuide)
+
+		// 🪝 Company webhooks
+		authorized.GET("/webhooks/events", middleware.AuthorizeRoles("company"), controller.GetWebhookEvents)
+		authorized.GET("/webhooks", middleware.AuthorizeRoles("company"), controller.GetWebhooks)
+		authorized.POST("/webhooks", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.CreateWebhook)
+		authorized.GET("/webhooks/:id", middleware.AuthorizeRoles("company"), controller.GetWebhook)
+		authorized.PUT("/webhooks/:id", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.UpdateWebhook)
+		authorized.DELETE("/webhooks/:id", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.DeleteWebhook)
+		authorized.POST("/webhooks/:id/rotate-secret", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.RotateWebhookSecret)
+		authorized.POST("/webhooks/:id/ping", middleware.AuthorizeRoles("company"), controller.PingWebhook)
+		authorized.GET("/webhooks/:id/deliveries", middleware.AuthorizeRoles("company"), controller.GetWebhookDeliveries)
+		authorized.GET("/webhooks/:id/deliveries/:delivery_id", middleware.AuthorizeRoles("company"), controller.GetWebhookDelivery)
+		authorized.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.RedeliverWebhook)
+
+		// 🔑 API keys
+		authorized.GET("/api-keys/scopes", middleware.AuthorizeRoles("company"), controller.GetAPIKeyScopes)
+		authorized.GET("/api-keys", middleware.AuthorizeRoles("company"), controller.GetAPIKeys)
+		authorized.POST("/api-keys", middleware.AuthorizeRoles("company"), controller.CreateAPIKey)
+		authorized.DELETE("/api-keys/:id", middleware.AuthorizeRoles("company"), controller.RevokeAPIKey)
+
+		// 🔔 Notification preferences
+		authorized.GET("/notifications/preferences", controller.GetNotificationPreferences)
+		authorized.PUT("/notifications/preferences", controller.UpdateNotificationPreferences)
+
+		// ⚙️ Background jobs
+		authorized.GET("/admin/jobs", middleware.AuthorizeRoles("admin"), controller.GetBackgroundJobs)
+		authorized.GET("/admin/jobs/:id", middleware.AuthorizeRoles("admin"), controller.GetBackgroundJob)
+		authorized.POST("/admin/jobs/:id/retry", middleware.AuthorizeRoles("admin"), controller.RetryBackgroundJob)
+		authorized.DELETE("/admin/jobs/:id", middleware.AuthorizeRoles("admin"), controller.DeleteBackgroundJob)
+
+		// 🏢 Organisation routes
+		authorized.POST("/organisations", middleware.AuthorizeRoles("company"), controller.CreateOrganisation)
+		authorized.GET("/organisation", middleware.AuthorizeRoles("company"), controller.GetMyOrganisation)
+		authorized.PUT("/organisation", middleware.AuthorizeRoles("company"), controller.UpdateOrganisation)
+		authorized.POST("/organisation/invitations", middleware.AuthorizeRoles("company"), controller.InviteOrganisationMember)
+		authorized.GET("/organisation/invitations", middleware.AuthorizeRoles("company"), controller.GetOrganisationInvitations)
+		authorized.DELETE("/organisation/invitations/:id", middleware.AuthorizeRoles("company"), controller.RevokeOrganisationInvitation)
+		authorized.POST("/organisation/invitations/accept", middleware.AuthorizeRoles("company"), controller.AcceptOrganisationInvitation)
+		authorized.GET("/my-organisation-invitations", middleware.AuthorizeRoles("company"), controller.GetMyOrganisationInvitations)
+		authorized.PUT("/organisation/members/:user_id", middleware.AuthorizeRoles("company"), controller.UpdateOrganisationMemberRole)
+		authorized.DELETE("/organisation/members/:user_id", middleware.AuthorizeRoles("company"), controller.RemoveOrganisationMember)
+
 		// 💬 Chat routes
 		authorized.POST("/chat/start", middleware.AuthorizeRoles("student"), controller.StartConversation)
-		authorized.POST("/chat/send", middleware.AuthorizeRoles("student", "guide"), controller.SendMessage)
+		authorized.POST("/chat/send", middleware.AuthorizeRoles("student", "guide"), chatLimit, controller.SendMessage)
 		authorized.GET("/chat/history/:student_id/:guide_id", middleware.AuthorizeRoles("student", "guide"), controller.GetChatHistory)
 		authorized.GET("/chat/conversations", middleware.AuthorizeRoles("student", "guide"), controller.GetUserConversations)
 		authorized.GET("/chat/connected-guides", middleware.AuthorizeRoles("student"), controller.GetConnectedGuides)
@@ -96,24 +214,95 @@ func SetupRouter() *gin.Engine {
 		authorized.POST("/guide/confirm-connection", middleware.AuthorizeRoles("guide"), controller.ConfirmConnection)
 
 		// 💼 Job listing routes (Company side)
-		authorized.POST("/jobs", middleware.AuthorizeRoles("company"), controller.CreateJobListing)
+		authorized.POST("/jobs", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.CreateJobListing)
 		authorized.GET("/company/jobs", middleware.AuthorizeRoles("company"), controller.GetCompanyJobListings)
 		// 🧑‍🎓 Student apply to job + my job applications
-		authorized.POST("/jobs/:id/apply", middleware.AuthorizeRoles("student"), controller.ApplyToJob)
+		authorized.POST("/jobs/:id/apply", middleware.AuthorizeRoles("student"), applyLimit, controller.ApplyToJob)
 		authorized.GET("/my-job-applications", middleware.AuthorizeRoles("student"), controller.GetMyJobApplications)
-		authorized.PUT("/jobs/:id", middleware.AuthorizeRoles("company"), controller.UpdateJobListing)
-		authorized.DELETE("/jobs/:id", middleware.AuthorizeRoles("company"), controller.DeleteJobListing)
+		authorized.PUT("/jobs/:id", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.UpdateJobListing)
+		authorized.DELETE("/jobs/:id", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.DeleteJobListing)
 		authorized.GET("/jobs/:id/applications", middleware.AuthorizeRoles("company"), controller.GetJobApplications)
+		authorized.GET("/jobs/:id/applications/export", middleware.AuthorizeRoles("company"), controller.ExportJobApplications)
+		authorized.POST("/jobs/:id/applications/fit", middleware.AuthorizeRoles("company"), controller.RecomputeJobFits)
 		authorized.GET("/applications/:id", middleware.AuthorizeRoles("company"), controller.GetApplicationDetail)
-		authorized.PATCH("/applications/:id/status", middleware.AuthorizeRoles("company"), controller.UpdateApplicationStatus)
+		authorized.PATCH("/applications/:id/status", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.UpdateApplicationStatus)
 		authorized.GET("/company/application-stats", middleware.AuthorizeRoles("company"), controller.GetApplicationStats)
+		authorized.GET("/jobs/:id/pipeline", middleware.AuthorizeRoles("company"), controller.GetJobPipeline)
+		authorized.PUT("/jobs/:id/pipeline", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.UpdateJobPipeline)
+		authorized.POST("/jobs/:id/applications/move", middleware.AuthorizeRoles("company"), controller.RequireListingManager(), controller.BulkMoveApplications)
+		authorized.GET("/applications/:id/history", middleware.AuthorizeRoles("company"), controller.GetApplicationHistory)
+		authorized.GET("/applications/:id/resume-url", middleware.AuthorizeRoles("company"), controller.GetApplicationResumeURL)
+		authorized.GET("/applications/:id/resume-version", middleware.AuthorizeRoles("company"), controller.GetApplicationResumeVersion)
+
+		// 📎 Resume uploads
+		authorized.POST("/resumes/upload", middleware.AuthorizeRoles("student"), uploadBody, controller.UploadResume)
+		authorized.GET("/resumes", middleware.AuthorizeRoles("student"), controller.GetMyResumes)
+		authorized.GET("/resumes/:id/download", middleware.AuthorizeRoles("student"), controller.DownloadMyResume)
+		authorized.DELETE("/resumes/:id", middleware.AuthorizeRoles("student"), controller.DeleteResume)
+
+		// 🗓️ Interview scheduling
+		authorized.POST("/jobs/:id/interview-slots", middleware.AuthorizeRoles("company"), controller.CreateInterviewSlots)
+		authorized.GET("/jobs/:id/interview-slots", middleware.AuthorizeRoles("company"), controller.GetJobInterviewSlots)
+		authorized.DELETE("/interview-slots/:id", middleware.AuthorizeRoles("company"), controller.DeleteInterviewSlot)
+		authorized.GET("/my-job-applications/:id/interview-slots", middleware.AuthorizeRoles("student"), controller.GetAvailableInterviewSlots)
+		authorized.POST("/interviews", middleware.AuthorizeRoles("student"), controller.BookInterview)
+		authorized.GET("/interviews", middleware.AuthorizeRoles("student", "company"), controller.GetMyInterviews)
+		authorized.PUT("/interviews/:id/reschedule", middleware.AuthorizeRoles("student", "company"), controller.RescheduleInterview)
+		authorized.POST("/interviews/:id/cancel", middleware.AuthorizeRoles("student", "company"), controller.CancelInterview)
+		authorized.GET("/interviews/:id/ics", middleware.AuthorizeRoles("student", "company", "admin"), controller.GetInterviewICS)
+		authorized.GET("/interviews/calendar-feed", middleware.AuthorizeRoles("student", "company"), controller.GetCalendarFeedURL)
 
 		// 🎓 Interview Prep
 		authorized.GET("/interview-prep", controller.GetInterviewResources)
 
 		// 📄 Resume Builder
-		authorized.POST("/resume/generate", middleware.AuthorizeRoles("student"), controller.GenerateResume)
+		authorized.POST("/resume/generate", middleware.AuthorizeRoles("student"), middleware.ContentSecurityPolicy(middleware.DocumentCSP), controller.GenerateResume)
+		authorized.GET("/resume/templates", middleware.AuthorizeRoles("student"), controller.GetResumeTemplates)
+
+		authorized.POST("/resume/parse", middleware.AuthorizeRoles("student"), uploadBody, controller.ParseResume)
+		authorized.POST("/resume/parse/apply", middleware.AuthorizeRoles("student"), controller.ApplyParsedResume)
+
+		// 🔔 Saved searches and job alerts
+		authorized.POST("/saved-searches", middleware.AuthorizeRoles("student"), controller.CreateSavedSearch)
+		authorized.GET("/saved-searches", middleware.AuthorizeRoles("student"), controller.GetSavedSearches)
+		authorized.PUT("/saved-searches/:id", middleware.AuthorizeRoles("student"), controller.UpdateSavedSearch)
+		authorized.DELETE("/saved-searches/:id", middleware.AuthorizeRoles("student"), controller.DeleteSavedSearch)
+		authorized.GET("/saved-searches/:id/results", middleware.AuthorizeRoles("student"), controller.RunSavedSearch)
+		authorized.GET("/saved-searches/:id/matches", middleware.AuthorizeRoles("student"), controller.GetSavedSearchMatches)
+
+		// Resume drafts and versions
+		authorized.POST("/resume/drafts", middleware.AuthorizeRoles("student"), controller.CreateResumeDraft)
+		authorized.GET("/resume/drafts", middleware.AuthorizeRoles("student"), controller.GetResumeDrafts)
+		authorized.GET("/resume/drafts/:id", middleware.AuthorizeRoles("student"), controller.GetResumeDraft)
+		authorized.PATCH("/resume/drafts/:id", middleware.AuthorizeRoles("student"), controller.UpdateResumeDraft)
+		authorized.DELETE("/resume/drafts/:id", middleware.AuthorizeRoles("student"), controller.DeleteResumeDraft)
+		authorized.POST("/resume/drafts/:id/duplicate", middleware.AuthorizeRoles("student"), controller.DuplicateResumeDraft)
+		authorized.POST("/resume/drafts/:id/render", middleware.AuthorizeRoles("student"), middleware.ContentSecurityPolicy(middleware.DocumentCSP), controller.RenderResumeDraft)
+		authorized.POST("/resume/drafts/:id/versions", middleware.AuthorizeRoles("student"), controller.CreateResumeVersion)
+		authorized.GET("/resume/drafts/:id/versions/:version", middleware.AuthorizeRoles("student"), controller.GetResumeVersion)
+		authorized.POST("/resume/drafts/:id/versions/:version/restore", middleware.AuthorizeRoles("student"), controller.RestoreResumeVersion)
+
+	}
+
+	// 🔑 Routes that also accept API keys, and the scope each needs
+	for _, route := range []struct{ method, path, scope string }{
+		{"GET", "/api/company/jobs", models.ScopeJobsRead},
+		{"GET", "/api/jobs/:id/pipeline", models.ScopeJobsRead},
+		{"POST", "/api/jobs", models.ScopeJobsWrite},
+		{"PUT", "/api/jobs/:id", models.ScopeJobsWrite},
+		{"DELETE", "/api/jobs/:id", models.ScopeJobsWrite},
+		{"GET", "/api/jobs/:id/applications", models.ScopeApplicationsRead},
+		{"GET", "/api/jobs/:id/applications/export", models.ScopeApplicationsRead},
+		{"GET", "/api/applications/:id", models.ScopeApplicationsRead},
+		{"GET", "/api/applications/:id/history", models.ScopeApplicationsRead},
+		{"GET", "/api/applications/:id/resume-url", models.ScopeApplicationsRead},
+	} {
+		middleware.AllowAPIKey(route.method, route.path, route.scope)
+	}
 
+	controller.DocumentAPI(spec)
+	if missing := spec.Undocumented(router.Routes()); len(missing) > 0 {
+		slog.Warn("openapi: routes missing from the API specification", "routes", strings.Join(missing, ", "))
 	}
 
 	return router
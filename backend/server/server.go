@@ -0,0 +1,151 @@
+// Package server runs the HTTP server with timeouts, optional TLS and a
+// graceful shutdown that lets in-flight requests finish.
+package server
+
+import (
+	"SkillBridge/config"
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// draining is set once Run starts shutting down
+var draining atomic.Bool
+
+// Draining reports whether the server is shutting down. Readiness checks use
+// it to take the instance out of rotation while in-flight requests finish.
+func Draining() bool {
+	return draining.Load()
+}
+
+// Config holds the server settings; ConfigFromEnv fills it from the environment
+type Config struct {
+	Addr              string
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	ShutdownTimeout   time.Duration // how long in-flight requests get to finish
+	// ShutdownDelay keeps serving after shutdown begins, so load balancers see
+	// readiness fail and stop routing here before connections are closed
+	ShutdownDelay time.Duration
+
+	// TLS is served when both files are set. The files are watched, so a
+	// renewed certificate is picked up without a restart.
+	TLSCertFile string
+	TLSKeyFile  string
+}
+
+// ConfigFromEnv reads PORT, HTTP_READ_HEADER_TIMEOUT, HTTP_READ_TIMEOUT,
+// HTTP_WRITE_TIMEOUT, HTTP_IDLE_TIMEOUT, SHUTDOWN_TIMEOUT, SHUTDOWN_DELAY
+// (durations such as "30s"), HTTP_MAX_HEADER_BYTES, TLS_CERT_FILE and TLS_KEY_FILE
+func ConfigFromEnv() Config {
+	return Config{
+		Addr:              ":" + config.GetEnv("PORT", "8080"),
+		ReadHeaderTimeout: durationFromEnv("HTTP_READ_HEADER_TIMEOUT", 10*time.Second),
+		ReadTimeout:       durationFromEnv("HTTP_READ_TIMEOUT", 60*time.Second),
+		WriteTimeout:      durationFromEnv("HTTP_WRITE_TIMEOUT", 120*time.Second),
+		IdleTimeout:       durationFromEnv("HTTP_IDLE_TIMEOUT", 120*time.Second),
+		MaxHeaderBytes:    intFromEnv("HTTP_MAX_HEADER_BYTES", 64<<10),
+		ShutdownTimeout:   durationFromEnv("SHUTDOWN_TIMEOUT", 30*time.Second),
+		ShutdownDelay:     durationFromEnv("SHUTDOWN_DELAY", 0),
+		TLSCertFile:       config.GetEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:        config.GetEnv("TLS_KEY_FILE", ""),
+	}
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := config.GetEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		slog.Warn("invalid duration, using default", "setting", key, "value", value, "default", fallback)
+		return fallback
+	}
+	return d
+}
+
+func intFromEnv(key string, fallback int) int {
+	value := config.GetEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		slog.Warn("invalid number, using default", "setting", key, "value", value, "default", fallback)
+		return fallback
+	}
+	return n
+}
+
+// Run serves handler until ctx is cancelled, then stops accepting connections
+// and waits up to cfg.ShutdownTimeout for in-flight requests to finish
+func Run(ctx context.Context, handler http.Handler, cfg Config) error {
+	srv := &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+
+	useTLS := cfg.TLSCertFile != "" && cfg.TLSKeyFile != ""
+	if useTLS {
+		certs, err := newCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return err
+		}
+		go certs.watch(ctx)
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: certs.GetCertificate}
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		slog.Info("server listening", "addr", cfg.Addr, "tls", useTLS)
+		var err error
+		if useTLS {
+			// The certificate comes from TLSConfig.GetCertificate
+			err = srv.ListenAndServeTLS("", "")
+		} else {
+			err = srv.ListenAndServe()
+		}
+		errs <- err
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	draining.Store(true)
+	if cfg.ShutdownDelay > 0 {
+		slog.Info("shutting down, waiting before closing connections", "delay", cfg.ShutdownDelay)
+		select {
+		case err := <-errs:
+			return err
+		case <-time.After(cfg.ShutdownDelay):
+		}
+	}
+
+	slog.Info("shutting down, draining in-flight requests", "timeout", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
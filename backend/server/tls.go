@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// certCheckInterval is how often the certificate files are checked for changes
+const certCheckInterval = time.Minute
+
+// certReloader serves the certificate in certFile and keyFile, loading it
+// again when either file changes or the process receives SIGHUP. A broken
+// renewal is logged and the previous certificate kept.
+type certReloader struct {
+	certFile, keyFile string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	modTimes [2]time.Time
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *certReloader) load() error {
+	modTimes, err := r.stat()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.cert, r.modTimes = &cert, modTimes
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *certReloader) stat() ([2]time.Time, error) {
+	var modTimes [2]time.Time
+	for i, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// watch reloads the certificate on SIGHUP or when the files change, until ctx is cancelled
+func (r *certReloader) watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	ticker := time.NewTicker(certCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		case <-ticker.C:
+			modTimes, err := r.stat()
+			r.mu.RLock()
+			unchanged := modTimes == r.modTimes
+			r.mu.RUnlock()
+			if err != nil || unchanged {
+				continue
+			}
+		}
+		if err := r.load(); err != nil {
+			slog.Error("failed to reload TLS certificate, keeping the current one", "error", err)
+			continue
+		}
+		slog.Info("reloaded TLS certificate", "cert", r.certFile)
+	}
+}
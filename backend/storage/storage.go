@@ -0,0 +1,54 @@
+// Package storage keeps uploaded files such as resumes outside the database.
+// The backend is chosen with STORAGE_DRIVER: "local" (default) writes under
+// STORAGE_LOCAL_DIR, "s3" talks to any S3-compatible service such as MinIO.
+package storage
+
+import (
+	"SkillBridge/config"
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("storage: object not found")
+
+// Store saves and retrieves objects by key. Keys use forward slashes.
+type Store interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// FromEnv builds the store configured by the environment
+func FromEnv() (Store, error) {
+	switch driver := config.GetEnv("STORAGE_DRIVER", "local"); driver {
+	case "local":
+		return NewLocalStore(config.GetEnv("STORAGE_LOCAL_DIR", "uploads"))
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  config.GetEnv("S3_ENDPOINT", "http://localhost:9000"),
+			Region:    config.GetEnv("S3_REGION", "us-east-1"),
+			Bucket:    config.GetEnv("S3_BUCKET", "skillbridge"),
+			AccessKey: config.GetEnv("S3_ACCESS_KEY", ""),
+			SecretKey: config.GetEnv("S3_SECRET_KEY", ""),
+			PathStyle: config.GetEnv("S3_PATH_STYLE", "true") == "true",
+		})
+	default:
+		return nil, errors.New("storage: unknown STORAGE_DRIVER " + driver)
+	}
+}
+
+// validKey rejects keys that could escape the store's root
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
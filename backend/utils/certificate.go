@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"SkillBridge/config"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+var (
+	signingKey     ed25519.PrivateKey
+	signingKeyErr  error
+	signingKeyOnce sync.Once
+)
+
+// certificateKey loads the Ed25519 key used to sign certificates from
+// CERTIFICATE_SIGNING_KEY, a base64 32-byte seed. Only in development does a
+// missing key fall back to a fixed one; anywhere else certificates would be forgeable.
+func certificateKey() (ed25519.PrivateKey, error) {
+	signingKeyOnce.Do(func() {
+		encoded := config.GetEnv("CERTIFICATE_SIGNING_KEY", "")
+		if encoded == "" {
+			if !config.DevMode() {
+				signingKeyErr = errors.New("CERTIFICATE_SIGNING_KEY is not set (APP_ENV=development uses a built-in key)")
+				return
+			}
+			slog.Warn("CERTIFICATE_SIGNING_KEY is not set, signing certificates with the development key")
+			seed := sha256.Sum256([]byte("skillbridge-certificates:development"))
+			signingKey = ed25519.NewKeyFromSeed(seed[:])
+			return
+		}
+		seed, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			signingKeyErr = fmt.Errorf("CERTIFICATE_SIGNING_KEY is not a base64 %d-byte seed", ed25519.SeedSize)
+			return
+		}
+		signingKey = ed25519.NewKeyFromSeed(seed)
+	})
+	return signingKey, signingKeyErr
+}
+
+// CheckCertificateKey reports why certificates cannot be signed, if they can't.
+// main calls it at startup so a missing key stops the server.
+func CheckCertificateKey() error {
+	_, err := certificateKey()
+	return err
+}
+
+// SignCredential signs the credential bytes and returns a base64url signature
+func SignCredential(credential []byte) (string, error) {
+	key, err := certificateKey()
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, credential)), nil
+}
+
+// VerifyCredential checks a signature produced by SignCredential
+func VerifyCredential(credential []byte, signature string) bool {
+	key, err := certificateKey()
+	if err != nil {
+		return false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(key.Public().(ed25519.PublicKey), credential, sig)
+}
+
+// CertificatePublicKey returns the base64 public key so third parties can
+// verify credentials offline, or "" when no signing key is configured
+func CertificatePublicKey() string {
+	key, err := certificateKey()
+	if err != nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+}
+
+// NewCertificateID returns a random, URL-safe certificate identifier
+func NewCertificateID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "SB-" + hex.EncodeToString(b)
+}
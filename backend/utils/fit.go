@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// FitJob is what a job listing asks for
+type FitJob struct {
+	Skills          []string
+	Requirements    []string
+	ExperienceYears int
+}
+
+// FitCandidate is what an applicant brings
+type FitCandidate struct {
+	Skills          []string // profile skills and skills listed on the resume
+	ProjectSkills   [][]string
+	ResumeText      string
+	ExperienceYears float64
+}
+
+// FitResult scores a candidate against a job. Component scores are 0-1; Score is 0-100.
+type FitResult struct {
+	Score            float64
+	SkillScore       float64
+	RequirementScore float64
+	ExperienceScore  float64
+	ProjectScore     float64
+	MatchedSkills    []string
+	MissingSkills    []string
+	VerifiedSkills   []string // matched skills backed by a completed SkillBridge project
+}
+
+// Component weights; components that don't apply to a job are left out of the average
+const (
+	fitSkillWeight       = 50
+	fitRequirementWeight = 20
+	fitExperienceWeight  = 15
+	fitProjectWeight     = 15
+)
+
+var fitStopWords = map[string]bool{
+	"and": true, "the": true, "with": true, "for": true, "our": true, "you": true, "your": true,
+	"are": true, "have": true, "has": true, "who": true, "will": true, "can": true, "able": true,
+	"from": true, "that": true, "this": true, "into": true, "using": true, "use": true, "etc": true,
+	"least": true, "years": true, "year": true, "experience": true, "knowledge": true, "good": true,
+	"strong": true, "understanding": true, "familiarity": true, "familiar": true, "plus": true,
+	"must": true, "should": true, "preferred": true, "required": true, "ability": true, "skills": true,
+}
+
+var fitWordPattern = regexp.MustCompile(`[a-z0-9][a-z0-9+#.]*[a-z0-9+#]|[a-z0-9]`)
+
+// normaliseSkill folds spelling differences such as "Node.js" / "nodejs" / "Node JS"
+func normaliseSkill(skill string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '.', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(skill)))
+}
+
+// mentions reports whether text contains the skill as a whole word
+func mentions(text, skill string) bool {
+	skill = strings.ToLower(strings.TrimSpace(skill))
+	if skill == "" {
+		return false
+	}
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], skill)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(skill)
+		if (start == 0 || !isWordByte(text[start-1])) && (end == len(text) || !isWordByte(text[end])) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b == '+' || b == '#'
+}
+
+// ComputeFit scores how well a candidate matches a job
+func ComputeFit(job FitJob, candidate FitCandidate) FitResult {
+	result := FitResult{MatchedSkills: []string{}, MissingSkills: []string{}, VerifiedSkills: []string{}}
+	text := strings.ToLower(candidate.ResumeText)
+
+	have := map[string]bool{}
+	for _, s := range candidate.Skills {
+		have[normaliseSkill(s)] = true
+	}
+	projectHas := map[string]bool{}
+	for _, skills := range candidate.ProjectSkills {
+		for _, s := range skills {
+			projectHas[normaliseSkill(s)] = true
+		}
+	}
+
+	var totalWeight, weighted float64
+
+	if len(job.Skills) > 0 {
+		for _, skill := range job.Skills {
+			key := normaliseSkill(skill)
+			switch {
+			case projectHas[key]:
+				result.MatchedSkills = append(result.MatchedSkills, skill)
+				result.VerifiedSkills = append(result.VerifiedSkills, skill)
+			case have[key] || mentions(text, skill):
+				result.MatchedSkills = append(result.MatchedSkills, skill)
+			default:
+				result.MissingSkills = append(result.MissingSkills, skill)
+			}
+		}
+		result.SkillScore = float64(len(result.MatchedSkills)) / float64(len(job.Skills))
+		totalWeight += fitSkillWeight
+		weighted += fitSkillWeight * result.SkillScore
+
+		// Completed projects using the job's skills; two or more earn full marks
+		relevant := 0
+		for _, skills := range candidate.ProjectSkills {
+			for _, s := range skills {
+				if containsSkill(job.Skills, s) {
+					relevant++
+					break
+				}
+			}
+		}
+		result.ProjectScore = math.Min(1, float64(relevant)/2)
+		totalWeight += fitProjectWeight
+		weighted += fitProjectWeight * result.ProjectScore
+	}
+
+	if len(job.Requirements) > 0 {
+		// A requirement counts as met when most of its keywords appear in the candidate's material
+		var corpus strings.Builder
+		corpus.WriteString(text)
+		for _, s := range candidate.Skills {
+			corpus.WriteString(" " + strings.ToLower(s))
+		}
+		for _, skills := range candidate.ProjectSkills {
+			for _, s := range skills {
+				corpus.WriteString(" " + strings.ToLower(s))
+			}
+		}
+		all := corpus.String()
+
+		var met float64
+		counted := 0
+		for _, requirement := range job.Requirements {
+			var keywords []string
+			for _, word := range fitWordPattern.FindAllString(strings.ToLower(requirement), -1) {
+				if len(word) >= 3 && !fitStopWords[word] && strings.Trim(word, "0123456789+") != "" {
+					keywords = append(keywords, word)
+				}
+			}
+			if len(keywords) == 0 {
+				continue
+			}
+			counted++
+			found := 0
+			for _, k := range keywords {
+				if mentions(all, k) {
+					found++
+				}
+			}
+			if found*2 >= len(keywords) {
+				met++
+			}
+		}
+		if counted > 0 {
+			result.RequirementScore = met / float64(counted)
+			totalWeight += fitRequirementWeight
+			weighted += fitRequirementWeight * result.RequirementScore
+		}
+	}
+
+	result.ExperienceScore = 1
+	if job.ExperienceYears > 0 {
+		result.ExperienceScore = math.Min(1, candidate.ExperienceYears/float64(job.ExperienceYears))
+	}
+	totalWeight += fitExperienceWeight
+	weighted += fitExperienceWeight * result.ExperienceScore
+
+	result.Score = math.Round(weighted/totalWeight*1000) / 10
+	result.SkillScore = roundFit(result.SkillScore)
+	result.RequirementScore = roundFit(result.RequirementScore)
+	result.ExperienceScore = roundFit(result.ExperienceScore)
+	result.ProjectScore = roundFit(result.ProjectScore)
+	return result
+}
+
+func roundFit(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func containsSkill(skills []string, skill string) bool {
+	key := normaliseSkill(skill)
+	for _, s := range skills {
+		if normaliseSkill(s) == key {
+			return true
+		}
+	}
+	return false
+}
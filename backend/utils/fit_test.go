@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComputeFit(t *testing.T) {
+	tests := []struct {
+		name      string
+		job       FitJob
+		candidate FitCandidate
+		want      FitResult
+	}{
+		{
+			name: "no requirements scores on experience alone",
+			job:  FitJob{},
+			want: FitResult{Score: 100, ExperienceScore: 1},
+		},
+		{
+			name:      "profile skills match with spelling differences",
+			job:       FitJob{Skills: []string{"Node.js", "React"}},
+			candidate: FitCandidate{Skills: []string{"nodejs", "react"}},
+			want: FitResult{
+				Score:           81.3, // (50*1 + 15*0 + 15*1) / 80
+				SkillScore:      1,
+				ExperienceScore: 1,
+				MatchedSkills:   []string{"Node.js", "React"},
+			},
+		},
+		{
+			name:      "skills mentioned in the resume count as whole words only",
+			job:       FitJob{Skills: []string{"Go", "Java"}},
+			candidate: FitCandidate{ResumeText: "Built services in Go and JavaScript"},
+			want: FitResult{
+				Score:           50, // (50*0.5 + 15*1) / 80
+				SkillScore:      0.5,
+				ExperienceScore: 1,
+				MatchedSkills:   []string{"Go"},
+				MissingSkills:   []string{"Java"},
+			},
+		},
+		{
+			name: "skills from completed projects are verified",
+			job:  FitJob{Skills: []string{"Python", "SQL"}},
+			candidate: FitCandidate{
+				Skills:        []string{"SQL"},
+				ProjectSkills: [][]string{{"python"}, {"Python", "Django"}},
+			},
+			want: FitResult{
+				Score:           100,
+				SkillScore:      1,
+				ExperienceScore: 1,
+				ProjectScore:    1,
+				MatchedSkills:   []string{"Python", "SQL"},
+				VerifiedSkills:  []string{"Python"},
+			},
+		},
+		{
+			name: "requirements are met when most keywords appear",
+			job: FitJob{Requirements: []string{
+				"Experience with Docker and Kubernetes",
+				"Strong knowledge of PostgreSQL",
+				"Must have 3+ years", // no keywords left, so not counted
+			}},
+			candidate: FitCandidate{ResumeText: "Deployed apps with docker"},
+			want: FitResult{
+				Score:            71.4, // (20*0.5 + 15*1) / 35
+				RequirementScore: 0.5,
+				ExperienceScore:  1,
+			},
+		},
+		{
+			name:      "experience is scored against the years asked for",
+			job:       FitJob{ExperienceYears: 4},
+			candidate: FitCandidate{ExperienceYears: 1},
+			want:      FitResult{Score: 25, ExperienceScore: 0.25},
+		},
+		{
+			name:      "more experience than asked for is capped",
+			job:       FitJob{ExperienceYears: 2},
+			candidate: FitCandidate{ExperienceYears: 10},
+			want:      FitResult{Score: 100, ExperienceScore: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, list := range []*[]string{&tt.want.MatchedSkills, &tt.want.MissingSkills, &tt.want.VerifiedSkills} {
+				if *list == nil {
+					*list = []string{}
+				}
+			}
+			got := ComputeFit(tt.job, tt.candidate)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ComputeFit() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
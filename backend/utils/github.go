@@ -1,14 +1,22 @@
 package utils
 
 import (
+	"SkillBridge/metrics"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 )
 
+// githubTransport counts and times every call to the GitHub API
+var githubTransport = metrics.Transport("github", nil)
+
 // GitHubService handles GitHub API operations
 type GitHubService struct {
 	Token string
@@ -73,7 +81,7 @@ func (gs *GitHubService) CreateRepository(name, description string, private bool
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 
-	client := &http.Client{}
+	client := &http.Client{Transport: githubTransport}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
@@ -123,7 +131,7 @@ func (gs *GitHubService) AddCollaborator(owner, repo, username string, permissio
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 
-	client := &http.Client{}
+	client := &http.Client{Transport: githubTransport}
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to make request: %w", err)
@@ -149,7 +157,7 @@ func (gs *GitHubService) GetUserInfo() (map[string]interface{}, error) {
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 
-	client := &http.Client{}
+	client := &http.Client{Transport: githubTransport}
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
@@ -223,3 +231,80 @@ func CreateProjectRepository(projectTitle, studentName, companyName string, gith
 
 	return repo, nil
 }
+
+// GitHubUserStats holds the public profile numbers shown on student portfolios
+type GitHubUserStats struct {
+	Login       string `json:"login"`
+	PublicRepos int    `json:"public_repos"`
+	Followers   int    `json:"followers"`
+	Following   int    `json:"following"`
+	HTMLURL     string `json:"html_url"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type cachedGitHubStats struct {
+	stats     *GitHubUserStats
+	fetchedAt time.Time
+}
+
+var (
+	githubStatsCache   = map[string]cachedGitHubStats{}
+	githubStatsCacheMu sync.Mutex
+)
+
+// githubStatsTTL keeps unauthenticated calls well under GitHub's hourly rate limit
+const githubStatsTTL = time.Hour
+
+// githubUsernamePattern matches GitHub logins: up to 39 letters, digits and
+// single inner hyphens
+var githubUsernamePattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,37}[A-Za-z0-9])?$`)
+
+// GetPublicUserStats fetches public GitHub profile stats without a token, cached per username
+func GetPublicUserStats(username string) (*GitHubUserStats, error) {
+	if username == "" {
+		return nil, fmt.Errorf("GitHub username is required")
+	}
+	if !githubUsernamePattern.MatchString(username) || strings.Contains(username, "--") {
+		return nil, fmt.Errorf("invalid GitHub username %q", username)
+	}
+
+	githubStatsCacheMu.Lock()
+	cached, ok := githubStatsCache[strings.ToLower(username)]
+	githubStatsCacheMu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < githubStatsTTL {
+		return cached.stats, nil
+	}
+
+	req, err := http.NewRequest("GET", "https://api.github.com/users/"+url.PathEscape(username), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	client := &http.Client{Timeout: 5 * time.Second, Transport: githubTransport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var stats GitHubUserStats
+	if err := json.Unmarshal(body, &stats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	githubStatsCacheMu.Lock()
+	githubStatsCache[strings.ToLower(username)] = cachedGitHubStats{stats: &stats, fetchedAt: time.Now()}
+	githubStatsCacheMu.Unlock()
+
+	return &stats, nil
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // IANA zones for interview slots, even on hosts without zoneinfo
+)
+
+// ICSEvent is a calendar event rendered into an iCalendar (RFC 5545) document
+type ICSEvent struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Cancelled   bool
+	Updated     time.Time
+}
+
+// BuildICS renders events as a VCALENDAR. Method is PUBLISH for feeds and
+// downloads, or REQUEST/CANCEL for invitations.
+func BuildICS(name, method string, events []ICSEvent) []byte {
+	var b strings.Builder
+	line := func(text string) {
+		// A stray CR or LF would end the property and start one of the caller's choosing
+		text = strings.NewReplacer("\r", "", "\n", "").Replace(text)
+		b.WriteString(foldICSLine(text))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//SkillBridge//Interviews//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:" + method)
+	if name != "" {
+		line("X-WR-CALNAME:" + escapeICSText(name))
+	}
+	for _, event := range events {
+		updated := event.Updated
+		if updated.IsZero() {
+			updated = time.Now()
+		}
+		line("BEGIN:VEVENT")
+		line("UID:" + event.UID)
+		line(fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		line("DTSTAMP:" + icsTime(updated))
+		line("DTSTART:" + icsTime(event.Start))
+		line("DTEND:" + icsTime(event.End))
+		line("SUMMARY:" + escapeICSText(event.Summary))
+		if event.Description != "" {
+			line("DESCRIPTION:" + escapeICSText(event.Description))
+		}
+		if event.Location != "" {
+			line("LOCATION:" + escapeICSText(event.Location))
+		}
+		if event.URL != "" {
+			line("URL:" + event.URL)
+		}
+		if event.Cancelled {
+			line("STATUS:CANCELLED")
+		} else {
+			line("STATUS:CONFIRMED")
+			line("BEGIN:VALARM")
+			line("ACTION:DISPLAY")
+			line("DESCRIPTION:" + escapeICSText(event.Summary))
+			line("TRIGGER:-PT30M")
+			line("END:VALARM")
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// NewICSUID returns a globally unique event identifier
+func NewICSUID() string {
+	return RandomToken(16) + "@skillbridge"
+}
+
+// RandomToken returns n random bytes hex encoded
+func RandomToken(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func escapeICSText(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(text)
+}
+
+// foldICSLine splits lines longer than 75 octets as required by RFC 5545,
+// without breaking multi-byte characters
+func foldICSLine(text string) string {
+	if len(text) <= 75 {
+		return text
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range text {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
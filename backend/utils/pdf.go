@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// PDFDocument is a minimal PDF writer for generated documents such as
+// certificates and resumes. It supports A4 pages with Helvetica text,
+// lines and filled rectangles, which is all the built-in layouts need.
+type PDFDocument struct {
+	pages []*bytes.Buffer
+}
+
+const (
+	PDFPageWidth  = 595.28 // A4 in points
+	PDFPageHeight = 841.89
+)
+
+// NewPDFDocument creates an empty document; call AddPage before drawing
+func NewPDFDocument() *PDFDocument {
+	return &PDFDocument{}
+}
+
+// AddPage starts a new page; subsequent drawing goes to it
+func (d *PDFDocument) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+func (d *PDFDocument) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text draws a single line of text with its baseline at (x, y), measured from the bottom-left corner
+func (d *PDFDocument) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.current(), "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfEscape(text))
+}
+
+// CenteredText draws text horizontally centred on the page
+func (d *PDFDocument) CenteredText(y, size float64, bold bool, text string) {
+	x := (PDFPageWidth - PDFTextWidth(text, size)) / 2
+	d.Text(x, y, size, bold, text)
+}
+
+// Line draws a straight line of the given width
+func (d *PDFDocument) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current(), "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, y1, x2, y2)
+}
+
+// Rect draws a rectangle outline, or a filled one with the given grey level (0 black, 1 white)
+func (d *PDFDocument) Rect(x, y, w, h, width float64, fillGrey float64, fill bool) {
+	if fill {
+		fmt.Fprintf(d.current(), "%.2f g %.2f %.2f %.2f %.2f re f 0 g\n", fillGrey, x, y, w, h)
+		return
+	}
+	fmt.Fprintf(d.current(), "%.2f w %.2f %.2f %.2f %.2f re S\n", width, x, y, w, h)
+}
+
+// Bytes serialises the document
+func (d *PDFDocument) Bytes() []byte {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	writeObj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// Object layout: 1 catalog, 2 page tree, 3-4 fonts, then a page and content stream per page
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	writeObj("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		writeObj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PDFPageWidth, PDFPageHeight, 6+i*2))
+		writeObj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// PDFTextWidth approximates the rendered width of Helvetica text in points
+func PDFTextWidth(text string, size float64) float64 {
+	var units float64
+	for _, r := range text {
+		switch {
+		case r == ' ' || r == 'i' || r == 'l' || r == 'j' || r == '.' || r == ',' || r == '\'':
+			units += 278
+		case r == 'm' || r == 'w' || r == 'M' || r == 'W':
+			units += 833
+		case r >= 'A' && r <= 'Z':
+			units += 667
+		default:
+			units += 556
+		}
+	}
+	return units * size / 1000
+}
+
+// PDFWrapText splits text into lines that fit within maxWidth at the given font size
+func PDFWrapText(text string, size, maxWidth float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := words[0]
+		for _, word := range words[1:] {
+			if PDFTextWidth(line+" "+word, size) > maxWidth {
+				lines = append(lines, line)
+				line = word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pdfEscape escapes a string for a PDF literal and drops characters outside Latin-1
+func pdfEscape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r == '•':
+			b.WriteByte(0x95)
+		case r == '–' || r == '—':
+			b.WriteByte('-')
+		case r < 32:
+		case r < 128:
+			b.WriteRune(r)
+		case r < 256:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
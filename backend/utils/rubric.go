@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"SkillBridge/models"
+	"math"
+)
+
+// ComputeRubricScore turns per-criterion scores into a weighted percentage (0-100).
+// Criteria without a score count as zero so skipped criteria lower the result.
+func ComputeRubricScore(criteria []models.RubricCriterion, scores map[uint]int) float64 {
+	var totalWeight, weighted float64
+	for _, criterion := range criteria {
+		if criterion.MaxScore <= 0 || criterion.Weight <= 0 {
+			continue
+		}
+		totalWeight += criterion.Weight
+		weighted += criterion.Weight * float64(scores[criterion.ID]) / float64(criterion.MaxScore)
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+	return math.Round(weighted/totalWeight*10000) / 100
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"SkillBridge/config"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// fileURLKey signs download links; see config.Secret
+func fileURLKey() ([]byte, error) {
+	secret, err := config.Secret("FILE_URL_SECRET", "skillbridge-files:development")
+	return []byte(secret), err
+}
+
+// CheckFileURLKey reports why download links cannot be signed, if they can't.
+// main calls it at startup so a missing secret stops the server.
+func CheckFileURLKey() error {
+	_, err := fileURLKey()
+	return err
+}
+
+func fileSignature(path string, expires int64) (string, error) {
+	key, err := fileURLKey()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s\n%d", path, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// SignedFileURL appends an expiry and signature to path so it can be fetched
+// without logging in until the link expires
+func SignedFileURL(path string, ttl time.Duration) (string, time.Time, error) {
+	expires := time.Now().Add(ttl).Truncate(time.Second)
+	signature, err := fileSignature(path, expires.Unix())
+	if err != nil {
+		return "", expires, err
+	}
+	return fmt.Sprintf("%s?expires=%d&signature=%s", path, expires.Unix(), signature), expires, nil
+}
+
+// VerifyFileURL checks a signature produced by SignedFileURL and that it has not expired
+func VerifyFileURL(path, expires, signature string) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return false
+	}
+	expected, err := fileSignature(path, exp)
+	return err == nil && hmac.Equal([]byte(signature), []byte(expected))
+}
@@ -4,7 +4,8 @@ import (
 	"SkillBridge/models"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
-	"log"
+	"strconv"
+	"strings"
 )
 
 func HashPassword(password string) (string, error) {
@@ -16,15 +17,7 @@ func HashPassword(password string) (string, error) {
 }
 
 func CheckPassword(storedHash, plainPassword string) bool {
-	log.Printf("storedHash: %s", storedHash)
-	log.Printf("plainPassword: %s", plainPassword)
-	err := bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(plainPassword))
-	if err != nil {
-		log.Printf("bcrypt comparison error: %v", err)
-	} else {
-		log.Printf("bcrypt comparison succeeded")
-	}
-	return err == nil
+	return bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(plainPassword)) == nil
 }
 
 func CreateNotification(db *gorm.DB, userID uint, message string) error {
@@ -35,3 +28,25 @@ func CreateNotification(db *gorm.DB, userID uint, message string) error {
 	}
 	return db.Create(&notification).Error
 }
+
+// ParseSkills splits a comma-separated skills string into trimmed, de-duplicated entries
+func ParseSkills(skills string) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, s := range strings.Split(skills, ",") {
+		trimmed := strings.TrimSpace(s)
+		key := strings.ToLower(trimmed)
+		if trimmed == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, trimmed)
+	}
+	return result
+}
+
+// ParseID parses a numeric path parameter into a database ID
+func ParseID(value string) (uint, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+	return uint(id), err
+}
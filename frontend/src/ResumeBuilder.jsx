@@ -61,7 +61,7 @@ function ResumeBuilder() {
             const data = await resumeAPI.generateResume(token, payload);
             const url = data?.data?.file_url || data?.data?.url || data?.url || data?.resumeUrl || null;
             if (url) {
-                setResumeUrl(url);
+                setResumeUrl(utils.apiURL(url));
             } else {
                 // Show full response for debugging
                 setError('Resume generated but no download link found. Raw: ' + JSON.stringify(data));
@@ -293,6 +293,11 @@ export const utils = {
   logout: () => {
     localStorage.removeItem('token');
     localStorage.removeItem('user');
+  },
+
+  // Resolve a link returned by the API, such as a signed file path, against the API host
+  apiURL: (path) => {
+    return new URL(path, API_BASE_URL).href;
   }
 };
 